@@ -0,0 +1,227 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+// Package cache persists dependency data to disk so that templates can be
+// rendered from the last known data when Consul or Nomad is unreachable at
+// startup, and Vault leases so that secrets can be reused across restarts.
+package cache
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/consul-template/version"
+	"github.com/mitchellh/hashstructure"
+	"github.com/pkg/errors"
+)
+
+// FilePerms are the permissions of the cache file.
+const FilePerms = 0o600
+
+// cacheData is the GOB encoded contents of the cache file. Each value is
+// encoded on its own so that data of a type gob cannot encode only drops that
+// dependency from the cache.
+type cacheData struct {
+	// Version is the version of Consul Template which wrote the cache. Data
+	// written by a different version is ignored, since the types may differ.
+	Version string
+
+	// Data maps the string form of a dependency to its GOB encoded data.
+	Data map[string][]byte
+}
+
+// Cache reads and writes dependency data at a path on disk.
+type Cache struct {
+	path string
+
+	// aead seals the cache file, if encryption is enabled.
+	aead cipher.AEAD
+
+	// lastHash is the hash of the data that was last written, to avoid
+	// rewriting the file when nothing changed.
+	lastHash uint64
+	saved    bool
+}
+
+// New creates a cache at the given path. If keyFile is not empty, the cache
+// file is encrypted with AES-GCM using the base64 encoded 16, 24 or 32 byte
+// key in that file.
+func New(path, keyFile string) (*Cache, error) {
+	if path == "" {
+		return nil, errors.New("cache: missing path")
+	}
+
+	c := &Cache{path: path}
+	if keyFile != "" {
+		aead, err := LoadKey(keyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "cache")
+		}
+		c.aead = aead
+	}
+	return c, nil
+}
+
+// LoadKey reads the base64 encoded AES key in the given file and returns an
+// AES-GCM cipher for it.
+func LoadKey(path string) (cipher.AEAD, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(b)))
+	if err != nil {
+		return nil, fmt.Errorf("decoding key %s: %w", path, err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %w", path, err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// Seal encrypts the plaintext with the cipher, prefixing it with a random
+// nonce.
+func Seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Open decrypts ciphertext that was encrypted by Seal.
+func Open(aead cipher.AEAD, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
+
+// Load returns the cached data, keyed by the string form of the dependency.
+// A cache file that does not exist yet or that was written by a different
+// version is empty.
+func (c *Cache) Load() (map[string]interface{}, error) {
+	b, err := os.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return map[string]interface{}{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "cache")
+	}
+
+	if c.aead != nil {
+		if b, err = Open(c.aead, b); err != nil {
+			return nil, errors.Wrapf(err, "cache: decrypting %s", c.path)
+		}
+	}
+
+	var cd cacheData
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&cd); err != nil {
+		return nil, errors.Wrapf(err, "cache: decoding %s", c.path)
+	}
+	if cd.Version != version.Version {
+		log.Printf("[WARN] (cache) ignoring %s created with different version (%s vs %s)",
+			c.path, cd.Version, version.Version)
+		return map[string]interface{}{}, nil
+	}
+
+	data := make(map[string]interface{}, len(cd.Data))
+	for key, raw := range cd.Data {
+		var value interface{}
+		if err := gob.NewDecoder(bytes.NewReader(raw)).Decode(&value); err != nil {
+			log.Printf("[WARN] (cache) failed to decode %s: %v", key, err)
+			continue
+		}
+		data[key] = value
+	}
+	return data, nil
+}
+
+// Save replaces the cached data with the given data, keyed by the string form
+// of the dependency. The file is only written if the data changed since the
+// last call.
+func (c *Cache) Save(data map[string]interface{}) error {
+	// Hash the data rather than the encoded value since gob encoding does not
+	// guarantee stable ordering for maps.
+	hash, err := hashstructure.Hash(data, nil)
+	if err != nil {
+		return fmt.Errorf("cache: calculating hash failed: %v", err)
+	}
+	if c.saved && hash == c.lastHash {
+		return nil
+	}
+
+	cd := cacheData{
+		Version: version.Version,
+		Data:    make(map[string][]byte, len(data)),
+	}
+	for key, value := range data {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(&value); err != nil {
+			log.Printf("[DEBUG] (cache) not caching %s: %v", key, err)
+			continue
+		}
+		cd.Data[key] = buf.Bytes()
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&cd); err != nil {
+		return errors.Wrap(err, "cache: encode failed")
+	}
+	b := buf.Bytes()
+	if c.aead != nil {
+		if b, err = Seal(c.aead, b); err != nil {
+			return errors.Wrap(err, "cache: encrypting")
+		}
+	}
+
+	if err := writeFile(c.path, b); err != nil {
+		return errors.Wrap(err, "cache")
+	}
+	log.Printf("[DEBUG] (cache) saved %d dependencies to %s", len(cd.Data), c.path)
+	c.lastHash, c.saved = hash, true
+	return nil
+}
+
+// writeFile atomically replaces the file at path, creating its parent
+// directory if needed.
+func writeFile(path string, b []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), FilePerms); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
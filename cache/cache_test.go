@@ -0,0 +1,130 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cache
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	dep "github.com/hashicorp/consul-template/dependency"
+)
+
+func testKeyFile(t *testing.T) string {
+	t.Helper()
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "cache.key")
+	if err := os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCache_SaveLoad(t *testing.T) {
+	data := map[string]interface{}{
+		"kv.block(foo)": "bar",
+		"catalog.services": []*dep.CatalogSnippet{
+			{Name: "web", Tags: dep.ServiceTags{"a"}},
+		},
+	}
+
+	cases := []struct {
+		name    string
+		keyFile string
+	}{
+		{
+			"plain",
+			"",
+		},
+		{
+			"encrypted",
+			testKeyFile(t),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "sub", "cache")
+
+			c, err := New(path, tc.keyFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := c.Save(data); err != nil {
+				t.Fatal(err)
+			}
+
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != FilePerms {
+				t.Errorf("expected perms %o, got %o", FilePerms, info.Mode().Perm())
+			}
+
+			c, err = New(path, tc.keyFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			act, err := c.Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(data, act) {
+				t.Errorf("\nexp: %#v\nact: %#v", data, act)
+			}
+		})
+	}
+}
+
+func TestCache_Load_missing(t *testing.T) {
+	c, err := New(filepath.Join(t.TempDir(), "cache"), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := c.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 0 {
+		t.Errorf("expected empty cache, got %#v", data)
+	}
+}
+
+func TestCache_Load_wrongKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache")
+
+	c, err := New(path, testKeyFile(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Save(map[string]interface{}{"kv.block(foo)": "bar"}); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err = New(path, testKeyFile(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Load(); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestNew_badKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.key")
+	if err := os.WriteFile(path, []byte("not a key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(filepath.Join(t.TempDir(), "cache"), path); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cache
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/json"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/pkg/errors"
+)
+
+// LeaseStore persists Vault secrets and their leases to disk, encrypted, so
+// that the leases can be renewed and the secrets reused after a restart
+// instead of creating new secrets.
+type LeaseStore struct {
+	sync.Mutex
+
+	path string
+	aead cipher.AEAD
+
+	// secrets are the stored secrets by dependency.
+	secrets map[string]*api.Secret
+}
+
+// NewLeaseStore creates a lease store at the given path, encrypted with
+// AES-GCM using the base64 encoded 16, 24 or 32 byte key in keyFile. Secrets
+// already stored at the path are loaded. A store that cannot be read is
+// ignored, so that it never prevents startup.
+func NewLeaseStore(path, keyFile string) (*LeaseStore, error) {
+	if path == "" {
+		return nil, errors.New("lease store: missing path")
+	}
+	if keyFile == "" {
+		return nil, errors.New("lease store: missing encryption key file")
+	}
+
+	aead, err := LoadKey(keyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "lease store")
+	}
+
+	s := &LeaseStore{
+		path:    path,
+		aead:    aead,
+		secrets: make(map[string]*api.Secret),
+	}
+	if err := s.load(); err != nil {
+		log.Printf("[WARN] (lease store) not using stored leases: %v", err)
+	}
+	return s, nil
+}
+
+// load reads the secrets stored on disk.
+func (s *LeaseStore) load() error {
+	b, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if b, err = Open(s.aead, b); err != nil {
+		return errors.Wrapf(err, "decrypting %s", s.path)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return errors.Wrapf(err, "decoding %s", s.path)
+	}
+
+	// Parse the secrets like Vault responses, so numbers in the data are
+	// json.Number as they were when the secret was read.
+	for key, r := range raw {
+		secret, err := api.ParseSecret(bytes.NewReader(r))
+		if err != nil || secret == nil {
+			log.Printf("[WARN] (lease store) failed to decode %s: %v", key, err)
+			continue
+		}
+		s.secrets[key] = secret
+	}
+	log.Printf("[DEBUG] (lease store) loaded %d leases from %s", len(s.secrets), s.path)
+	return nil
+}
+
+// Get returns the stored secret of the given dependency, or nil if there is
+// none.
+func (s *LeaseStore) Get(key string) *api.Secret {
+	s.Lock()
+	defer s.Unlock()
+	return s.secrets[key]
+}
+
+// Put stores the secret of the given dependency and writes the store to disk.
+func (s *LeaseStore) Put(key string, secret *api.Secret) error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.secrets[key] = secret
+	return s.save()
+}
+
+// Delete removes the secret of the given dependency and writes the store to
+// disk.
+func (s *LeaseStore) Delete(key string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.secrets[key]; !ok {
+		return nil
+	}
+	delete(s.secrets, key)
+	return s.save()
+}
+
+// save writes the secrets to disk. It must be called with the lock held.
+func (s *LeaseStore) save() error {
+	b, err := json.Marshal(s.secrets)
+	if err != nil {
+		return errors.Wrap(err, "lease store: encode failed")
+	}
+	if b, err = Seal(s.aead, b); err != nil {
+		return errors.Wrap(err, "lease store: encrypting")
+	}
+	if err := writeFile(s.path, b); err != nil {
+		return errors.Wrap(err, "lease store")
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+)
+
+func TestLeaseStore_PutGet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "leases")
+	keyFile := testKeyFile(t)
+
+	s, err := NewLeaseStore(path, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret := &api.Secret{
+		LeaseID:       "database/creds/app/abcd",
+		LeaseDuration: 3600,
+		Renewable:     true,
+		Data: map[string]interface{}{
+			"username": "v-app-1234",
+			"password": "hunter2",
+			"ttl":      json.Number("3600"),
+		},
+	}
+	if err := s.Put("vault.read(database/creds/app)", secret); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != FilePerms {
+		t.Errorf("expected perms %o, got %o", FilePerms, info.Mode().Perm())
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if json.Valid(b) {
+		t.Error("expected the lease store to be encrypted")
+	}
+
+	s, err = NewLeaseStore(path, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	act := s.Get("vault.read(database/creds/app)")
+	if !reflect.DeepEqual(secret, act) {
+		t.Errorf("\nexp: %#v\nact: %#v", secret, act)
+	}
+
+	if err := s.Delete("vault.read(database/creds/app)"); err != nil {
+		t.Fatal(err)
+	}
+	s, err = NewLeaseStore(path, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act := s.Get("vault.read(database/creds/app)"); act != nil {
+		t.Errorf("expected no secret, got %#v", act)
+	}
+}
+
+func TestLeaseStore_wrongKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "leases")
+
+	s, err := NewLeaseStore(path, testKeyFile(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put("vault.read(secret/foo)", &api.Secret{LeaseID: "foo"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A store that cannot be read is ignored.
+	s, err = NewLeaseStore(path, testKeyFile(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act := s.Get("vault.read(secret/foo)"); act != nil {
+		t.Errorf("expected no secret, got %#v", act)
+	}
+}
+
+func TestNewLeaseStore_missingKey(t *testing.T) {
+	if _, err := NewLeaseStore(filepath.Join(t.TempDir(), "leases"), ""); err == nil {
+		t.Fatal("expected error")
+	}
+}
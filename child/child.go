@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/hashicorp/consul-template/signals"
+	metrics "github.com/hashicorp/go-metrics"
 )
 
 var (
@@ -32,6 +33,26 @@ var (
 	ExitCodeError = 127
 )
 
+// ExitError is returned when a command started with a timeout exits with a
+// non-zero exit status before the timeout is reached.
+type ExitError struct {
+	// Command is the human-formatted command with arguments.
+	Command string
+
+	// ExitCode is the exit status of the command.
+	ExitCode int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf(
+		"command exited with a non-zero exit status:\n"+
+			"\n    %s\n\n"+
+			"Please ensure the command exits with a zero (0) "+
+			"exit status to indicate success",
+		e.Command,
+	)
+}
+
 // Child is a wrapper around a child process which can be used to send signals
 // and manage the processes' lifecycle.
 type Child struct {
@@ -226,6 +247,7 @@ func (c *Child) Signal(s os.Signal) error {
 func (c *Child) Reload() error {
 	if c.reloadSignal == nil {
 		c.logger.Printf("[INFO] (child) restarting process")
+		metrics.IncrCounter([]string{"child", "restart"}, 1)
 
 		// Take a full lock because start is going to replace the process. We also
 		// want to make sure that no other routines attempt to send reload signals
@@ -343,13 +365,7 @@ func (c *Child) start() error {
 		select {
 		case code := <-exitCh:
 			if code != 0 {
-				return fmt.Errorf(
-					"command exited with a non-zero exit status:\n"+
-						"\n    %s\n\n"+
-						"Please ensure the command exits with a zero (0) "+
-						"exit status to indicate success",
-					c.Command(),
-				)
+				return &ExitError{Command: c.Command(), ExitCode: code}
 			}
 		case <-time.After(c.timeout):
 			// Force-kill the process
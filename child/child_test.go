@@ -5,6 +5,7 @@ package child
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"log"
 	"os"
@@ -205,6 +206,24 @@ func TestStart(t *testing.T) {
 	}
 }
 
+func TestStart_exitError(t *testing.T) {
+	c := testChild(t)
+	c.command = "sh"
+	c.args = []string{"-c", "exit 3"}
+	c.timeout = 5 * time.Second
+
+	err := c.Start()
+	defer c.Stop()
+
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("expected an ExitError, got %#v", err)
+	}
+	if exitErr.ExitCode != 3 {
+		t.Errorf("expected exit code 3, got %d", exitErr.ExitCode)
+	}
+}
+
 func TestSignal(t *testing.T) {
 	c := testChild(t)
 	c.command = "sh"
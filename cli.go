@@ -16,10 +16,15 @@ import (
 
 	"github.com/coreos/go-systemd/v22/daemon"
 	"github.com/hashicorp/consul-template/config"
+	"github.com/hashicorp/consul-template/fixture"
 	"github.com/hashicorp/consul-template/logging"
 	"github.com/hashicorp/consul-template/manager"
+	"github.com/hashicorp/consul-template/renderer"
 	"github.com/hashicorp/consul-template/service_os"
 	"github.com/hashicorp/consul-template/signals"
+	"github.com/hashicorp/consul-template/status"
+	"github.com/hashicorp/consul-template/telemetry"
+	"github.com/hashicorp/consul-template/tracing"
 	"github.com/hashicorp/consul-template/version"
 )
 
@@ -36,6 +41,7 @@ const (
 	ExitCodeParseFlagsError
 	ExitCodeRunnerError
 	ExitCodeConfigError
+	ExitCodeTestFailure
 )
 
 // CLI is the main entry point.
@@ -52,6 +58,18 @@ type CLI struct {
 	// stopCh is an internal channel used to trigger a shutdown of the CLI.
 	stopCh  chan struct{}
 	stopped bool
+
+	// telemetry is the running metrics listener, if enabled. It is replaced
+	// on each configuration reload.
+	telemetry *telemetry.Telemetry
+
+	// tracing is the running trace pipeline, if enabled. It is replaced on
+	// each configuration reload.
+	tracing *tracing.Tracing
+
+	// status is the running status API listener, if enabled. It is replaced
+	// whenever a new runner is created.
+	status *status.Server
 }
 
 // NewCLI creates a new CLI object with the given stdout and stderr streams.
@@ -94,6 +112,8 @@ func (cli *CLI) Run(args []string) int {
 	if err != nil {
 		return logError(err, ExitCodeConfigError)
 	}
+	defer cli.stopTelemetry()
+	defer cli.stopTracing()
 
 	// Print version information for debugging
 	log.Printf("[INFO] %s", version.HumanVersion)
@@ -107,6 +127,10 @@ func (cli *CLI) Run(args []string) int {
 		return ExitCodeOK
 	}
 
+	if config.Test {
+		return cli.runTests(config)
+	}
+
 	// Create a context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -138,6 +162,11 @@ func (cli *CLI) Run(args []string) int {
 		return logError(err, ExitCodeRunnerError)
 	}
 
+	if err := cli.startStatus(config, runner); err != nil {
+		return logError(err, ExitCodeConfigError)
+	}
+	defer cli.stopStatus()
+
 	runner.SetReadyChannel(readyCh)
 	go runner.Start()
 
@@ -193,6 +222,9 @@ func (cli *CLI) Run(args []string) int {
 				if err != nil {
 					return logError(err, ExitCodeRunnerError)
 				}
+				if err := cli.startStatus(config, runner); err != nil {
+					return logError(err, ExitCodeConfigError)
+				}
 				runner.SetReadyChannel(readyCh)
 				go runner.Start()
 			case *config.KillSignal:
@@ -375,6 +407,14 @@ func (cli *CLI) ParseFlags(args []string) (
 
 	flags.BoolVar(&dry, "dry", false, "")
 
+	flags.Var((funcVar)(func(s string) error {
+		if err := renderer.ValidDryFormat(s); err != nil {
+			return err
+		}
+		c.DryFormat = s
+		return nil
+	}), "dry-format", "")
+
 	flags.Var((funcVar)(func(s string) error {
 		c.Exec.Enabled = config.Bool(true)
 		c.Exec.Command = []string{s}
@@ -443,6 +483,11 @@ func (cli *CLI) ParseFlags(args []string) (
 		return nil
 	}), "log-level", "")
 
+	flags.Var((funcVar)(func(s string) error {
+		c.LogFormat = config.String(s)
+		return nil
+	}), "log-format", "")
+
 	flags.Var((funcVar)(func(s string) error {
 		c.FileLog.LogFilePath = config.String(s)
 		return nil
@@ -497,6 +542,11 @@ func (cli *CLI) ParseFlags(args []string) (
 		return nil
 	}), "retry", "")
 
+	flags.Var((funcVar)(func(s string) error {
+		c.Status.Address = config.String(s)
+		return nil
+	}), "status-address", "")
+
 	flags.Var((funcBoolVar)(func(b bool) error {
 		c.Syslog.Enabled = config.Bool(b)
 		return nil
@@ -512,6 +562,11 @@ func (cli *CLI) ParseFlags(args []string) (
 		return nil
 	}), "syslog-name", "")
 
+	flags.Var((funcVar)(func(s string) error {
+		c.Telemetry.Address = config.String(s)
+		return nil
+	}), "telemetry-address", "")
+
 	flags.Var((funcVar)(func(s string) error {
 		t, err := config.ParseTemplateConfig(s)
 		if err != nil {
@@ -526,6 +581,21 @@ func (cli *CLI) ParseFlags(args []string) (
 		return nil
 	}), "template-error-fatal", "")
 
+	flags.Var((funcBoolVar)(func(b bool) error {
+		c.Test = b
+		return nil
+	}), "test", "")
+
+	flags.Var((funcVar)(func(s string) error {
+		c.TestFixtures = s
+		return nil
+	}), "test-fixtures", "")
+
+	flags.Var((funcBoolVar)(func(b bool) error {
+		c.TestUpdate = b
+		return nil
+	}), "test-update", "")
+
 	flags.Var((funcVar)(func(s string) error {
 		c.Vault.Address = config.String(s)
 		return nil
@@ -707,6 +777,7 @@ func logError(err error, status int) int {
 func (cli *CLI) setup(conf *config.Config) (*config.Config, error) {
 	if err := logging.Setup(&logging.Config{
 		Level:             config.StringVal(conf.LogLevel),
+		Format:            config.StringVal(conf.LogFormat),
 		LogFilePath:       config.StringVal(conf.FileLog.LogFilePath),
 		LogRotateBytes:    config.IntVal(conf.FileLog.LogRotateBytes),
 		LogRotateDuration: config.TimeDurationVal(conf.FileLog.LogRotateDuration),
@@ -719,9 +790,129 @@ func (cli *CLI) setup(conf *config.Config) (*config.Config, error) {
 		return nil, err
 	}
 
+	// Restart the metrics listener so that changes to the telemetry stanza
+	// are picked up on reload.
+	cli.stopTelemetry()
+	if config.BoolVal(conf.Telemetry.Enabled) {
+		t, err := telemetry.Setup(&telemetry.Config{
+			Address: config.StringVal(conf.Telemetry.Address),
+			Prefix:  config.StringVal(conf.Telemetry.MetricsPrefix),
+		})
+		if err != nil {
+			return nil, err
+		}
+		cli.telemetry = t
+	}
+
+	// Likewise restart tracing so that changes to the tracing stanza are
+	// picked up on reload. Stopping flushes any spans from the old runner.
+	cli.stopTracing()
+	if config.BoolVal(conf.Tracing.Enabled) {
+		t, err := tracing.Setup(&tracing.Config{
+			Exporter:    config.StringVal(conf.Tracing.Exporter),
+			Endpoint:    config.StringVal(conf.Tracing.Endpoint),
+			Insecure:    config.BoolVal(conf.Tracing.Insecure),
+			FilePath:    config.StringVal(conf.Tracing.FilePath),
+			ServiceName: config.StringVal(conf.Tracing.ServiceName),
+		})
+		if err != nil {
+			return nil, err
+		}
+		cli.tracing = t
+	}
+
 	return conf, nil
 }
 
+// stopTelemetry stops the metrics listener, if one is running.
+func (cli *CLI) stopTelemetry() {
+	if cli.telemetry != nil {
+		cli.telemetry.Stop()
+		cli.telemetry = nil
+	}
+}
+
+// stopTracing flushes and stops the trace pipeline, if one is running.
+func (cli *CLI) stopTracing() {
+	if cli.tracing != nil {
+		cli.tracing.Stop()
+		cli.tracing = nil
+	}
+}
+
+// startStatus restarts the status API listener so that it reports on the given
+// runner. It is a no-op if the status API is not enabled.
+func (cli *CLI) startStatus(conf *config.Config, runner *manager.Runner) error {
+	cli.stopStatus()
+	if !config.BoolVal(conf.Status.Enabled) {
+		return nil
+	}
+
+	s, err := status.Setup(&status.Config{
+		Address: config.StringVal(conf.Status.Address),
+		Source:  runner,
+	})
+	if err != nil {
+		return err
+	}
+	cli.status = s
+	return nil
+}
+
+// stopStatus stops the status API listener, if one is running.
+func (cli *CLI) stopStatus() {
+	if cli.status != nil {
+		cli.status.Stop()
+		cli.status = nil
+	}
+}
+
+// runTests renders every template against the configured fixtures, prints the
+// results and returns ExitCodeTestFailure if any template did not pass.
+func (cli *CLI) runTests(conf *config.Config) int {
+	fixtures := make(fixture.Fixtures)
+	if conf.TestFixtures != "" {
+		var err error
+		fixtures, err = fixture.Load(conf.TestFixtures)
+		if err != nil {
+			return logError(err, ExitCodeConfigError)
+		}
+	}
+
+	results, err := fixture.Run(conf, fixtures, conf.TestUpdate)
+	if err != nil {
+		return logError(err, ExitCodeConfigError)
+	}
+
+	var failed int
+	for _, r := range results {
+		switch {
+		case !r.Passed():
+			failed++
+			fmt.Fprintf(cli.outStream, "FAIL   %s => %s\n", r.Source, r.Golden)
+		case r.Updated:
+			fmt.Fprintf(cli.outStream, "UPDATE %s => %s\n", r.Source, r.Golden)
+		default:
+			fmt.Fprintf(cli.outStream, "PASS   %s => %s\n", r.Source, r.Golden)
+		}
+		for _, d := range r.Missing {
+			fmt.Fprintf(cli.outStream, "  missing fixture: %s\n", d)
+		}
+		if r.Err != nil {
+			fmt.Fprintf(cli.outStream, "  error: %s\n", r.Err)
+		}
+		if r.Diff != "" {
+			fmt.Fprint(cli.outStream, r.Diff)
+		}
+	}
+
+	fmt.Fprintf(cli.outStream, "%d passed, %d failed\n", len(results)-failed, failed)
+	if failed > 0 {
+		return ExitCodeTestFailure
+	}
+	return ExitCodeOK
+}
+
 const usage = `Usage: %s [options]
 
   Watches a series of templates on the file system, writing new changes when
@@ -810,7 +1001,13 @@ Options:
       The default right delimiter for templating
 
   -dry
-      Print generated templates to stdout instead of rendering
+      Print what rendering the templates would change to stdout instead of
+      rendering
+
+  -dry-format=<format>
+      Set the dry mode output format - values are "diff" (a unified diff
+      against the existing destination), "json" and "contents" (the full
+      rendered contents)
 
   -exec=<command>
       Enable exec mode to run as a supervisor-like process - the given command
@@ -856,6 +1053,9 @@ Options:
   -log-level=<level>
       Set the logging level - values are "debug", "info", "warn", and "err"
 
+  -log-format=<format>
+      Set the log output format - values are "text" and "json"
+
   -max-stale=<duration>
       Set the maximum staleness and allow stale queries to Consul which will
       distribute work among all servers instead of just the leader
@@ -876,6 +1076,9 @@ Options:
       The amount of time to wait if Consul returns an error when communicating
       with the API
 
+  -status-address=<address>
+      Serve the read-only status API on the given host:port
+
   -syslog
       Send the output to syslog instead of standard error and standard out. The
       syslog facility defaults to LOCAL0 and can be changed using a
@@ -889,6 +1092,9 @@ Options:
       Set the name of the application which will appear in syslog, if this
       attribute is supplied, the -syslog flag must also be supplied
 
+  -telemetry-address=<address>
+      Serve Prometheus metrics on the given host:port at the /metrics path
+
   -template=<template>
       Adds a new template to watch on disk in the format 'in:out(:command)'
 
@@ -896,6 +1102,18 @@ Options:
       Control whether template errors cause consul-template to immediately exit.
       This overrides the per-template setting.
 
+  -test
+      Render each template once using fixture data instead of Consul, Vault
+      and Nomad, compare the output to the template destination and exit
+
+  -test-fixtures=<path>
+      Path to a JSON or YAML file mapping dependencies to fixture data for
+      -test mode
+
+  -test-update
+      Overwrite the template destinations with the rendered output in -test
+      mode instead of comparing them
+
   -vault-addr=<address>
       Sets the address of the Vault server
 
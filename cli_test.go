@@ -300,6 +300,20 @@ func TestCLI_ParseFlags(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"dry-format",
+			[]string{"-dry-format", "json"},
+			&config.Config{
+				DryFormat: "json",
+			},
+			false,
+		},
+		{
+			"dry-format-invalid",
+			[]string{"-dry-format", "xml"},
+			nil,
+			true,
+		},
 		{
 			"exec",
 			[]string{"-exec", "command"},
@@ -469,6 +483,14 @@ func TestCLI_ParseFlags(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"log-format",
+			[]string{"-log-format", "json"},
+			&config.Config{
+				LogFormat: config.String("json"),
+			},
+			false,
+		},
 		{
 			"log-file",
 			[]string{"-log-file", "something.log"},
@@ -553,6 +575,16 @@ func TestCLI_ParseFlags(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"status-address",
+			[]string{"-status-address", "127.0.0.1:9999"},
+			&config.Config{
+				Status: &config.StatusConfig{
+					Address: config.String("127.0.0.1:9999"),
+				},
+			},
+			false,
+		},
 		{
 			"syslog",
 			[]string{"-syslog"},
@@ -573,6 +605,16 @@ func TestCLI_ParseFlags(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"telemetry-address",
+			[]string{"-telemetry-address", "127.0.0.1:9999"},
+			&config.Config{
+				Telemetry: &config.TelemetryConfig{
+					Address: config.String("127.0.0.1:9999"),
+				},
+			},
+			false,
+		},
 		{
 			"template",
 			[]string{"-template", "/tmp/in.tpl"},
@@ -890,6 +932,16 @@ func TestCLI_ParseFlags(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"test",
+			[]string{"-test", "-test-fixtures", "/tmp/fixtures.json", "-test-update"},
+			&config.Config{
+				Test:         true,
+				TestFixtures: "/tmp/fixtures.json",
+				TestUpdate:   true,
+			},
+			false,
+		},
 	}
 
 	for i, tc := range cases {
@@ -975,6 +1027,46 @@ func TestCLI_Run(t *testing.T) {
 		})
 	}
 
+	t.Run("test", func(t *testing.T) {
+		dir := t.TempDir()
+		write := func(name, contents string) string {
+			path := dir + "/" + name
+			if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			return path
+		}
+		fixtures := write("fixtures.json", `{"kv.block(test-foo)": "bar"}`)
+		pass := write("pass.tpl", `{{ key "test-foo" }}`)
+		fail := write("fail.tpl", `{{ key "test-bar" }}`)
+		write("pass", "bar")
+		write("fail", "")
+
+		out := gatedio.NewByteBuffer()
+		cli := NewCLI(out, out)
+		exit := cli.Run([]string{
+			"consul-template",
+			"-test",
+			"-test-fixtures", fixtures,
+			"-template", pass + ":" + dir + "/pass",
+			"-template", fail + ":" + dir + "/fail",
+		})
+
+		if exit != ExitCodeTestFailure {
+			t.Errorf("expected %d to be %d", exit, ExitCodeTestFailure)
+		}
+		for _, exp := range []string{
+			"PASS   " + pass,
+			"FAIL   " + fail,
+			"missing fixture: kv.block(test-bar)",
+			"1 passed, 1 failed",
+		} {
+			if !strings.Contains(out.String(), exp) {
+				t.Errorf("expected %q to contain %q", out.String(), exp)
+			}
+		}
+	})
+
 	t.Run("once", func(t *testing.T) {
 		f, err := os.CreateTemp("", "")
 		if err != nil {
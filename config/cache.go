@@ -0,0 +1,107 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+)
+
+// CacheConfig is the configuration for the on-disk cache of Consul and Nomad
+// dependency data.
+type CacheConfig struct {
+	// Enabled controls whether dependency data is cached. Specifying a path
+	// also enables the cache.
+	Enabled *bool `mapstructure:"enabled"`
+
+	// Path is the file the cache is written to.
+	Path *string `mapstructure:"path"`
+
+	// EncryptionKeyFile is the path to a file holding a base64 encoded 16, 24
+	// or 32 byte AES key. If set, the cache is encrypted with AES-GCM.
+	EncryptionKeyFile *string `mapstructure:"encryption_key_file"`
+}
+
+// DefaultCacheConfig returns a configuration that is populated with the
+// default values.
+func DefaultCacheConfig() *CacheConfig {
+	return &CacheConfig{}
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *CacheConfig) Copy() *CacheConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o CacheConfig
+	o.Enabled = c.Enabled
+	o.Path = c.Path
+	o.EncryptionKeyFile = c.EncryptionKeyFile
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration, with values in the other configuration taking precedence.
+// Maps and slices are merged, most other values are overwritten. Complex
+// structs define their own merge functionality.
+func (c *CacheConfig) Merge(o *CacheConfig) *CacheConfig {
+	if c == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if o == nil {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+
+	if o.Enabled != nil {
+		r.Enabled = o.Enabled
+	}
+
+	if o.Path != nil {
+		r.Path = o.Path
+	}
+
+	if o.EncryptionKeyFile != nil {
+		r.EncryptionKeyFile = o.EncryptionKeyFile
+	}
+
+	return r
+}
+
+// Finalize ensures there no nil pointers.
+func (c *CacheConfig) Finalize() {
+	if c.Enabled == nil {
+		c.Enabled = Bool(StringPresent(c.Path))
+	}
+
+	if c.Path == nil {
+		c.Path = String("")
+	}
+
+	if c.EncryptionKeyFile == nil {
+		c.EncryptionKeyFile = String("")
+	}
+}
+
+// GoString defines the printable version of this struct.
+func (c *CacheConfig) GoString() string {
+	if c == nil {
+		return "(*CacheConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&CacheConfig{"+
+		"Enabled:%s, "+
+		"Path:%s, "+
+		"EncryptionKeyFile:%s"+
+		"}",
+		BoolGoString(c.Enabled),
+		StringGoString(c.Path),
+		StringGoString(c.EncryptionKeyFile),
+	)
+}
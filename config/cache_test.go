@@ -0,0 +1,160 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestCacheConfig_Copy(t *testing.T) {
+	cases := []struct {
+		name string
+		a    *CacheConfig
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&CacheConfig{},
+		},
+		{
+			"same_enabled",
+			&CacheConfig{
+				Enabled:           Bool(true),
+				Path:              String("/var/lib/consul-template/cache"),
+				EncryptionKeyFile: String("/etc/consul-template/cache.key"),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Copy()
+			if !reflect.DeepEqual(tc.a, r) {
+				t.Errorf("\nexp: %#v\nact: %#v", tc.a, r)
+			}
+		})
+	}
+}
+
+func TestCacheConfig_Merge(t *testing.T) {
+	cases := []struct {
+		name string
+		a    *CacheConfig
+		b    *CacheConfig
+		r    *CacheConfig
+	}{
+		{
+			"nil_a",
+			nil,
+			&CacheConfig{},
+			&CacheConfig{},
+		},
+		{
+			"nil_b",
+			&CacheConfig{},
+			nil,
+			&CacheConfig{},
+		},
+		{
+			"nil_both",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"empty",
+			&CacheConfig{},
+			&CacheConfig{},
+			&CacheConfig{},
+		},
+		{
+			"enabled_overrides",
+			&CacheConfig{Enabled: Bool(true)},
+			&CacheConfig{Enabled: Bool(false)},
+			&CacheConfig{Enabled: Bool(false)},
+		},
+		{
+			"enabled_empty_one",
+			&CacheConfig{Enabled: Bool(true)},
+			&CacheConfig{},
+			&CacheConfig{Enabled: Bool(true)},
+		},
+		{
+			"path_overrides",
+			&CacheConfig{Path: String("a")},
+			&CacheConfig{Path: String("b")},
+			&CacheConfig{Path: String("b")},
+		},
+		{
+			"path_empty_two",
+			&CacheConfig{},
+			&CacheConfig{Path: String("b")},
+			&CacheConfig{Path: String("b")},
+		},
+		{
+			"encryption_key_file_overrides",
+			&CacheConfig{EncryptionKeyFile: String("a")},
+			&CacheConfig{EncryptionKeyFile: String("b")},
+			&CacheConfig{EncryptionKeyFile: String("b")},
+		},
+		{
+			"encryption_key_file_empty_one",
+			&CacheConfig{EncryptionKeyFile: String("a")},
+			&CacheConfig{},
+			&CacheConfig{EncryptionKeyFile: String("a")},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Merge(tc.b)
+			if !reflect.DeepEqual(tc.r, r) {
+				t.Errorf("\nexp: %#v\nact: %#v", tc.r, r)
+			}
+		})
+	}
+}
+
+func TestCacheConfig_Finalize(t *testing.T) {
+	cases := []struct {
+		name string
+		i    *CacheConfig
+		r    *CacheConfig
+	}{
+		{
+			"empty",
+			&CacheConfig{},
+			&CacheConfig{
+				Enabled:           Bool(false),
+				Path:              String(""),
+				EncryptionKeyFile: String(""),
+			},
+		},
+		{
+			"with_path",
+			&CacheConfig{
+				Path: String("/tmp/cache"),
+			},
+			&CacheConfig{
+				Enabled:           Bool(true),
+				Path:              String("/tmp/cache"),
+				EncryptionKeyFile: String(""),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			tc.i.Finalize()
+			if !reflect.DeepEqual(tc.r, tc.i) {
+				t.Errorf("\nexp: %#v\nact: %#v", tc.r, tc.i)
+			}
+		})
+	}
+}
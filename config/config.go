@@ -27,6 +27,9 @@ const (
 	// DefaultLogLevel is the default logging level.
 	DefaultLogLevel = "WARN"
 
+	// DefaultLogFormat is the default log output format.
+	DefaultLogFormat = "text"
+
 	// DefaultMaxStale is the default staleness permitted. This enables stale
 	// queries by default for performance reasons.
 	DefaultMaxStale = 2 * time.Second
@@ -46,6 +49,9 @@ var homePath, _ = homedir.Dir()
 
 // Config is used to configure Consul Template
 type Config struct {
+	// Cache is the configuration for the on-disk cache of dependency data.
+	Cache *CacheConfig `mapstructure:"cache"`
+
 	// Consul is the configuration for connecting to a Consul cluster.
 	Consul *ConsulConfig `mapstructure:"consul"`
 
@@ -64,6 +70,9 @@ type Config struct {
 	// LogLevel is the level with which to log for this config.
 	LogLevel *string `mapstructure:"log_level"`
 
+	// LogFormat is the format of log output, either "text" or "json".
+	LogFormat *string `mapstructure:"log_format"`
+
 	// FileLog is the configuration for file logging.
 	FileLog *LogFileConfig `mapstructure:"log_file"`
 
@@ -79,12 +88,25 @@ type Config struct {
 	// ReloadSignal is the signal to listen for a reload event.
 	ReloadSignal *os.Signal `mapstructure:"reload_signal"`
 
+	// Status is the configuration for the read-only HTTP status API.
+	Status *StatusConfig `mapstructure:"status"`
+
 	// Syslog is the configuration for syslog.
 	Syslog *SyslogConfig `mapstructure:"syslog"`
 
+	// Telemetry is the configuration for the Prometheus metrics listener.
+	Telemetry *TelemetryConfig `mapstructure:"telemetry"`
+
+	// Tracing is the configuration for OpenTelemetry tracing.
+	Tracing *TracingConfig `mapstructure:"tracing"`
+
 	// Templates is the list of templates.
 	Templates *TemplateConfigs `mapstructure:"template"`
 
+	// TemplateGroups is the list of template groups, whose templates are
+	// rendered all-or-nothing.
+	TemplateGroups *TemplateGroupConfigs `mapstructure:"template_group"`
+
 	// TemplateErrFatal determines whether template errors should cause the
 	// process to exit, or just log and continue.
 	TemplateErrFatal *bool `mapstructure:"template_error_fatal"`
@@ -106,6 +128,22 @@ type Config struct {
 	// checking well formedness.
 	ParseOnly bool
 
+	// DryFormat is the format dry mode prints changes in, one of "diff",
+	// "json" or "contents". It defaults to "diff".
+	DryFormat string
+
+	// Test renders each template once against fixture data instead of live
+	// data, compares the output to the template destination and exits.
+	Test bool
+
+	// TestFixtures is the path to the JSON or YAML file with the fixture data
+	// used in test mode.
+	TestFixtures string
+
+	// TestUpdate rewrites the template destinations with the rendered output in
+	// test mode instead of comparing them.
+	TestUpdate bool
+
 	// BlockQueryWaitTime is amount of time in seconds to do a blocking query for
 	BlockQueryWaitTime *time.Duration `mapstructure:"block_query_wait"`
 
@@ -138,6 +176,10 @@ func (c *Config) Copy() *Config {
 	}
 	var o Config
 
+	if c.Cache != nil {
+		o.Cache = c.Cache.Copy()
+	}
+
 	o.Consul = c.Consul
 
 	if c.Consul != nil {
@@ -160,6 +202,8 @@ func (c *Config) Copy() *Config {
 
 	o.LogLevel = c.LogLevel
 
+	o.LogFormat = c.LogFormat
+
 	o.MaxStale = c.MaxStale
 
 	o.PidFile = c.PidFile
@@ -170,14 +214,30 @@ func (c *Config) Copy() *Config {
 		o.FileLog = c.FileLog.Copy()
 	}
 
+	if c.Status != nil {
+		o.Status = c.Status.Copy()
+	}
+
 	if c.Syslog != nil {
 		o.Syslog = c.Syslog.Copy()
 	}
 
+	if c.Telemetry != nil {
+		o.Telemetry = c.Telemetry.Copy()
+	}
+
+	if c.Tracing != nil {
+		o.Tracing = c.Tracing.Copy()
+	}
+
 	if c.Templates != nil {
 		o.Templates = c.Templates.Copy()
 	}
 
+	if c.TemplateGroups != nil {
+		o.TemplateGroups = c.TemplateGroups.Copy()
+	}
+
 	if c.TemplateErrFatal != nil {
 		o.TemplateErrFatal = c.TemplateErrFatal
 	}
@@ -192,6 +252,10 @@ func (c *Config) Copy() *Config {
 
 	o.Once = c.Once
 	o.ParseOnly = c.ParseOnly
+	o.DryFormat = c.DryFormat
+	o.Test = c.Test
+	o.TestFixtures = c.TestFixtures
+	o.TestUpdate = c.TestUpdate
 	o.ErrOnFailedLookup = c.ErrOnFailedLookup
 	o.BlockQueryWaitTime = c.BlockQueryWaitTime
 
@@ -221,6 +285,10 @@ func (c *Config) Merge(o *Config) *Config {
 
 	r := c.Copy()
 
+	if o.Cache != nil {
+		r.Cache = r.Cache.Merge(o.Cache)
+	}
+
 	if o.Consul != nil {
 		r.Consul = r.Consul.Merge(o.Consul)
 	}
@@ -245,6 +313,10 @@ func (c *Config) Merge(o *Config) *Config {
 		r.LogLevel = o.LogLevel
 	}
 
+	if o.LogFormat != nil {
+		r.LogFormat = o.LogFormat
+	}
+
 	if o.MaxStale != nil {
 		r.MaxStale = o.MaxStale
 	}
@@ -261,14 +333,30 @@ func (c *Config) Merge(o *Config) *Config {
 		r.FileLog = r.FileLog.Merge(o.FileLog)
 	}
 
+	if o.Status != nil {
+		r.Status = r.Status.Merge(o.Status)
+	}
+
 	if o.Syslog != nil {
 		r.Syslog = r.Syslog.Merge(o.Syslog)
 	}
 
+	if o.Telemetry != nil {
+		r.Telemetry = r.Telemetry.Merge(o.Telemetry)
+	}
+
+	if o.Tracing != nil {
+		r.Tracing = r.Tracing.Merge(o.Tracing)
+	}
+
 	if o.Templates != nil {
 		r.Templates = r.Templates.Merge(o.Templates)
 	}
 
+	if o.TemplateGroups != nil {
+		r.TemplateGroups = r.TemplateGroups.Merge(o.TemplateGroups)
+	}
+
 	if o.TemplateErrFatal != nil {
 		r.TemplateErrFatal = o.TemplateErrFatal
 	}
@@ -287,6 +375,14 @@ func (c *Config) Merge(o *Config) *Config {
 
 	r.Once = o.Once
 	r.ParseOnly = o.ParseOnly
+	if o.DryFormat != "" {
+		r.DryFormat = o.DryFormat
+	}
+	r.Test = o.Test
+	if o.TestFixtures != "" {
+		r.TestFixtures = o.TestFixtures
+	}
+	r.TestUpdate = o.TestUpdate
 	if o.ErrOnFailedLookup {
 		r.ErrOnFailedLookup = o.ErrOnFailedLookup
 	}
@@ -320,6 +416,7 @@ func Parse(s string) (*Config, error) {
 
 	flattenKeys(parsed, []string{
 		"auth",
+		"cache",
 		"consul",
 		"consul.auth",
 		"consul.retry",
@@ -335,8 +432,13 @@ func Parse(s string) (*Config, error) {
 		"nomad.ssl",
 		"nomad.transport",
 		"ssl",
+		"status",
 		"syslog",
+		"telemetry",
+		"tracing",
 		"vault",
+		"vault.auth",
+		"vault.lease_store",
 		"vault.retry",
 		"vault.ssl",
 		"vault.transport",
@@ -346,13 +448,19 @@ func Parse(s string) (*Config, error) {
 	// FlattenFlatten keys belonging to the templates. We cannot do this above
 	// because it is an array of templates.
 	if templates, ok := parsed["template"].([]map[string]interface{}); ok {
-		for _, template := range templates {
-			flattenKeys(template, []string{
-				"env",
+		flattenTemplates(templates)
+	}
+
+	// Template groups have their own exec block and a list of templates.
+	if groups, ok := parsed["template_group"].([]map[string]interface{}); ok {
+		for _, group := range groups {
+			flattenKeys(group, []string{
 				"exec",
 				"exec.env",
-				"wait",
 			})
+			if templates, ok := group["template"].([]map[string]interface{}); ok {
+				flattenTemplates(templates)
+			}
 		}
 	}
 
@@ -483,18 +591,24 @@ func (c *Config) GoString() string {
 	}
 
 	return fmt.Sprintf("&Config{"+
+		"Cache:%#v, "+
 		"Consul:%#v, "+
 		"Dedup:%#v, "+
 		"DefaultDelims:%#v, "+
 		"Exec:%#v, "+
 		"KillSignal:%s, "+
 		"LogLevel:%s, "+
+		"LogFormat:%s, "+
 		"MaxStale:%s, "+
 		"PidFile:%s, "+
 		"ReloadSignal:%s, "+
 		"FileLog:%#v, "+
+		"Status:%#v, "+
 		"Syslog:%#v, "+
+		"Telemetry:%#v, "+
+		"Tracing:%#v, "+
 		"Templates:%#v, "+
+		"TemplateGroups:%#v, "+
 		"TemplateErrFatal:%#v"+
 		"Vault:%#v, "+
 		"Wait:%#v, "+
@@ -502,18 +616,24 @@ func (c *Config) GoString() string {
 		"BlockQueryWaitTime:%#v, "+
 		"ErrOnFailedLookup:%#v"+
 		"}",
+		c.Cache,
 		c.Consul,
 		c.Dedup,
 		c.DefaultDelims,
 		c.Exec,
 		SignalGoString(c.KillSignal),
 		StringGoString(c.LogLevel),
+		StringGoString(c.LogFormat),
 		TimeDurationGoString(c.MaxStale),
 		StringGoString(c.PidFile),
 		SignalGoString(c.ReloadSignal),
 		c.FileLog,
+		c.Status,
 		c.Syslog,
+		c.Telemetry,
+		c.Tracing,
 		c.Templates,
+		c.TemplateGroups,
 		c.TemplateErrFatal,
 		c.Vault,
 		c.Wait,
@@ -554,16 +674,21 @@ func (expected *Config) Diff(actual *Config) string {
 // variables may be set which control the values for the default configuration.
 func DefaultConfig() *Config {
 	return &Config{
-		Consul:        DefaultConsulConfig(),
-		Dedup:         DefaultDedupConfig(),
-		DefaultDelims: DefaultDefaultDelims(),
-		Exec:          DefaultExecConfig(),
-		FileLog:       DefaultLogFileConfig(),
-		Nomad:         DefaultNomadConfig(),
-		Syslog:        DefaultSyslogConfig(),
-		Templates:     DefaultTemplateConfigs(),
-		Vault:         DefaultVaultConfig(),
-		Wait:          DefaultWaitConfig(),
+		Cache:          DefaultCacheConfig(),
+		Consul:         DefaultConsulConfig(),
+		Dedup:          DefaultDedupConfig(),
+		DefaultDelims:  DefaultDefaultDelims(),
+		Exec:           DefaultExecConfig(),
+		FileLog:        DefaultLogFileConfig(),
+		Nomad:          DefaultNomadConfig(),
+		Status:         DefaultStatusConfig(),
+		Syslog:         DefaultSyslogConfig(),
+		Telemetry:      DefaultTelemetryConfig(),
+		Tracing:        DefaultTracingConfig(),
+		Templates:      DefaultTemplateConfigs(),
+		TemplateGroups: DefaultTemplateGroupConfigs(),
+		Vault:          DefaultVaultConfig(),
+		Wait:           DefaultWaitConfig(),
 	}
 }
 
@@ -576,6 +701,11 @@ func (c *Config) Finalize() {
 	if c == nil {
 		return
 	}
+	if c.Cache == nil {
+		c.Cache = DefaultCacheConfig()
+	}
+	c.Cache.Finalize()
+
 	if c.Consul == nil {
 		c.Consul = DefaultConsulConfig()
 	}
@@ -607,6 +737,12 @@ func (c *Config) Finalize() {
 		}, DefaultLogLevel)
 	}
 
+	if c.LogFormat == nil {
+		c.LogFormat = stringFromEnv([]string{
+			"CONSUL_TEMPLATE_LOG_FORMAT",
+		}, DefaultLogFormat)
+	}
+
 	if c.MaxStale == nil {
 		c.MaxStale = TimeDuration(DefaultMaxStale)
 	}
@@ -629,11 +765,26 @@ func (c *Config) Finalize() {
 	}
 	c.Nomad.Finalize()
 
+	if c.Status == nil {
+		c.Status = DefaultStatusConfig()
+	}
+	c.Status.Finalize()
+
 	if c.Syslog == nil {
 		c.Syslog = DefaultSyslogConfig()
 	}
 	c.Syslog.Finalize()
 
+	if c.Telemetry == nil {
+		c.Telemetry = DefaultTelemetryConfig()
+	}
+	c.Telemetry.Finalize()
+
+	if c.Tracing == nil {
+		c.Tracing = DefaultTracingConfig()
+	}
+	c.Tracing.Finalize()
+
 	if c.Templates == nil {
 		c.Templates = DefaultTemplateConfigs()
 	}
@@ -644,6 +795,21 @@ func (c *Config) Finalize() {
 	}
 	c.Templates.Finalize()
 
+	if c.TemplateGroups == nil {
+		c.TemplateGroups = DefaultTemplateGroupConfigs()
+	}
+	for _, group := range *c.TemplateGroups {
+		if group.Templates == nil {
+			continue
+		}
+		for _, tmpl := range *group.Templates {
+			if tmpl.ErrFatal == nil {
+				tmpl.ErrFatal = c.TemplateErrFatal
+			}
+		}
+	}
+	c.TemplateGroups.Finalize()
+
 	if c.Vault == nil {
 		c.Vault = DefaultVaultConfig()
 	}
@@ -715,6 +881,18 @@ func boolFromEnv(list []string, def bool) *bool {
 	return Bool(def)
 }
 
+// flattenTemplates flattens the keys of each parsed template block.
+func flattenTemplates(templates []map[string]interface{}) {
+	for _, template := range templates {
+		flattenKeys(template, []string{
+			"env",
+			"exec",
+			"exec.env",
+			"wait",
+		})
+	}
+}
+
 // flattenKeys is a function that takes a map[string]interface{} and recursively
 // flattens any keys that are a []map[string]interface{} where the key is in the
 // given list of keys.
@@ -49,6 +49,20 @@ func TestParse(t *testing.T) {
 		e    *Config
 		err  bool
 	}{
+		{
+			"cache",
+			`cache {
+				path = "/var/lib/consul-template/cache"
+				encryption_key_file = "/etc/consul-template/cache.key"
+			}`,
+			&Config{
+				Cache: &CacheConfig{
+					Path:              String("/var/lib/consul-template/cache"),
+					EncryptionKeyFile: String("/etc/consul-template/cache.key"),
+				},
+			},
+			false,
+		},
 		{
 			"consul_address",
 			`consul {
@@ -79,6 +93,18 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"consul_prepared_query_interval",
+			`consul {
+				prepared_query_interval = "30s"
+			}`,
+			&Config{
+				Consul: &ConsulConfig{
+					PreparedQueryInterval: TimeDuration(30 * time.Second),
+				},
+			},
+			false,
+		},
 		{
 			"consul_retry",
 			`consul {
@@ -553,6 +579,14 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"log_format",
+			`log_format = "json"`,
+			&Config{
+				LogFormat: String("json"),
+			},
+			false,
+		},
 		{
 			"log_file",
 			`log_file {}`,
@@ -705,6 +739,18 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"status",
+			`status {
+				address = "127.0.0.1:9999"
+			}`,
+			&Config{
+				Status: &StatusConfig{
+					Address: String("127.0.0.1:9999"),
+				},
+			},
+			false,
+		},
 		{
 			"syslog",
 			`syslog {}`,
@@ -749,6 +795,50 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"telemetry",
+			`telemetry {
+				address = "127.0.0.1:9999"
+				metrics_prefix = "ct"
+			}`,
+			&Config{
+				Telemetry: &TelemetryConfig{
+					Address:       String("127.0.0.1:9999"),
+					MetricsPrefix: String("ct"),
+				},
+			},
+			false,
+		},
+		{
+			"tracing",
+			`tracing {
+				endpoint = "collector:4318"
+				insecure = true
+				service_name = "ct"
+			}`,
+			&Config{
+				Tracing: &TracingConfig{
+					Endpoint:    String("collector:4318"),
+					Insecure:    Bool(true),
+					ServiceName: String("ct"),
+				},
+			},
+			false,
+		},
+		{
+			"tracing_file",
+			`tracing {
+				exporter = "file"
+				file_path = "/tmp/traces.json"
+			}`,
+			&Config{
+				Tracing: &TracingConfig{
+					Exporter: String("file"),
+					FilePath: String("/tmp/traces.json"),
+				},
+			},
+			false,
+		},
 		{
 			"template",
 			`template {}`,
@@ -1246,6 +1336,62 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"template_validate_command",
+			`template {
+				validate_command = "nginx -t -c {{path}}"
+				validate_command_timeout = "5s"
+			}`,
+			&Config{
+				Templates: &TemplateConfigs{
+					&TemplateConfig{
+						ValidateCommand:        []string{"nginx -t -c {{path}}"},
+						ValidateCommandTimeout: TimeDuration(5 * time.Second),
+					},
+				},
+			},
+			false,
+		},
+		{
+			"template_group",
+			`template_group {
+				name = "tls"
+				exec {
+					command = "systemctl reload nginx"
+				}
+				template {
+					source = "cert.tpl"
+					destination = "/etc/nginx/cert.pem"
+				}
+				template {
+					source = "key.tpl"
+					destination = "/etc/nginx/key.pem"
+					perms = 0600
+				}
+			}`,
+			&Config{
+				TemplateGroups: &TemplateGroupConfigs{
+					&TemplateGroupConfig{
+						Name: String("tls"),
+						Exec: &ExecConfig{
+							Command: []string{"systemctl reload nginx"},
+						},
+						Templates: &TemplateConfigs{
+							&TemplateConfig{
+								Source:      String("cert.tpl"),
+								Destination: String("/etc/nginx/cert.pem"),
+							},
+							&TemplateConfig{
+								Source:      String("key.tpl"),
+								Destination: String("/etc/nginx/key.pem"),
+								Perms:       FileMode(0o600),
+							},
+						},
+					},
+				},
+			},
+			false,
+		},
 		{
 			"template_wait",
 			`template {
@@ -1475,6 +1621,44 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"vault_auth",
+			`vault {
+				auth {
+					method         = "approle"
+					role_id_file   = "/etc/role_id"
+					secret_id_file = "/etc/secret_id"
+				}
+			}`,
+			&Config{
+				Vault: &VaultConfig{
+					Auth: &VaultAuthConfig{
+						Method:       String("approle"),
+						RoleIDFile:   String("/etc/role_id"),
+						SecretIDFile: String("/etc/secret_id"),
+					},
+				},
+			},
+			false,
+		},
+		{
+			"vault_lease_store",
+			`vault {
+				lease_store {
+					path                = "/var/lib/consul-template/leases"
+					encryption_key_file = "/etc/consul-template/key"
+				}
+			}`,
+			&Config{
+				Vault: &VaultConfig{
+					LeaseStore: &VaultLeaseStoreConfig{
+						Path:              String("/var/lib/consul-template/leases"),
+						EncryptionKeyFile: String("/etc/consul-template/key"),
+					},
+				},
+			},
+			false,
+		},
 		{
 			"vault_unwrap_token",
 			`vault {
@@ -1499,6 +1683,18 @@ func TestParse(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"vault_revoke_on_shutdown",
+			`vault {
+				revoke_on_shutdown = true
+			}`,
+			&Config{
+				Vault: &VaultConfig{
+					RevokeOnShutdown: Bool(true),
+				},
+			},
+			false,
+		},
 		{
 			"vault_retry_backoff",
 			`vault {
@@ -2174,6 +2370,18 @@ func TestConfig_Merge(t *testing.T) {
 				LogLevel: String("log_level-diff"),
 			},
 		},
+		{
+			"log_format",
+			&Config{
+				LogFormat: String("text"),
+			},
+			&Config{
+				LogFormat: String("json"),
+			},
+			&Config{
+				LogFormat: String("json"),
+			},
+		},
 		{
 			"file_log",
 			&Config{
@@ -2348,6 +2556,33 @@ func TestConfig_Merge(t *testing.T) {
 				ParseOnly: true,
 			},
 		},
+		{
+			"dry_format",
+			&Config{
+				DryFormat: "json",
+			},
+			&Config{
+				DryFormat: "contents",
+			},
+			&Config{
+				DryFormat: "contents",
+			},
+		},
+		{
+			"test",
+			&Config{
+				TestFixtures: "/tmp/a.json",
+			},
+			&Config{
+				Test:       true,
+				TestUpdate: true,
+			},
+			&Config{
+				Test:         true,
+				TestFixtures: "/tmp/a.json",
+				TestUpdate:   true,
+			},
+		},
 	}
 
 	for i, tc := range cases {
@@ -2479,6 +2714,14 @@ func TestDefaultConfig(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"CONSUL_TEMPLATE_LOG_FORMAT",
+			"json",
+			&Config{
+				LogFormat: String("json"),
+			},
+			false,
+		},
 		{
 			"CONSUL_TOKEN",
 			"token",
@@ -3,7 +3,18 @@
 
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/consul-template/dependency"
+)
+
+const (
+	// DefaultPreparedQueryInterval is the default amount of time between
+	// executions of a prepared query.
+	DefaultPreparedQueryInterval = dependency.DefaultNonBlockingQuerySleepTime
+)
 
 // ConsulConfig contains the configurations options for connecting to a
 // Consul cluster.
@@ -18,6 +29,10 @@ type ConsulConfig struct {
 	// Auth is the HTTP basic authentication for communicating with Consul.
 	Auth *AuthConfig `mapstructure:"auth"`
 
+	// PreparedQueryInterval is the amount of time between executions of a
+	// prepared query, since prepared queries do not support blocking queries.
+	PreparedQueryInterval *time.Duration `mapstructure:"prepared_query_interval"`
+
 	// Retry is the configuration for specifying how to behave on failure.
 	Retry *RetryConfig `mapstructure:"retry"`
 
@@ -62,6 +77,8 @@ func (c *ConsulConfig) Copy() *ConsulConfig {
 		o.Auth = c.Auth.Copy()
 	}
 
+	o.PreparedQueryInterval = c.PreparedQueryInterval
+
 	if c.Retry != nil {
 		o.Retry = c.Retry.Copy()
 	}
@@ -110,6 +127,10 @@ func (c *ConsulConfig) Merge(o *ConsulConfig) *ConsulConfig {
 		r.Auth = r.Auth.Merge(o.Auth)
 	}
 
+	if o.PreparedQueryInterval != nil {
+		r.PreparedQueryInterval = o.PreparedQueryInterval
+	}
+
 	if o.Retry != nil {
 		r.Retry = r.Retry.Merge(o.Retry)
 	}
@@ -150,6 +171,10 @@ func (c *ConsulConfig) Finalize() {
 	}
 	c.Auth.Finalize()
 
+	if c.PreparedQueryInterval == nil {
+		c.PreparedQueryInterval = TimeDuration(DefaultPreparedQueryInterval)
+	}
+
 	if c.Retry == nil {
 		c.Retry = DefaultRetryConfig()
 	}
@@ -190,6 +215,7 @@ func (c *ConsulConfig) GoString() string {
 		"Address:%s, "+
 		"Namespace:%s, "+
 		"Auth:%#v, "+
+		"PreparedQueryInterval:%s, "+
 		"Retry:%#v, "+
 		"SSL:%#v, "+
 		"Token:%t, "+
@@ -199,6 +225,7 @@ func (c *ConsulConfig) GoString() string {
 		StringGoString(c.Address),
 		StringGoString(c.Namespace),
 		c.Auth,
+		TimeDurationGoString(c.PreparedQueryInterval),
 		c.Retry,
 		c.SSL,
 		StringPresent(c.Token),
@@ -26,13 +26,14 @@ func TestConsulConfig_Copy(t *testing.T) {
 		{
 			"same_enabled",
 			&ConsulConfig{
-				Address:   String("1.2.3.4"),
-				Namespace: String("foo"),
-				Auth:      &AuthConfig{Enabled: Bool(true)},
-				Retry:     &RetryConfig{Enabled: Bool(true)},
-				SSL:       &SSLConfig{Enabled: Bool(true)},
-				Token:     String("abcd1234"),
-				TokenFile: String("/a/very/secret/path"),
+				Address:               String("1.2.3.4"),
+				Namespace:             String("foo"),
+				Auth:                  &AuthConfig{Enabled: Bool(true)},
+				PreparedQueryInterval: TimeDuration(30 * time.Second),
+				Retry:                 &RetryConfig{Enabled: Bool(true)},
+				SSL:                   &SSLConfig{Enabled: Bool(true)},
+				Token:                 String("abcd1234"),
+				TokenFile:             String("/a/very/secret/path"),
 				Transport: &TransportConfig{
 					DialKeepAlive: TimeDuration(20 * time.Second),
 				},
@@ -129,6 +130,24 @@ func TestConsulConfig_Merge(t *testing.T) {
 			&ConsulConfig{Namespace: String("foo")},
 			&ConsulConfig{Namespace: String("foo")},
 		},
+		{
+			"prepared_query_interval_overrides",
+			&ConsulConfig{PreparedQueryInterval: TimeDuration(10 * time.Second)},
+			&ConsulConfig{PreparedQueryInterval: TimeDuration(20 * time.Second)},
+			&ConsulConfig{PreparedQueryInterval: TimeDuration(20 * time.Second)},
+		},
+		{
+			"prepared_query_interval_empty_one",
+			&ConsulConfig{PreparedQueryInterval: TimeDuration(10 * time.Second)},
+			&ConsulConfig{},
+			&ConsulConfig{PreparedQueryInterval: TimeDuration(10 * time.Second)},
+		},
+		{
+			"prepared_query_interval_empty_two",
+			&ConsulConfig{},
+			&ConsulConfig{PreparedQueryInterval: TimeDuration(20 * time.Second)},
+			&ConsulConfig{PreparedQueryInterval: TimeDuration(20 * time.Second)},
+		},
 		{
 			"auth_overrides",
 			&ConsulConfig{Auth: &AuthConfig{Enabled: Bool(true)}},
@@ -302,6 +321,7 @@ func TestConsulConfig_Finalize(t *testing.T) {
 					Username: String(""),
 					Password: String(""),
 				},
+				PreparedQueryInterval: TimeDuration(DefaultPreparedQueryInterval),
 				Retry: &RetryConfig{
 					Backoff:    TimeDuration(DefaultRetryBackoff),
 					MaxBackoff: TimeDuration(DefaultRetryMaxBackoff),
@@ -0,0 +1,155 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestStatusConfig_Copy(t *testing.T) {
+	cases := []struct {
+		name string
+		a    *StatusConfig
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&StatusConfig{},
+		},
+		{
+			"same_enabled",
+			&StatusConfig{
+				Enabled: Bool(true),
+				Address: String("127.0.0.1:9999"),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Copy()
+			if !reflect.DeepEqual(tc.a, r) {
+				t.Errorf("\nexp: %#v\nact: %#v", tc.a, r)
+			}
+		})
+	}
+}
+
+func TestStatusConfig_Merge(t *testing.T) {
+	cases := []struct {
+		name string
+		a    *StatusConfig
+		b    *StatusConfig
+		r    *StatusConfig
+	}{
+		{
+			"nil_a",
+			nil,
+			&StatusConfig{},
+			&StatusConfig{},
+		},
+		{
+			"nil_b",
+			&StatusConfig{},
+			nil,
+			&StatusConfig{},
+		},
+		{
+			"nil_both",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"empty",
+			&StatusConfig{},
+			&StatusConfig{},
+			&StatusConfig{},
+		},
+		{
+			"enabled_overrides",
+			&StatusConfig{Enabled: Bool(true)},
+			&StatusConfig{Enabled: Bool(false)},
+			&StatusConfig{Enabled: Bool(false)},
+		},
+		{
+			"enabled_empty_one",
+			&StatusConfig{Enabled: Bool(true)},
+			&StatusConfig{},
+			&StatusConfig{Enabled: Bool(true)},
+		},
+		{
+			"address_overrides",
+			&StatusConfig{Address: String("127.0.0.1:1")},
+			&StatusConfig{Address: String("127.0.0.1:2")},
+			&StatusConfig{Address: String("127.0.0.1:2")},
+		},
+		{
+			"address_empty_two",
+			&StatusConfig{},
+			&StatusConfig{Address: String("127.0.0.1:2")},
+			&StatusConfig{Address: String("127.0.0.1:2")},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Merge(tc.b)
+			if !reflect.DeepEqual(tc.r, r) {
+				t.Errorf("\nexp: %#v\nact: %#v", tc.r, r)
+			}
+		})
+	}
+}
+
+func TestStatusConfig_Finalize(t *testing.T) {
+	cases := []struct {
+		name string
+		i    *StatusConfig
+		r    *StatusConfig
+	}{
+		{
+			"empty",
+			&StatusConfig{},
+			&StatusConfig{
+				Enabled: Bool(false),
+				Address: String(DefaultStatusAddress),
+			},
+		},
+		{
+			"with_address",
+			&StatusConfig{
+				Address: String("0.0.0.0:9999"),
+			},
+			&StatusConfig{
+				Enabled: Bool(true),
+				Address: String("0.0.0.0:9999"),
+			},
+		},
+		{
+			"enabled",
+			&StatusConfig{
+				Enabled: Bool(true),
+			},
+			&StatusConfig{
+				Enabled: Bool(true),
+				Address: String(DefaultStatusAddress),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			tc.i.Finalize()
+			if !reflect.DeepEqual(tc.r, tc.i) {
+				t.Errorf("\nexp: %#v\nact: %#v", tc.r, tc.i)
+			}
+		})
+	}
+}
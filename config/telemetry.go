@@ -0,0 +1,116 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+)
+
+const (
+	// DefaultTelemetryAddress is the default address the metrics listener
+	// binds to when telemetry is enabled.
+	DefaultTelemetryAddress = "127.0.0.1:9110"
+
+	// DefaultTelemetryMetricsPrefix is the default prefix for all emitted
+	// metric names.
+	DefaultTelemetryMetricsPrefix = "consul_template"
+)
+
+// TelemetryConfig is the configuration for the Prometheus metrics listener.
+type TelemetryConfig struct {
+	// Enabled controls whether the metrics listener is started. Specifying an
+	// address also enables telemetry.
+	Enabled *bool `mapstructure:"enabled"`
+
+	// Address is the host:port the metrics listener binds to. Metrics are
+	// served at /metrics in the Prometheus text exposition format.
+	Address *string `mapstructure:"address"`
+
+	// MetricsPrefix is prepended to every metric name.
+	MetricsPrefix *string `mapstructure:"metrics_prefix"`
+}
+
+// DefaultTelemetryConfig returns a configuration that is populated with the
+// default values.
+func DefaultTelemetryConfig() *TelemetryConfig {
+	return &TelemetryConfig{}
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *TelemetryConfig) Copy() *TelemetryConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o TelemetryConfig
+	o.Enabled = c.Enabled
+	o.Address = c.Address
+	o.MetricsPrefix = c.MetricsPrefix
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration, with values in the other configuration taking precedence.
+// Maps and slices are merged, most other values are overwritten. Complex
+// structs define their own merge functionality.
+func (c *TelemetryConfig) Merge(o *TelemetryConfig) *TelemetryConfig {
+	if c == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if o == nil {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+
+	if o.Enabled != nil {
+		r.Enabled = o.Enabled
+	}
+
+	if o.Address != nil {
+		r.Address = o.Address
+	}
+
+	if o.MetricsPrefix != nil {
+		r.MetricsPrefix = o.MetricsPrefix
+	}
+
+	return r
+}
+
+// Finalize ensures there no nil pointers.
+func (c *TelemetryConfig) Finalize() {
+	if c.Enabled == nil {
+		c.Enabled = Bool(StringPresent(c.Address))
+	}
+
+	if c.Address == nil {
+		c.Address = String(DefaultTelemetryAddress)
+	}
+
+	if c.MetricsPrefix == nil {
+		c.MetricsPrefix = String(DefaultTelemetryMetricsPrefix)
+	}
+}
+
+// GoString defines the printable version of this struct.
+func (c *TelemetryConfig) GoString() string {
+	if c == nil {
+		return "(*TelemetryConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&TelemetryConfig{"+
+		"Enabled:%s, "+
+		"Address:%s, "+
+		"MetricsPrefix:%s"+
+		"}",
+		BoolGoString(c.Enabled),
+		StringGoString(c.Address),
+		StringGoString(c.MetricsPrefix),
+	)
+}
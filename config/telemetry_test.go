@@ -0,0 +1,171 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestTelemetryConfig_Copy(t *testing.T) {
+	cases := []struct {
+		name string
+		a    *TelemetryConfig
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&TelemetryConfig{},
+		},
+		{
+			"same_enabled",
+			&TelemetryConfig{
+				Enabled:       Bool(true),
+				Address:       String("127.0.0.1:9999"),
+				MetricsPrefix: String("prefix"),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Copy()
+			if !reflect.DeepEqual(tc.a, r) {
+				t.Errorf("\nexp: %#v\nact: %#v", tc.a, r)
+			}
+		})
+	}
+}
+
+func TestTelemetryConfig_Merge(t *testing.T) {
+	cases := []struct {
+		name string
+		a    *TelemetryConfig
+		b    *TelemetryConfig
+		r    *TelemetryConfig
+	}{
+		{
+			"nil_a",
+			nil,
+			&TelemetryConfig{},
+			&TelemetryConfig{},
+		},
+		{
+			"nil_b",
+			&TelemetryConfig{},
+			nil,
+			&TelemetryConfig{},
+		},
+		{
+			"nil_both",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"empty",
+			&TelemetryConfig{},
+			&TelemetryConfig{},
+			&TelemetryConfig{},
+		},
+		{
+			"enabled_overrides",
+			&TelemetryConfig{Enabled: Bool(true)},
+			&TelemetryConfig{Enabled: Bool(false)},
+			&TelemetryConfig{Enabled: Bool(false)},
+		},
+		{
+			"enabled_empty_one",
+			&TelemetryConfig{Enabled: Bool(true)},
+			&TelemetryConfig{},
+			&TelemetryConfig{Enabled: Bool(true)},
+		},
+		{
+			"address_overrides",
+			&TelemetryConfig{Address: String("127.0.0.1:1")},
+			&TelemetryConfig{Address: String("127.0.0.1:2")},
+			&TelemetryConfig{Address: String("127.0.0.1:2")},
+		},
+		{
+			"address_empty_two",
+			&TelemetryConfig{},
+			&TelemetryConfig{Address: String("127.0.0.1:2")},
+			&TelemetryConfig{Address: String("127.0.0.1:2")},
+		},
+		{
+			"metrics_prefix_overrides",
+			&TelemetryConfig{MetricsPrefix: String("a")},
+			&TelemetryConfig{MetricsPrefix: String("b")},
+			&TelemetryConfig{MetricsPrefix: String("b")},
+		},
+		{
+			"metrics_prefix_empty_one",
+			&TelemetryConfig{MetricsPrefix: String("a")},
+			&TelemetryConfig{},
+			&TelemetryConfig{MetricsPrefix: String("a")},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Merge(tc.b)
+			if !reflect.DeepEqual(tc.r, r) {
+				t.Errorf("\nexp: %#v\nact: %#v", tc.r, r)
+			}
+		})
+	}
+}
+
+func TestTelemetryConfig_Finalize(t *testing.T) {
+	cases := []struct {
+		name string
+		i    *TelemetryConfig
+		r    *TelemetryConfig
+	}{
+		{
+			"empty",
+			&TelemetryConfig{},
+			&TelemetryConfig{
+				Enabled:       Bool(false),
+				Address:       String(DefaultTelemetryAddress),
+				MetricsPrefix: String(DefaultTelemetryMetricsPrefix),
+			},
+		},
+		{
+			"with_address",
+			&TelemetryConfig{
+				Address: String("0.0.0.0:9999"),
+			},
+			&TelemetryConfig{
+				Enabled:       Bool(true),
+				Address:       String("0.0.0.0:9999"),
+				MetricsPrefix: String(DefaultTelemetryMetricsPrefix),
+			},
+		},
+		{
+			"enabled",
+			&TelemetryConfig{
+				Enabled: Bool(true),
+			},
+			&TelemetryConfig{
+				Enabled:       Bool(true),
+				Address:       String(DefaultTelemetryAddress),
+				MetricsPrefix: String(DefaultTelemetryMetricsPrefix),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			tc.i.Finalize()
+			if !reflect.DeepEqual(tc.r, tc.i) {
+				t.Errorf("\nexp: %#v\nact: %#v", tc.r, tc.i)
+			}
+		})
+	}
+}
@@ -99,6 +99,18 @@ type TemplateConfig struct {
 	// this or Contents should be specified, but not both.
 	Source *string `mapstructure:"source"`
 
+	// ValidateCommand is the command to run against the rendered contents
+	// before they replace the destination. The contents are written to a
+	// temporary file next to the destination, whose path replaces any
+	// "{{path}}" in the command and is also set in the
+	// CONSUL_TEMPLATE_VALIDATE_PATH environment variable. If the command exits
+	// non-zero, the destination is left untouched and no command is run.
+	ValidateCommand commandList `mapstructure:"validate_command"`
+
+	// ValidateCommandTimeout is the amount of time to wait for the validate
+	// command to finish before force-killing it and failing the render.
+	ValidateCommandTimeout *time.Duration `mapstructure:"validate_command_timeout"`
+
 	// Wait configures per-template quiescence timers.
 	Wait *WaitConfig `mapstructure:"wait"`
 
@@ -184,6 +196,10 @@ func (c *TemplateConfig) Copy() *TemplateConfig {
 	o.Uid = c.Uid
 	o.Gid = c.Gid
 
+	o.ValidateCommand = c.ValidateCommand
+
+	o.ValidateCommandTimeout = c.ValidateCommandTimeout
+
 	if c.Wait != nil {
 		o.Wait = c.Wait.Copy()
 	}
@@ -283,6 +299,14 @@ func (c *TemplateConfig) Merge(o *TemplateConfig) *TemplateConfig {
 		r.Gid = o.Gid
 	}
 
+	if o.ValidateCommand != nil {
+		r.ValidateCommand = o.ValidateCommand
+	}
+
+	if o.ValidateCommandTimeout != nil {
+		r.ValidateCommandTimeout = o.ValidateCommandTimeout
+	}
+
 	if o.Wait != nil {
 		r.Wait = r.Wait.Merge(o.Wait)
 	}
@@ -390,6 +414,14 @@ func (c *TemplateConfig) Finalize() {
 		c.Source = String("")
 	}
 
+	if c.ValidateCommand == nil {
+		c.ValidateCommand = []string{}
+	}
+
+	if c.ValidateCommandTimeout == nil {
+		c.ValidateCommandTimeout = TimeDuration(DefaultTemplateCommandTimeout)
+	}
+
 	if c.Wait == nil {
 		c.Wait = DefaultWaitConfig()
 	}
@@ -441,6 +473,8 @@ func (c *TemplateConfig) GoString() string {
 		"Exec:%#v, "+
 		"Perms:%s, "+
 		"Source:%s, "+
+		"ValidateCommand:%s, "+
+		"ValidateCommandTimeout:%s, "+
 		"Wait:%#v, "+
 		"LeftDelim:%s, "+
 		"RightDelim:%s, "+
@@ -460,6 +494,8 @@ func (c *TemplateConfig) GoString() string {
 		c.Exec,
 		FileModeGoString(c.Perms),
 		StringGoString(c.Source),
+		c.ValidateCommand,
+		TimeDurationGoString(c.ValidateCommandTimeout),
 		c.Wait,
 		StringGoString(c.LeftDelim),
 		StringGoString(c.RightDelim),
@@ -0,0 +1,207 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TemplateGroupConfig is a set of templates that are rendered all-or-nothing.
+// None of the templates in a group are written until every one of them can
+// be rendered, the files are then replaced together and the group's command
+// is run once.
+type TemplateGroupConfig struct {
+	// Name identifies the group in logs.
+	Name *string `mapstructure:"name"`
+
+	// Exec is the configuration for the command to run when the group renders.
+	// Templates in a group cannot have their own command.
+	Exec *ExecConfig `mapstructure:"exec"`
+
+	// Templates is the list of templates in the group.
+	Templates *TemplateConfigs `mapstructure:"template"`
+}
+
+// DefaultTemplateGroupConfig returns a configuration that is populated with
+// the default values.
+func DefaultTemplateGroupConfig() *TemplateGroupConfig {
+	return &TemplateGroupConfig{
+		Exec:      DefaultExecConfig(),
+		Templates: DefaultTemplateConfigs(),
+	}
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *TemplateGroupConfig) Copy() *TemplateGroupConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o TemplateGroupConfig
+
+	o.Name = c.Name
+
+	if c.Exec != nil {
+		o.Exec = c.Exec.Copy()
+	}
+
+	if c.Templates != nil {
+		o.Templates = c.Templates.Copy()
+	}
+
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration, with values in the other configuration taking precedence.
+// Maps and slices are merged, most other values are overwritten. Complex
+// structs define their own merge functionality.
+func (c *TemplateGroupConfig) Merge(o *TemplateGroupConfig) *TemplateGroupConfig {
+	if c == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if o == nil {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+
+	if o.Name != nil {
+		r.Name = o.Name
+	}
+
+	if o.Exec != nil {
+		r.Exec = r.Exec.Merge(o.Exec)
+	}
+
+	if o.Templates != nil {
+		r.Templates = r.Templates.Merge(o.Templates)
+	}
+
+	return r
+}
+
+// Finalize ensures the configuration has no nil pointers and sets default
+// values.
+func (c *TemplateGroupConfig) Finalize() {
+	if c.Name == nil {
+		c.Name = String("")
+	}
+
+	if c.Exec == nil {
+		c.Exec = DefaultExecConfig()
+	}
+	if c.Exec.Timeout == nil {
+		c.Exec.Timeout = TimeDuration(DefaultTemplateCommandTimeout)
+	}
+	c.Exec.Finalize()
+
+	if c.Templates == nil {
+		c.Templates = DefaultTemplateConfigs()
+	}
+	c.Templates.Finalize()
+}
+
+// GoString defines the printable version of this struct.
+func (c *TemplateGroupConfig) GoString() string {
+	if c == nil {
+		return "(*TemplateGroupConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&TemplateGroupConfig{"+
+		"Name:%s, "+
+		"Exec:%#v, "+
+		"Templates:%#v"+
+		"}",
+		StringGoString(c.Name),
+		c.Exec,
+		c.Templates,
+	)
+}
+
+// Display is the human-friendly form of this configuration, for use in logs.
+func (c *TemplateGroupConfig) Display() string {
+	if c == nil {
+		return ""
+	}
+
+	if StringPresent(c.Name) {
+		return fmt.Sprintf("template group %q", StringVal(c.Name))
+	}
+
+	var destinations []string
+	if c.Templates != nil {
+		for _, t := range *c.Templates {
+			destinations = append(destinations, StringVal(t.Destination))
+		}
+	}
+	return fmt.Sprintf("template group %q", strings.Join(destinations, ", "))
+}
+
+// TemplateGroupConfigs is a collection of TemplateGroupConfigs.
+type TemplateGroupConfigs []*TemplateGroupConfig
+
+// DefaultTemplateGroupConfigs returns a configuration that is populated with
+// the default values.
+func DefaultTemplateGroupConfigs() *TemplateGroupConfigs {
+	return &TemplateGroupConfigs{}
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *TemplateGroupConfigs) Copy() *TemplateGroupConfigs {
+	o := make(TemplateGroupConfigs, len(*c))
+	for i, g := range *c {
+		o[i] = g.Copy()
+	}
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration, with values in the other configuration taking precedence.
+// Groups are appended, like templates.
+func (c *TemplateGroupConfigs) Merge(o *TemplateGroupConfigs) *TemplateGroupConfigs {
+	if c == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if o == nil {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+
+	*r = append(*r, *o...)
+
+	return r
+}
+
+// Finalize ensures the configuration has no nil pointers and sets default
+// values.
+func (c *TemplateGroupConfigs) Finalize() {
+	for _, g := range *c {
+		g.Finalize()
+	}
+}
+
+// GoString defines the printable version of this struct.
+func (c *TemplateGroupConfigs) GoString() string {
+	if c == nil {
+		return "(*TemplateGroupConfigs)(nil)"
+	}
+
+	s := make([]string, len(*c))
+	for i, g := range *c {
+		s[i] = g.GoString()
+	}
+
+	return "{" + strings.Join(s, ", ") + "}"
+}
@@ -0,0 +1,205 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTemplateGroupConfig_Copy(t *testing.T) {
+	cases := []struct {
+		name string
+		a    *TemplateGroupConfig
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&TemplateGroupConfig{},
+		},
+		{
+			"same_enabled",
+			&TemplateGroupConfig{
+				Name: String("tls"),
+				Exec: &ExecConfig{
+					Command: []string{"reload"},
+				},
+				Templates: &TemplateConfigs{
+					&TemplateConfig{Destination: String("/tmp/cert")},
+					&TemplateConfig{Destination: String("/tmp/key")},
+				},
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Copy()
+			if !reflect.DeepEqual(tc.a, r) {
+				t.Errorf("\nexp: %#v\nact: %#v", tc.a, r)
+			}
+		})
+	}
+}
+
+func TestTemplateGroupConfig_Merge(t *testing.T) {
+	cases := []struct {
+		name string
+		a    *TemplateGroupConfig
+		b    *TemplateGroupConfig
+		r    *TemplateGroupConfig
+	}{
+		{
+			"nil_a",
+			nil,
+			&TemplateGroupConfig{},
+			&TemplateGroupConfig{},
+		},
+		{
+			"nil_b",
+			&TemplateGroupConfig{},
+			nil,
+			&TemplateGroupConfig{},
+		},
+		{
+			"nil_both",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"empty",
+			&TemplateGroupConfig{},
+			&TemplateGroupConfig{},
+			&TemplateGroupConfig{},
+		},
+		{
+			"name_overrides",
+			&TemplateGroupConfig{Name: String("a")},
+			&TemplateGroupConfig{Name: String("b")},
+			&TemplateGroupConfig{Name: String("b")},
+		},
+		{
+			"name_empty_one",
+			&TemplateGroupConfig{Name: String("a")},
+			&TemplateGroupConfig{},
+			&TemplateGroupConfig{Name: String("a")},
+		},
+		{
+			"exec_overrides",
+			&TemplateGroupConfig{Exec: &ExecConfig{Command: []string{"a"}}},
+			&TemplateGroupConfig{Exec: &ExecConfig{Command: []string{"b"}}},
+			&TemplateGroupConfig{Exec: &ExecConfig{Command: []string{"b"}}},
+		},
+		{
+			"templates_appends",
+			&TemplateGroupConfig{Templates: &TemplateConfigs{
+				&TemplateConfig{Destination: String("a")},
+			}},
+			&TemplateGroupConfig{Templates: &TemplateConfigs{
+				&TemplateConfig{Destination: String("b")},
+			}},
+			&TemplateGroupConfig{Templates: &TemplateConfigs{
+				&TemplateConfig{Destination: String("a")},
+				&TemplateConfig{Destination: String("b")},
+			}},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Merge(tc.b)
+			if !reflect.DeepEqual(tc.r, r) {
+				t.Errorf("\nexp: %#v\nact: %#v", tc.r, r)
+			}
+		})
+	}
+}
+
+func TestTemplateGroupConfig_Finalize(t *testing.T) {
+	exec := func(c *ExecConfig) *ExecConfig {
+		c.Finalize()
+		return c
+	}
+
+	cases := []struct {
+		name string
+		i    *TemplateGroupConfig
+		r    *TemplateGroupConfig
+	}{
+		{
+			"empty",
+			&TemplateGroupConfig{},
+			&TemplateGroupConfig{
+				Name: String(""),
+				Exec: exec(&ExecConfig{
+					Timeout: TimeDuration(DefaultTemplateCommandTimeout),
+				}),
+				Templates: &TemplateConfigs{},
+			},
+		},
+		{
+			"with_timeout",
+			&TemplateGroupConfig{
+				Name: String("tls"),
+				Exec: &ExecConfig{
+					Command: []string{"reload"},
+					Timeout: TimeDuration(5 * time.Second),
+				},
+			},
+			&TemplateGroupConfig{
+				Name: String("tls"),
+				Exec: exec(&ExecConfig{
+					Command: []string{"reload"},
+					Timeout: TimeDuration(5 * time.Second),
+				}),
+				Templates: &TemplateConfigs{},
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			tc.i.Finalize()
+			if !reflect.DeepEqual(tc.r, tc.i) {
+				t.Errorf("\nexp: %#v\nact: %#v", tc.r, tc.i)
+			}
+		})
+	}
+}
+
+func TestTemplateGroupConfig_Display(t *testing.T) {
+	cases := []struct {
+		name string
+		c    *TemplateGroupConfig
+		e    string
+	}{
+		{
+			"name",
+			&TemplateGroupConfig{Name: String("tls")},
+			`template group "tls"`,
+		},
+		{
+			"destinations",
+			&TemplateGroupConfig{Templates: &TemplateConfigs{
+				&TemplateConfig{Destination: String("/tmp/cert")},
+				&TemplateConfig{Destination: String("/tmp/key")},
+			}},
+			`template group "/tmp/cert, /tmp/key"`,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			if a := tc.c.Display(); a != tc.e {
+				t.Errorf("\nexp: %q\nact: %q", tc.e, a)
+			}
+		})
+	}
+}
@@ -36,6 +36,8 @@ func TestTemplateConfig_Copy(t *testing.T) {
 				Exec:                     &ExecConfig{Command: []string{"command"}},
 				Perms:                    FileMode(0o600),
 				Source:                   String("source"),
+				ValidateCommand:          []string{"validate"},
+				ValidateCommandTimeout:   TimeDuration(10 * time.Second),
 				Wait:                     &WaitConfig{Min: TimeDuration(10)},
 				LeftDelim:                String("left_delim"),
 				RightDelim:               String("right_delim"),
@@ -109,6 +111,24 @@ func TestTemplateConfig_Merge(t *testing.T) {
 			&TemplateConfig{Backup: Bool(true)},
 			&TemplateConfig{Backup: Bool(true)},
 		},
+		{
+			"validate_command_overrides",
+			&TemplateConfig{ValidateCommand: []string{"validate"}},
+			&TemplateConfig{ValidateCommand: []string{}},
+			&TemplateConfig{ValidateCommand: []string{}},
+		},
+		{
+			"validate_command_empty_one",
+			&TemplateConfig{ValidateCommand: []string{"validate"}},
+			&TemplateConfig{},
+			&TemplateConfig{ValidateCommand: []string{"validate"}},
+		},
+		{
+			"validate_command_timeout_overrides",
+			&TemplateConfig{ValidateCommandTimeout: TimeDuration(10 * time.Second)},
+			&TemplateConfig{ValidateCommandTimeout: TimeDuration(0)},
+			&TemplateConfig{ValidateCommandTimeout: TimeDuration(0)},
+		},
 		{
 			"command_overrides",
 			&TemplateConfig{Command: []string{"command"}},
@@ -462,8 +482,10 @@ func TestTemplateConfig_Finalize(t *testing.T) {
 					Splay:        TimeDuration(0 * time.Second),
 					Timeout:      TimeDuration(DefaultTemplateCommandTimeout),
 				},
-				Perms:  FileMode(0),
-				Source: String(""),
+				Perms:                  FileMode(0),
+				Source:                 String(""),
+				ValidateCommand:        []string{},
+				ValidateCommandTimeout: TimeDuration(DefaultTemplateCommandTimeout),
 				Wait: &WaitConfig{
 					Enabled: Bool(false),
 					Max:     TimeDuration(0 * time.Second),
@@ -0,0 +1,167 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+)
+
+const (
+	// TracingExporterOTLP sends spans to an OTLP/HTTP collector.
+	TracingExporterOTLP = "otlp"
+
+	// TracingExporterFile writes spans as JSON to a local file.
+	TracingExporterFile = "file"
+
+	// DefaultTracingEndpoint is the default OTLP/HTTP collector endpoint.
+	DefaultTracingEndpoint = "localhost:4318"
+
+	// DefaultTracingServiceName is the default service name attached to all
+	// spans.
+	DefaultTracingServiceName = "consul-template"
+)
+
+// TracingConfig is the configuration for OpenTelemetry tracing.
+type TracingConfig struct {
+	// Enabled controls whether spans are recorded and exported. Specifying an
+	// endpoint or a file path also enables tracing.
+	Enabled *bool `mapstructure:"enabled"`
+
+	// Exporter is where spans are sent, either "otlp" or "file". It defaults to
+	// "file" when FilePath is set and "otlp" otherwise.
+	Exporter *string `mapstructure:"exporter"`
+
+	// Endpoint is the host:port of the OTLP/HTTP collector.
+	Endpoint *string `mapstructure:"endpoint"`
+
+	// Insecure disables TLS when talking to the OTLP collector.
+	Insecure *bool `mapstructure:"insecure"`
+
+	// FilePath is the path spans are appended to when using the file exporter.
+	FilePath *string `mapstructure:"file_path"`
+
+	// ServiceName is the service.name resource attribute attached to all spans.
+	ServiceName *string `mapstructure:"service_name"`
+}
+
+// DefaultTracingConfig returns a configuration that is populated with the
+// default values.
+func DefaultTracingConfig() *TracingConfig {
+	return &TracingConfig{}
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *TracingConfig) Copy() *TracingConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o TracingConfig
+	o.Enabled = c.Enabled
+	o.Exporter = c.Exporter
+	o.Endpoint = c.Endpoint
+	o.Insecure = c.Insecure
+	o.FilePath = c.FilePath
+	o.ServiceName = c.ServiceName
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration, with values in the other configuration taking precedence.
+// Maps and slices are merged, most other values are overwritten. Complex
+// structs define their own merge functionality.
+func (c *TracingConfig) Merge(o *TracingConfig) *TracingConfig {
+	if c == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if o == nil {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+
+	if o.Enabled != nil {
+		r.Enabled = o.Enabled
+	}
+
+	if o.Exporter != nil {
+		r.Exporter = o.Exporter
+	}
+
+	if o.Endpoint != nil {
+		r.Endpoint = o.Endpoint
+	}
+
+	if o.Insecure != nil {
+		r.Insecure = o.Insecure
+	}
+
+	if o.FilePath != nil {
+		r.FilePath = o.FilePath
+	}
+
+	if o.ServiceName != nil {
+		r.ServiceName = o.ServiceName
+	}
+
+	return r
+}
+
+// Finalize ensures there no nil pointers.
+func (c *TracingConfig) Finalize() {
+	if c.Enabled == nil {
+		c.Enabled = Bool(StringPresent(c.Endpoint) || StringPresent(c.FilePath))
+	}
+
+	if c.Exporter == nil {
+		if StringPresent(c.FilePath) {
+			c.Exporter = String(TracingExporterFile)
+		} else {
+			c.Exporter = String(TracingExporterOTLP)
+		}
+	}
+
+	if c.Endpoint == nil {
+		c.Endpoint = String(DefaultTracingEndpoint)
+	}
+
+	if c.Insecure == nil {
+		c.Insecure = Bool(false)
+	}
+
+	if c.FilePath == nil {
+		c.FilePath = String("")
+	}
+
+	if c.ServiceName == nil {
+		c.ServiceName = String(DefaultTracingServiceName)
+	}
+}
+
+// GoString defines the printable version of this struct.
+func (c *TracingConfig) GoString() string {
+	if c == nil {
+		return "(*TracingConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&TracingConfig{"+
+		"Enabled:%s, "+
+		"Exporter:%s, "+
+		"Endpoint:%s, "+
+		"Insecure:%s, "+
+		"FilePath:%s, "+
+		"ServiceName:%s"+
+		"}",
+		BoolGoString(c.Enabled),
+		StringGoString(c.Exporter),
+		StringGoString(c.Endpoint),
+		BoolGoString(c.Insecure),
+		StringGoString(c.FilePath),
+		StringGoString(c.ServiceName),
+	)
+}
@@ -0,0 +1,210 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestTracingConfig_Copy(t *testing.T) {
+	cases := []struct {
+		name string
+		a    *TracingConfig
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&TracingConfig{},
+		},
+		{
+			"same_enabled",
+			&TracingConfig{
+				Enabled:     Bool(true),
+				Exporter:    String("otlp"),
+				Endpoint:    String("collector:4318"),
+				Insecure:    Bool(true),
+				FilePath:    String("/tmp/traces.json"),
+				ServiceName: String("ct"),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Copy()
+			if !reflect.DeepEqual(tc.a, r) {
+				t.Errorf("\nexp: %#v\nact: %#v", tc.a, r)
+			}
+		})
+	}
+}
+
+func TestTracingConfig_Merge(t *testing.T) {
+	cases := []struct {
+		name string
+		a    *TracingConfig
+		b    *TracingConfig
+		r    *TracingConfig
+	}{
+		{
+			"nil_a",
+			nil,
+			&TracingConfig{},
+			&TracingConfig{},
+		},
+		{
+			"nil_b",
+			&TracingConfig{},
+			nil,
+			&TracingConfig{},
+		},
+		{
+			"nil_both",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"empty",
+			&TracingConfig{},
+			&TracingConfig{},
+			&TracingConfig{},
+		},
+		{
+			"enabled_overrides",
+			&TracingConfig{Enabled: Bool(true)},
+			&TracingConfig{Enabled: Bool(false)},
+			&TracingConfig{Enabled: Bool(false)},
+		},
+		{
+			"enabled_empty_one",
+			&TracingConfig{Enabled: Bool(true)},
+			&TracingConfig{},
+			&TracingConfig{Enabled: Bool(true)},
+		},
+		{
+			"exporter_overrides",
+			&TracingConfig{Exporter: String("otlp")},
+			&TracingConfig{Exporter: String("file")},
+			&TracingConfig{Exporter: String("file")},
+		},
+		{
+			"endpoint_overrides",
+			&TracingConfig{Endpoint: String("a:4318")},
+			&TracingConfig{Endpoint: String("b:4318")},
+			&TracingConfig{Endpoint: String("b:4318")},
+		},
+		{
+			"endpoint_empty_one",
+			&TracingConfig{Endpoint: String("a:4318")},
+			&TracingConfig{},
+			&TracingConfig{Endpoint: String("a:4318")},
+		},
+		{
+			"insecure_overrides",
+			&TracingConfig{Insecure: Bool(true)},
+			&TracingConfig{Insecure: Bool(false)},
+			&TracingConfig{Insecure: Bool(false)},
+		},
+		{
+			"file_path_overrides",
+			&TracingConfig{FilePath: String("/a")},
+			&TracingConfig{FilePath: String("/b")},
+			&TracingConfig{FilePath: String("/b")},
+		},
+		{
+			"service_name_overrides",
+			&TracingConfig{ServiceName: String("a")},
+			&TracingConfig{ServiceName: String("b")},
+			&TracingConfig{ServiceName: String("b")},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Merge(tc.b)
+			if !reflect.DeepEqual(tc.r, r) {
+				t.Errorf("\nexp: %#v\nact: %#v", tc.r, r)
+			}
+		})
+	}
+}
+
+func TestTracingConfig_Finalize(t *testing.T) {
+	cases := []struct {
+		name string
+		i    *TracingConfig
+		r    *TracingConfig
+	}{
+		{
+			"empty",
+			&TracingConfig{},
+			&TracingConfig{
+				Enabled:     Bool(false),
+				Exporter:    String(TracingExporterOTLP),
+				Endpoint:    String(DefaultTracingEndpoint),
+				Insecure:    Bool(false),
+				FilePath:    String(""),
+				ServiceName: String(DefaultTracingServiceName),
+			},
+		},
+		{
+			"with_endpoint",
+			&TracingConfig{
+				Endpoint: String("collector:4318"),
+			},
+			&TracingConfig{
+				Enabled:     Bool(true),
+				Exporter:    String(TracingExporterOTLP),
+				Endpoint:    String("collector:4318"),
+				Insecure:    Bool(false),
+				FilePath:    String(""),
+				ServiceName: String(DefaultTracingServiceName),
+			},
+		},
+		{
+			"with_file_path",
+			&TracingConfig{
+				FilePath: String("/tmp/traces.json"),
+			},
+			&TracingConfig{
+				Enabled:     Bool(true),
+				Exporter:    String(TracingExporterFile),
+				Endpoint:    String(DefaultTracingEndpoint),
+				Insecure:    Bool(false),
+				FilePath:    String("/tmp/traces.json"),
+				ServiceName: String(DefaultTracingServiceName),
+			},
+		},
+		{
+			"explicit_exporter",
+			&TracingConfig{
+				Exporter: String(TracingExporterOTLP),
+				FilePath: String("/tmp/traces.json"),
+			},
+			&TracingConfig{
+				Enabled:     Bool(true),
+				Exporter:    String(TracingExporterOTLP),
+				Endpoint:    String(DefaultTracingEndpoint),
+				Insecure:    Bool(false),
+				FilePath:    String("/tmp/traces.json"),
+				ServiceName: String(DefaultTracingServiceName),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			tc.i.Finalize()
+			if !reflect.DeepEqual(tc.r, tc.i) {
+				t.Errorf("\nexp: %#v\nact: %#v", tc.r, tc.i)
+			}
+		})
+	}
+}
@@ -24,6 +24,10 @@ const (
 	// be unwrapped.
 	DefaultVaultUnwrapToken = false
 
+	// DefaultVaultRevokeOnShutdown is the default value for if the leases of
+	// Vault secrets should be revoked on shutdown.
+	DefaultVaultRevokeOnShutdown = false
+
 	// DefaultVaultRetryBase is the default value for the base time to use for
 	// exponential backoff.
 	DefaultVaultRetryBase = 250 * time.Millisecond
@@ -53,9 +57,16 @@ type VaultConfig struct {
 	// Address is the URI to the Vault server.
 	Address *string `mapstructure:"address"`
 
+	// Auth is the auth method to log in to Vault with when no token is given.
+	Auth *VaultAuthConfig `mapstructure:"auth"`
+
 	// Enabled controls whether the Vault integration is active.
 	Enabled *bool `mapstructure:"enabled"`
 
+	// LeaseStore is the configuration for storing the leases of secrets on
+	// disk, so they are reused after a restart.
+	LeaseStore *VaultLeaseStoreConfig `mapstructure:"lease_store"`
+
 	// Namespace is the Vault namespace to use for reading/writing secrets. This can
 	// also be set via the VAULT_NAMESPACE environment variable.
 	Namespace *string `mapstructure:"namespace"`
@@ -66,6 +77,10 @@ type VaultConfig struct {
 	// Retry is the configuration for specifying how to behave on failure.
 	Retry *RetryConfig `mapstructure:"retry"`
 
+	// RevokeOnShutdown revokes the leases of the secrets rendered in templates
+	// when Consul Template stops.
+	RevokeOnShutdown *bool `mapstructure:"revoke_on_shutdown"`
+
 	// SSL indicates we should use a secure connection while talking to Vault.
 	SSL *SSLConfig `mapstructure:"ssl"`
 
@@ -139,9 +154,11 @@ type VaultConfig struct {
 // default values.
 func DefaultVaultConfig() *VaultConfig {
 	v := &VaultConfig{
-		Retry:     DefaultRetryConfig(),
-		SSL:       DefaultSSLConfig(),
-		Transport: DefaultTransportConfig(),
+		Auth:       DefaultVaultAuthConfig(),
+		LeaseStore: DefaultVaultLeaseStoreConfig(),
+		Retry:      DefaultRetryConfig(),
+		SSL:        DefaultSSLConfig(),
+		Transport:  DefaultTransportConfig(),
 	}
 
 	// Force SSL when communicating with Vault.
@@ -159,8 +176,16 @@ func (c *VaultConfig) Copy() *VaultConfig {
 	var o VaultConfig
 	o.Address = c.Address
 
+	if c.Auth != nil {
+		o.Auth = c.Auth.Copy()
+	}
+
 	o.Enabled = c.Enabled
 
+	if c.LeaseStore != nil {
+		o.LeaseStore = c.LeaseStore.Copy()
+	}
+
 	o.Namespace = c.Namespace
 
 	o.RenewToken = c.RenewToken
@@ -169,6 +194,8 @@ func (c *VaultConfig) Copy() *VaultConfig {
 		o.Retry = c.Retry.Copy()
 	}
 
+	o.RevokeOnShutdown = c.RevokeOnShutdown
+
 	if c.SSL != nil {
 		o.SSL = c.SSL.Copy()
 	}
@@ -219,10 +246,18 @@ func (c *VaultConfig) Merge(o *VaultConfig) *VaultConfig {
 		r.Address = o.Address
 	}
 
+	if o.Auth != nil {
+		r.Auth = r.Auth.Merge(o.Auth)
+	}
+
 	if o.Enabled != nil {
 		r.Enabled = o.Enabled
 	}
 
+	if o.LeaseStore != nil {
+		r.LeaseStore = r.LeaseStore.Merge(o.LeaseStore)
+	}
+
 	if o.Namespace != nil {
 		r.Namespace = o.Namespace
 	}
@@ -235,6 +270,10 @@ func (c *VaultConfig) Merge(o *VaultConfig) *VaultConfig {
 		r.Retry = r.Retry.Merge(o.Retry)
 	}
 
+	if o.RevokeOnShutdown != nil {
+		r.RevokeOnShutdown = o.RevokeOnShutdown
+	}
+
 	if o.SSL != nil {
 		r.SSL = r.SSL.Merge(o.SSL)
 	}
@@ -307,6 +346,10 @@ func (c *VaultConfig) Finalize() {
 	}
 	c.Retry.Finalize()
 
+	if c.RevokeOnShutdown == nil {
+		c.RevokeOnShutdown = Bool(DefaultVaultRevokeOnShutdown)
+	}
+
 	// Vault has custom SSL settings
 	if c.SSL == nil {
 		c.SSL = DefaultSSLConfig()
@@ -361,12 +404,22 @@ func (c *VaultConfig) Finalize() {
 		c.Token = stringFromFile([]string{*c.VaultAgentTokenFile}, "")
 	}
 
-	// must be after c.Token setting, as default depends on that.
+	if c.Auth == nil {
+		c.Auth = DefaultVaultAuthConfig()
+	}
+	c.Auth.Finalize()
+
+	if c.LeaseStore == nil {
+		c.LeaseStore = DefaultVaultLeaseStoreConfig()
+	}
+	c.LeaseStore.Finalize()
+
+	// must be after c.Token and c.Auth setting, as default depends on that.
 	if c.RenewToken == nil {
 		default_renew := DefaultVaultRenewToken
 		if c.VaultAgentTokenFile != nil {
 			default_renew = false
-		} else if StringVal(c.Token) == "" {
+		} else if StringVal(c.Token) == "" && StringVal(c.Auth.Method) == "" {
 			default_renew = false
 		}
 		c.RenewToken = boolFromEnv([]string{
@@ -427,10 +480,13 @@ func (c *VaultConfig) GoString() string {
 
 	return fmt.Sprintf("&VaultConfig{"+
 		"Address:%s, "+
+		"Auth:%#v, "+
 		"Enabled:%s, "+
+		"LeaseStore:%#v, "+
 		"Namespace:%s,"+
 		"RenewToken:%s, "+
 		"Retry:%#v, "+
+		"RevokeOnShutdown:%s, "+
 		"SSL:%#v, "+
 		"TLSConfig:%t, "+
 		"Token:%t, "+
@@ -445,10 +501,13 @@ func (c *VaultConfig) GoString() string {
 		"K8SServiceMountPath:%s, "+
 		"}",
 		StringGoString(c.Address),
+		c.Auth,
 		BoolGoString(c.Enabled),
+		c.LeaseStore,
 		StringGoString(c.Namespace),
 		BoolGoString(c.RenewToken),
 		c.Retry,
+		BoolGoString(c.RevokeOnShutdown),
 		c.SSL,
 		c.TLSConfig != nil,
 		StringPresent(c.Token),
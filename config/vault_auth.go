@@ -0,0 +1,204 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import "fmt"
+
+// VaultAuthConfig is the configuration for logging in to Vault with an auth
+// method, when no token is given. The token is renewed like any other token
+// and, once it can no longer be renewed, Consul Template logs in again.
+type VaultAuthConfig struct {
+	// Method is the auth method to log in with. It is one of "approle", "jwt",
+	// "cert" or "userpass".
+	Method *string `mapstructure:"method"`
+
+	// MountPath is the path the auth method is mounted at. It defaults to the
+	// name of the method, so "approle" logs in at "auth/approle/login".
+	MountPath *string `mapstructure:"mount_path"`
+
+	// RoleIDFile and SecretIDFile are the paths of the files that contain the
+	// AppRole role_id and secret_id. The files are read on every login, so
+	// they can be replaced while Consul Template is running.
+	RoleIDFile   *string `mapstructure:"role_id_file"`
+	SecretIDFile *string `mapstructure:"secret_id_file"`
+
+	// SecretIDWrapped indicates that SecretIDFile contains a response-wrapping
+	// token for the secret_id instead of the secret_id itself.
+	SecretIDWrapped *bool `mapstructure:"secret_id_wrapped"`
+
+	// Role is the role to log in as for the jwt method, and the name of the
+	// certificate role for the cert method.
+	Role *string `mapstructure:"role"`
+
+	// JWTFile is the path of the file that contains the JWT for the jwt
+	// method.
+	JWTFile *string `mapstructure:"jwt_file"`
+
+	// Username and Password are the credentials for the userpass method. The
+	// password can also be read from PasswordFile.
+	Username     *string `mapstructure:"username"`
+	Password     *string `mapstructure:"password" json:"-"`
+	PasswordFile *string `mapstructure:"password_file"`
+}
+
+// DefaultVaultAuthConfig returns a configuration that is populated with the
+// default values.
+func DefaultVaultAuthConfig() *VaultAuthConfig {
+	return &VaultAuthConfig{}
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *VaultAuthConfig) Copy() *VaultAuthConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o VaultAuthConfig
+	o.Method = c.Method
+	o.MountPath = c.MountPath
+	o.RoleIDFile = c.RoleIDFile
+	o.SecretIDFile = c.SecretIDFile
+	o.SecretIDWrapped = c.SecretIDWrapped
+	o.Role = c.Role
+	o.JWTFile = c.JWTFile
+	o.Username = c.Username
+	o.Password = c.Password
+	o.PasswordFile = c.PasswordFile
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration, with values in the other configuration taking precedence.
+// Maps and slices are merged, most other values are overwritten. Complex
+// structs define their own merge functionality.
+func (c *VaultAuthConfig) Merge(o *VaultAuthConfig) *VaultAuthConfig {
+	if c == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if o == nil {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+
+	if o.Method != nil {
+		r.Method = o.Method
+	}
+
+	if o.MountPath != nil {
+		r.MountPath = o.MountPath
+	}
+
+	if o.RoleIDFile != nil {
+		r.RoleIDFile = o.RoleIDFile
+	}
+
+	if o.SecretIDFile != nil {
+		r.SecretIDFile = o.SecretIDFile
+	}
+
+	if o.SecretIDWrapped != nil {
+		r.SecretIDWrapped = o.SecretIDWrapped
+	}
+
+	if o.Role != nil {
+		r.Role = o.Role
+	}
+
+	if o.JWTFile != nil {
+		r.JWTFile = o.JWTFile
+	}
+
+	if o.Username != nil {
+		r.Username = o.Username
+	}
+
+	if o.Password != nil {
+		r.Password = o.Password
+	}
+
+	if o.PasswordFile != nil {
+		r.PasswordFile = o.PasswordFile
+	}
+
+	return r
+}
+
+// Finalize ensures there no nil pointers.
+func (c *VaultAuthConfig) Finalize() {
+	if c.Method == nil {
+		c.Method = String("")
+	}
+
+	if c.MountPath == nil {
+		c.MountPath = String(*c.Method)
+	}
+
+	if c.RoleIDFile == nil {
+		c.RoleIDFile = String("")
+	}
+
+	if c.SecretIDFile == nil {
+		c.SecretIDFile = String("")
+	}
+
+	if c.SecretIDWrapped == nil {
+		c.SecretIDWrapped = Bool(false)
+	}
+
+	if c.Role == nil {
+		c.Role = String("")
+	}
+
+	if c.JWTFile == nil {
+		c.JWTFile = String("")
+	}
+
+	if c.Username == nil {
+		c.Username = String("")
+	}
+
+	if c.Password == nil {
+		c.Password = String("")
+	}
+
+	if c.PasswordFile == nil {
+		c.PasswordFile = String("")
+	}
+}
+
+// GoString defines the printable version of this struct.
+func (c *VaultAuthConfig) GoString() string {
+	if c == nil {
+		return "(*VaultAuthConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&VaultAuthConfig{"+
+		"Method:%s, "+
+		"MountPath:%s, "+
+		"RoleIDFile:%s, "+
+		"SecretIDFile:%s, "+
+		"SecretIDWrapped:%s, "+
+		"Role:%s, "+
+		"JWTFile:%s, "+
+		"Username:%s, "+
+		"Password:%t, "+
+		"PasswordFile:%s"+
+		"}",
+		StringGoString(c.Method),
+		StringGoString(c.MountPath),
+		StringGoString(c.RoleIDFile),
+		StringGoString(c.SecretIDFile),
+		BoolGoString(c.SecretIDWrapped),
+		StringGoString(c.Role),
+		StringGoString(c.JWTFile),
+		StringGoString(c.Username),
+		StringPresent(c.Password),
+		StringGoString(c.PasswordFile),
+	)
+}
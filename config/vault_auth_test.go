@@ -0,0 +1,236 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestVaultAuthConfig_Copy(t *testing.T) {
+	cases := []struct {
+		name string
+		a    *VaultAuthConfig
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&VaultAuthConfig{},
+		},
+		{
+			"same_enabled",
+			&VaultAuthConfig{
+				Method:          String("approle"),
+				MountPath:       String("approle"),
+				RoleIDFile:      String("role_id"),
+				SecretIDFile:    String("secret_id"),
+				SecretIDWrapped: Bool(true),
+				Role:            String("role"),
+				JWTFile:         String("jwt"),
+				Username:        String("username"),
+				Password:        String("password"),
+				PasswordFile:    String("password_file"),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Copy()
+			if !reflect.DeepEqual(tc.a, r) {
+				t.Errorf("\nexp: %#v\nact: %#v", tc.a, r)
+			}
+		})
+	}
+}
+
+func TestVaultAuthConfig_Merge(t *testing.T) {
+	cases := []struct {
+		name string
+		a    *VaultAuthConfig
+		b    *VaultAuthConfig
+		r    *VaultAuthConfig
+	}{
+		{
+			"nil_a",
+			nil,
+			&VaultAuthConfig{},
+			&VaultAuthConfig{},
+		},
+		{
+			"nil_b",
+			&VaultAuthConfig{},
+			nil,
+			&VaultAuthConfig{},
+		},
+		{
+			"nil_both",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"empty",
+			&VaultAuthConfig{},
+			&VaultAuthConfig{},
+			&VaultAuthConfig{},
+		},
+		{
+			"method_overrides",
+			&VaultAuthConfig{Method: String("approle")},
+			&VaultAuthConfig{Method: String("jwt")},
+			&VaultAuthConfig{Method: String("jwt")},
+		},
+		{
+			"method_empty_one",
+			&VaultAuthConfig{Method: String("approle")},
+			&VaultAuthConfig{},
+			&VaultAuthConfig{Method: String("approle")},
+		},
+		{
+			"method_empty_two",
+			&VaultAuthConfig{},
+			&VaultAuthConfig{Method: String("approle")},
+			&VaultAuthConfig{Method: String("approle")},
+		},
+		{
+			"mount_path_overrides",
+			&VaultAuthConfig{MountPath: String("first")},
+			&VaultAuthConfig{MountPath: String("second")},
+			&VaultAuthConfig{MountPath: String("second")},
+		},
+		{
+			"role_id_file_overrides",
+			&VaultAuthConfig{RoleIDFile: String("first")},
+			&VaultAuthConfig{RoleIDFile: String("second")},
+			&VaultAuthConfig{RoleIDFile: String("second")},
+		},
+		{
+			"secret_id_file_overrides",
+			&VaultAuthConfig{SecretIDFile: String("first")},
+			&VaultAuthConfig{SecretIDFile: String("second")},
+			&VaultAuthConfig{SecretIDFile: String("second")},
+		},
+		{
+			"secret_id_wrapped_overrides",
+			&VaultAuthConfig{SecretIDWrapped: Bool(true)},
+			&VaultAuthConfig{SecretIDWrapped: Bool(false)},
+			&VaultAuthConfig{SecretIDWrapped: Bool(false)},
+		},
+		{
+			"role_overrides",
+			&VaultAuthConfig{Role: String("first")},
+			&VaultAuthConfig{Role: String("second")},
+			&VaultAuthConfig{Role: String("second")},
+		},
+		{
+			"jwt_file_overrides",
+			&VaultAuthConfig{JWTFile: String("first")},
+			&VaultAuthConfig{JWTFile: String("second")},
+			&VaultAuthConfig{JWTFile: String("second")},
+		},
+		{
+			"username_overrides",
+			&VaultAuthConfig{Username: String("first")},
+			&VaultAuthConfig{Username: String("second")},
+			&VaultAuthConfig{Username: String("second")},
+		},
+		{
+			"password_overrides",
+			&VaultAuthConfig{Password: String("first")},
+			&VaultAuthConfig{Password: String("second")},
+			&VaultAuthConfig{Password: String("second")},
+		},
+		{
+			"password_file_overrides",
+			&VaultAuthConfig{PasswordFile: String("first")},
+			&VaultAuthConfig{PasswordFile: String("second")},
+			&VaultAuthConfig{PasswordFile: String("second")},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Merge(tc.b)
+			if !reflect.DeepEqual(tc.r, r) {
+				t.Errorf("\nexp: %#v\nact: %#v", tc.r, r)
+			}
+		})
+	}
+}
+
+func TestVaultAuthConfig_Finalize(t *testing.T) {
+	cases := []struct {
+		name string
+		i    *VaultAuthConfig
+		r    *VaultAuthConfig
+	}{
+		{
+			"empty",
+			&VaultAuthConfig{},
+			&VaultAuthConfig{
+				Method:          String(""),
+				MountPath:       String(""),
+				RoleIDFile:      String(""),
+				SecretIDFile:    String(""),
+				SecretIDWrapped: Bool(false),
+				Role:            String(""),
+				JWTFile:         String(""),
+				Username:        String(""),
+				Password:        String(""),
+				PasswordFile:    String(""),
+			},
+		},
+		{
+			"with_method",
+			&VaultAuthConfig{
+				Method: String("userpass"),
+			},
+			&VaultAuthConfig{
+				Method:          String("userpass"),
+				MountPath:       String("userpass"),
+				RoleIDFile:      String(""),
+				SecretIDFile:    String(""),
+				SecretIDWrapped: Bool(false),
+				Role:            String(""),
+				JWTFile:         String(""),
+				Username:        String(""),
+				Password:        String(""),
+				PasswordFile:    String(""),
+			},
+		},
+		{
+			"with_mount_path",
+			&VaultAuthConfig{
+				Method:    String("jwt"),
+				MountPath: String("oidc"),
+			},
+			&VaultAuthConfig{
+				Method:          String("jwt"),
+				MountPath:       String("oidc"),
+				RoleIDFile:      String(""),
+				SecretIDFile:    String(""),
+				SecretIDWrapped: Bool(false),
+				Role:            String(""),
+				JWTFile:         String(""),
+				Username:        String(""),
+				Password:        String(""),
+				PasswordFile:    String(""),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			tc.i.Finalize()
+			if !reflect.DeepEqual(tc.r, tc.i) {
+				t.Errorf("\nexp: %#v\nact: %#v", tc.r, tc.i)
+			}
+		})
+	}
+}
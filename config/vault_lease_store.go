@@ -0,0 +1,108 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+)
+
+// VaultLeaseStoreConfig is the configuration for the on-disk store of Vault
+// secrets and their leases, which are reused after a restart.
+type VaultLeaseStoreConfig struct {
+	// Enabled controls whether leases are stored. Specifying a path also
+	// enables the store.
+	Enabled *bool `mapstructure:"enabled"`
+
+	// Path is the file the leases are written to.
+	Path *string `mapstructure:"path"`
+
+	// EncryptionKeyFile is the path to a file holding a base64 encoded 16, 24
+	// or 32 byte AES key. The store is always encrypted with AES-GCM, so the
+	// key is required.
+	EncryptionKeyFile *string `mapstructure:"encryption_key_file"`
+}
+
+// DefaultVaultLeaseStoreConfig returns a configuration that is populated with
+// the default values.
+func DefaultVaultLeaseStoreConfig() *VaultLeaseStoreConfig {
+	return &VaultLeaseStoreConfig{}
+}
+
+// Copy returns a deep copy of this configuration.
+func (c *VaultLeaseStoreConfig) Copy() *VaultLeaseStoreConfig {
+	if c == nil {
+		return nil
+	}
+
+	var o VaultLeaseStoreConfig
+	o.Enabled = c.Enabled
+	o.Path = c.Path
+	o.EncryptionKeyFile = c.EncryptionKeyFile
+	return &o
+}
+
+// Merge combines all values in this configuration with the values in the other
+// configuration, with values in the other configuration taking precedence.
+// Maps and slices are merged, most other values are overwritten. Complex
+// structs define their own merge functionality.
+func (c *VaultLeaseStoreConfig) Merge(o *VaultLeaseStoreConfig) *VaultLeaseStoreConfig {
+	if c == nil {
+		if o == nil {
+			return nil
+		}
+		return o.Copy()
+	}
+
+	if o == nil {
+		return c.Copy()
+	}
+
+	r := c.Copy()
+
+	if o.Enabled != nil {
+		r.Enabled = o.Enabled
+	}
+
+	if o.Path != nil {
+		r.Path = o.Path
+	}
+
+	if o.EncryptionKeyFile != nil {
+		r.EncryptionKeyFile = o.EncryptionKeyFile
+	}
+
+	return r
+}
+
+// Finalize ensures there no nil pointers.
+func (c *VaultLeaseStoreConfig) Finalize() {
+	if c.Enabled == nil {
+		c.Enabled = Bool(StringPresent(c.Path))
+	}
+
+	if c.Path == nil {
+		c.Path = String("")
+	}
+
+	if c.EncryptionKeyFile == nil {
+		c.EncryptionKeyFile = String("")
+	}
+}
+
+// GoString defines the printable version of this struct.
+func (c *VaultLeaseStoreConfig) GoString() string {
+	if c == nil {
+		return "(*VaultLeaseStoreConfig)(nil)"
+	}
+
+	return fmt.Sprintf("&VaultLeaseStoreConfig{"+
+		"Enabled:%s, "+
+		"Path:%s, "+
+		"EncryptionKeyFile:%s"+
+		"}",
+		BoolGoString(c.Enabled),
+		StringGoString(c.Path),
+		StringGoString(c.EncryptionKeyFile),
+	)
+}
@@ -0,0 +1,160 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestVaultLeaseStoreConfig_Copy(t *testing.T) {
+	cases := []struct {
+		name string
+		a    *VaultLeaseStoreConfig
+	}{
+		{
+			"nil",
+			nil,
+		},
+		{
+			"empty",
+			&VaultLeaseStoreConfig{},
+		},
+		{
+			"same_enabled",
+			&VaultLeaseStoreConfig{
+				Enabled:           Bool(true),
+				Path:              String("/var/lib/consul-template/leases"),
+				EncryptionKeyFile: String("/etc/consul-template/leases.key"),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Copy()
+			if !reflect.DeepEqual(tc.a, r) {
+				t.Errorf("\nexp: %#v\nact: %#v", tc.a, r)
+			}
+		})
+	}
+}
+
+func TestVaultLeaseStoreConfig_Merge(t *testing.T) {
+	cases := []struct {
+		name string
+		a    *VaultLeaseStoreConfig
+		b    *VaultLeaseStoreConfig
+		r    *VaultLeaseStoreConfig
+	}{
+		{
+			"nil_a",
+			nil,
+			&VaultLeaseStoreConfig{},
+			&VaultLeaseStoreConfig{},
+		},
+		{
+			"nil_b",
+			&VaultLeaseStoreConfig{},
+			nil,
+			&VaultLeaseStoreConfig{},
+		},
+		{
+			"nil_both",
+			nil,
+			nil,
+			nil,
+		},
+		{
+			"empty",
+			&VaultLeaseStoreConfig{},
+			&VaultLeaseStoreConfig{},
+			&VaultLeaseStoreConfig{},
+		},
+		{
+			"enabled_overrides",
+			&VaultLeaseStoreConfig{Enabled: Bool(true)},
+			&VaultLeaseStoreConfig{Enabled: Bool(false)},
+			&VaultLeaseStoreConfig{Enabled: Bool(false)},
+		},
+		{
+			"enabled_empty_one",
+			&VaultLeaseStoreConfig{Enabled: Bool(true)},
+			&VaultLeaseStoreConfig{},
+			&VaultLeaseStoreConfig{Enabled: Bool(true)},
+		},
+		{
+			"path_overrides",
+			&VaultLeaseStoreConfig{Path: String("a")},
+			&VaultLeaseStoreConfig{Path: String("b")},
+			&VaultLeaseStoreConfig{Path: String("b")},
+		},
+		{
+			"path_empty_two",
+			&VaultLeaseStoreConfig{},
+			&VaultLeaseStoreConfig{Path: String("b")},
+			&VaultLeaseStoreConfig{Path: String("b")},
+		},
+		{
+			"encryption_key_file_overrides",
+			&VaultLeaseStoreConfig{EncryptionKeyFile: String("a")},
+			&VaultLeaseStoreConfig{EncryptionKeyFile: String("b")},
+			&VaultLeaseStoreConfig{EncryptionKeyFile: String("b")},
+		},
+		{
+			"encryption_key_file_empty_one",
+			&VaultLeaseStoreConfig{EncryptionKeyFile: String("a")},
+			&VaultLeaseStoreConfig{},
+			&VaultLeaseStoreConfig{EncryptionKeyFile: String("a")},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			r := tc.a.Merge(tc.b)
+			if !reflect.DeepEqual(tc.r, r) {
+				t.Errorf("\nexp: %#v\nact: %#v", tc.r, r)
+			}
+		})
+	}
+}
+
+func TestVaultLeaseStoreConfig_Finalize(t *testing.T) {
+	cases := []struct {
+		name string
+		i    *VaultLeaseStoreConfig
+		r    *VaultLeaseStoreConfig
+	}{
+		{
+			"empty",
+			&VaultLeaseStoreConfig{},
+			&VaultLeaseStoreConfig{
+				Enabled:           Bool(false),
+				Path:              String(""),
+				EncryptionKeyFile: String(""),
+			},
+		},
+		{
+			"with_path",
+			&VaultLeaseStoreConfig{
+				Path: String("/tmp/leases"),
+			},
+			&VaultLeaseStoreConfig{
+				Enabled:           Bool(true),
+				Path:              String("/tmp/leases"),
+				EncryptionKeyFile: String(""),
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			tc.i.Finalize()
+			if !reflect.DeepEqual(tc.r, tc.i) {
+				t.Errorf("\nexp: %#v\nact: %#v", tc.r, tc.i)
+			}
+		})
+	}
+}
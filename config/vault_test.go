@@ -29,13 +29,16 @@ func TestVaultConfig_Copy(t *testing.T) {
 		{
 			"same_enabled",
 			&VaultConfig{
-				Address:    String("address"),
-				Enabled:    Bool(true),
-				Namespace:  String("foo"),
-				RenewToken: Bool(true),
-				Retry:      &RetryConfig{Enabled: Bool(true)},
-				SSL:        &SSLConfig{Enabled: Bool(true)},
-				Token:      String("token"),
+				Address:          String("address"),
+				Auth:             &VaultAuthConfig{Method: String("approle")},
+				Enabled:          Bool(true),
+				LeaseStore:       &VaultLeaseStoreConfig{Path: String("leases")},
+				Namespace:        String("foo"),
+				RenewToken:       Bool(true),
+				Retry:            &RetryConfig{Enabled: Bool(true)},
+				RevokeOnShutdown: Bool(true),
+				SSL:              &SSLConfig{Enabled: Bool(true)},
+				Token:            String("token"),
 				TLSConfig: &tls.Config{
 					ServerName: "server",
 				},
@@ -246,6 +249,24 @@ func TestVaultConfig_Merge(t *testing.T) {
 			&VaultConfig{RenewToken: Bool(true)},
 			&VaultConfig{RenewToken: Bool(true)},
 		},
+		{
+			"revoke_on_shutdown_overrides",
+			&VaultConfig{RevokeOnShutdown: Bool(true)},
+			&VaultConfig{RevokeOnShutdown: Bool(false)},
+			&VaultConfig{RevokeOnShutdown: Bool(false)},
+		},
+		{
+			"revoke_on_shutdown_empty_one",
+			&VaultConfig{RevokeOnShutdown: Bool(true)},
+			&VaultConfig{},
+			&VaultConfig{RevokeOnShutdown: Bool(true)},
+		},
+		{
+			"revoke_on_shutdown_empty_two",
+			&VaultConfig{},
+			&VaultConfig{RevokeOnShutdown: Bool(true)},
+			&VaultConfig{RevokeOnShutdown: Bool(true)},
+		},
 		{
 			"retry_overrides",
 			&VaultConfig{Retry: &RetryConfig{Enabled: Bool(true)}},
@@ -462,6 +483,42 @@ func TestVaultConfig_Merge(t *testing.T) {
 			&VaultConfig{K8SServiceMountPath: String("same")},
 			&VaultConfig{K8SServiceMountPath: String("same")},
 		},
+		{
+			"auth_merges",
+			&VaultConfig{Auth: &VaultAuthConfig{Method: String("approle")}},
+			&VaultConfig{Auth: &VaultAuthConfig{RoleIDFile: String("role_id")}},
+			&VaultConfig{Auth: &VaultAuthConfig{Method: String("approle"), RoleIDFile: String("role_id")}},
+		},
+		{
+			"auth_empty_one",
+			&VaultConfig{Auth: &VaultAuthConfig{Method: String("approle")}},
+			&VaultConfig{},
+			&VaultConfig{Auth: &VaultAuthConfig{Method: String("approle")}},
+		},
+		{
+			"auth_empty_two",
+			&VaultConfig{},
+			&VaultConfig{Auth: &VaultAuthConfig{Method: String("approle")}},
+			&VaultConfig{Auth: &VaultAuthConfig{Method: String("approle")}},
+		},
+		{
+			"lease_store_merges",
+			&VaultConfig{LeaseStore: &VaultLeaseStoreConfig{Path: String("leases")}},
+			&VaultConfig{LeaseStore: &VaultLeaseStoreConfig{EncryptionKeyFile: String("key")}},
+			&VaultConfig{LeaseStore: &VaultLeaseStoreConfig{Path: String("leases"), EncryptionKeyFile: String("key")}},
+		},
+		{
+			"lease_store_empty_one",
+			&VaultConfig{LeaseStore: &VaultLeaseStoreConfig{Path: String("leases")}},
+			&VaultConfig{},
+			&VaultConfig{LeaseStore: &VaultLeaseStoreConfig{Path: String("leases")}},
+		},
+		{
+			"lease_store_empty_two",
+			&VaultConfig{},
+			&VaultConfig{LeaseStore: &VaultLeaseStoreConfig{Path: String("leases")}},
+			&VaultConfig{LeaseStore: &VaultLeaseStoreConfig{Path: String("leases")}},
+		},
 	}
 
 	for i, tc := range cases {
@@ -486,10 +543,11 @@ func TestVaultConfig_Finalize(t *testing.T) {
 			nil,
 			&VaultConfig{},
 			&VaultConfig{
-				Address:    String(""),
-				Enabled:    Bool(false),
-				Namespace:  String(""),
-				RenewToken: Bool(false),
+				Address:          String(""),
+				Enabled:          Bool(false),
+				Namespace:        String(""),
+				RenewToken:       Bool(false),
+				RevokeOnShutdown: Bool(DefaultVaultRevokeOnShutdown),
 				Retry: &RetryConfig{
 					Backoff:    TimeDuration(DefaultRetryBackoff),
 					MaxBackoff: TimeDuration(DefaultRetryMaxBackoff),
@@ -524,6 +582,23 @@ func TestVaultConfig_Finalize(t *testing.T) {
 				K8SServiceAccountTokenPath: String(DefaultK8SServiceAccountTokenPath),
 				K8SServiceAccountToken:     String(""),
 				K8SServiceMountPath:        String(DefaultK8SServiceMountPath),
+				Auth: &VaultAuthConfig{
+					Method:          String(""),
+					MountPath:       String(""),
+					RoleIDFile:      String(""),
+					SecretIDFile:    String(""),
+					SecretIDWrapped: Bool(false),
+					Role:            String(""),
+					JWTFile:         String(""),
+					Username:        String(""),
+					Password:        String(""),
+					PasswordFile:    String(""),
+				},
+				LeaseStore: &VaultLeaseStoreConfig{
+					Enabled:           Bool(false),
+					Path:              String(""),
+					EncryptionKeyFile: String(""),
+				},
 			},
 		},
 		{
@@ -533,10 +608,11 @@ func TestVaultConfig_Finalize(t *testing.T) {
 				Address: String("address"),
 			},
 			&VaultConfig{
-				Address:    String("address"),
-				Enabled:    Bool(true),
-				Namespace:  String(""),
-				RenewToken: Bool(false),
+				Address:          String("address"),
+				Enabled:          Bool(true),
+				Namespace:        String(""),
+				RenewToken:       Bool(false),
+				RevokeOnShutdown: Bool(DefaultVaultRevokeOnShutdown),
 				Retry: &RetryConfig{
 					Backoff:    TimeDuration(DefaultRetryBackoff),
 					MaxBackoff: TimeDuration(DefaultRetryMaxBackoff),
@@ -571,6 +647,23 @@ func TestVaultConfig_Finalize(t *testing.T) {
 				K8SServiceAccountTokenPath: String(DefaultK8SServiceAccountTokenPath),
 				K8SServiceAccountToken:     String(""),
 				K8SServiceMountPath:        String(DefaultK8SServiceMountPath),
+				Auth: &VaultAuthConfig{
+					Method:          String(""),
+					MountPath:       String(""),
+					RoleIDFile:      String(""),
+					SecretIDFile:    String(""),
+					SecretIDWrapped: Bool(false),
+					Role:            String(""),
+					JWTFile:         String(""),
+					Username:        String(""),
+					Password:        String(""),
+					PasswordFile:    String(""),
+				},
+				LeaseStore: &VaultLeaseStoreConfig{
+					Enabled:           Bool(false),
+					Path:              String(""),
+					EncryptionKeyFile: String(""),
+				},
 			},
 		},
 		{
@@ -585,10 +678,11 @@ func TestVaultConfig_Finalize(t *testing.T) {
 				},
 			},
 			&VaultConfig{
-				Address:    String("address"),
-				Enabled:    Bool(true),
-				Namespace:  String(""),
-				RenewToken: Bool(false),
+				Address:          String("address"),
+				Enabled:          Bool(true),
+				Namespace:        String(""),
+				RenewToken:       Bool(false),
+				RevokeOnShutdown: Bool(DefaultVaultRevokeOnShutdown),
 				Retry: &RetryConfig{
 					Backoff:    TimeDuration(DefaultRetryBackoff),
 					MaxBackoff: TimeDuration(DefaultRetryMaxBackoff),
@@ -623,6 +717,23 @@ func TestVaultConfig_Finalize(t *testing.T) {
 				K8SServiceAccountTokenPath: String(DefaultK8SServiceAccountTokenPath),
 				K8SServiceAccountToken:     String(""),
 				K8SServiceMountPath:        String(DefaultK8SServiceMountPath),
+				Auth: &VaultAuthConfig{
+					Method:          String(""),
+					MountPath:       String(""),
+					RoleIDFile:      String(""),
+					SecretIDFile:    String(""),
+					SecretIDWrapped: Bool(false),
+					Role:            String(""),
+					JWTFile:         String(""),
+					Username:        String(""),
+					Password:        String(""),
+					PasswordFile:    String(""),
+				},
+				LeaseStore: &VaultLeaseStoreConfig{
+					Enabled:           Bool(false),
+					Path:              String(""),
+					EncryptionKeyFile: String(""),
+				},
 			},
 		},
 		{
@@ -642,10 +753,11 @@ func TestVaultConfig_Finalize(t *testing.T) {
 				},
 			},
 			&VaultConfig{
-				Address:    String("address"),
-				Enabled:    Bool(true),
-				Namespace:  String(""),
-				RenewToken: Bool(false),
+				Address:          String("address"),
+				Enabled:          Bool(true),
+				Namespace:        String(""),
+				RenewToken:       Bool(false),
+				RevokeOnShutdown: Bool(DefaultVaultRevokeOnShutdown),
 				Retry: &RetryConfig{
 					Backoff:    TimeDuration(DefaultRetryBackoff),
 					MaxBackoff: TimeDuration(DefaultRetryMaxBackoff),
@@ -680,6 +792,23 @@ func TestVaultConfig_Finalize(t *testing.T) {
 				K8SServiceAccountTokenPath: String(DefaultK8SServiceAccountTokenPath),
 				K8SServiceAccountToken:     String(""),
 				K8SServiceMountPath:        String(DefaultK8SServiceMountPath),
+				Auth: &VaultAuthConfig{
+					Method:          String(""),
+					MountPath:       String(""),
+					RoleIDFile:      String(""),
+					SecretIDFile:    String(""),
+					SecretIDWrapped: Bool(false),
+					Role:            String(""),
+					JWTFile:         String(""),
+					Username:        String(""),
+					Password:        String(""),
+					PasswordFile:    String(""),
+				},
+				LeaseStore: &VaultLeaseStoreConfig{
+					Enabled:           Bool(false),
+					Path:              String(""),
+					EncryptionKeyFile: String(""),
+				},
 			},
 		},
 		{
@@ -697,10 +826,11 @@ func TestVaultConfig_Finalize(t *testing.T) {
 				Address: String("address"),
 			},
 			&VaultConfig{
-				Address:    String("address"),
-				Enabled:    Bool(true),
-				Namespace:  String(""),
-				RenewToken: Bool(false),
+				Address:          String("address"),
+				Enabled:          Bool(true),
+				Namespace:        String(""),
+				RenewToken:       Bool(false),
+				RevokeOnShutdown: Bool(DefaultVaultRevokeOnShutdown),
 				Retry: &RetryConfig{
 					Backoff:    TimeDuration(DefaultRetryBackoff),
 					MaxBackoff: TimeDuration(DefaultRetryMaxBackoff),
@@ -735,6 +865,23 @@ func TestVaultConfig_Finalize(t *testing.T) {
 				K8SServiceAccountTokenPath: String(DefaultK8SServiceAccountTokenPath),
 				K8SServiceAccountToken:     String(""),
 				K8SServiceMountPath:        String(DefaultK8SServiceMountPath),
+				Auth: &VaultAuthConfig{
+					Method:          String(""),
+					MountPath:       String(""),
+					RoleIDFile:      String(""),
+					SecretIDFile:    String(""),
+					SecretIDWrapped: Bool(false),
+					Role:            String(""),
+					JWTFile:         String(""),
+					Username:        String(""),
+					Password:        String(""),
+					PasswordFile:    String(""),
+				},
+				LeaseStore: &VaultLeaseStoreConfig{
+					Enabled:           Bool(false),
+					Path:              String(""),
+					EncryptionKeyFile: String(""),
+				},
 			},
 		},
 		{
@@ -745,10 +892,11 @@ func TestVaultConfig_Finalize(t *testing.T) {
 				DefaultLeaseDuration: TimeDuration(1 * time.Minute),
 			},
 			&VaultConfig{
-				Address:    String("address"),
-				Enabled:    Bool(true),
-				Namespace:  String(""),
-				RenewToken: Bool(false),
+				Address:          String("address"),
+				Enabled:          Bool(true),
+				Namespace:        String(""),
+				RenewToken:       Bool(false),
+				RevokeOnShutdown: Bool(DefaultVaultRevokeOnShutdown),
 				Retry: &RetryConfig{
 					Backoff:    TimeDuration(DefaultRetryBackoff),
 					MaxBackoff: TimeDuration(DefaultRetryMaxBackoff),
@@ -783,6 +931,23 @@ func TestVaultConfig_Finalize(t *testing.T) {
 				K8SServiceAccountTokenPath: String(DefaultK8SServiceAccountTokenPath),
 				K8SServiceAccountToken:     String(""),
 				K8SServiceMountPath:        String(DefaultK8SServiceMountPath),
+				Auth: &VaultAuthConfig{
+					Method:          String(""),
+					MountPath:       String(""),
+					RoleIDFile:      String(""),
+					SecretIDFile:    String(""),
+					SecretIDWrapped: Bool(false),
+					Role:            String(""),
+					JWTFile:         String(""),
+					Username:        String(""),
+					Password:        String(""),
+					PasswordFile:    String(""),
+				},
+				LeaseStore: &VaultLeaseStoreConfig{
+					Enabled:           Bool(false),
+					Path:              String(""),
+					EncryptionKeyFile: String(""),
+				},
 			},
 		},
 		{
@@ -793,10 +958,11 @@ func TestVaultConfig_Finalize(t *testing.T) {
 				LeaseRenewalThreshold: Float64(0.70),
 			},
 			&VaultConfig{
-				Address:    String("address"),
-				Enabled:    Bool(true),
-				Namespace:  String(""),
-				RenewToken: Bool(false),
+				Address:          String("address"),
+				Enabled:          Bool(true),
+				Namespace:        String(""),
+				RenewToken:       Bool(false),
+				RevokeOnShutdown: Bool(DefaultVaultRevokeOnShutdown),
 				Retry: &RetryConfig{
 					Backoff:    TimeDuration(DefaultRetryBackoff),
 					MaxBackoff: TimeDuration(DefaultRetryMaxBackoff),
@@ -831,6 +997,23 @@ func TestVaultConfig_Finalize(t *testing.T) {
 				K8SServiceAccountTokenPath: String(DefaultK8SServiceAccountTokenPath),
 				K8SServiceAccountToken:     String(""),
 				K8SServiceMountPath:        String(DefaultK8SServiceMountPath),
+				Auth: &VaultAuthConfig{
+					Method:          String(""),
+					MountPath:       String(""),
+					RoleIDFile:      String(""),
+					SecretIDFile:    String(""),
+					SecretIDWrapped: Bool(false),
+					Role:            String(""),
+					JWTFile:         String(""),
+					Username:        String(""),
+					Password:        String(""),
+					PasswordFile:    String(""),
+				},
+				LeaseStore: &VaultLeaseStoreConfig{
+					Enabled:           Bool(false),
+					Path:              String(""),
+					EncryptionKeyFile: String(""),
+				},
 			},
 		},
 		{
@@ -843,10 +1026,11 @@ func TestVaultConfig_Finalize(t *testing.T) {
 				K8SServiceMountPath:        String("K8SServiceMountPath"),
 			},
 			&VaultConfig{
-				Address:    String(""),
-				Enabled:    Bool(false),
-				Namespace:  String(""),
-				RenewToken: Bool(false),
+				Address:          String(""),
+				Enabled:          Bool(false),
+				Namespace:        String(""),
+				RenewToken:       Bool(false),
+				RevokeOnShutdown: Bool(DefaultVaultRevokeOnShutdown),
 				Retry: &RetryConfig{
 					Backoff:    TimeDuration(DefaultRetryBackoff),
 					MaxBackoff: TimeDuration(DefaultRetryMaxBackoff),
@@ -881,6 +1065,23 @@ func TestVaultConfig_Finalize(t *testing.T) {
 				K8SServiceAccountTokenPath: String("K8SServiceAccountTokenPath"),
 				K8SServiceAccountToken:     String("K8SServiceAccountToken"),
 				K8SServiceMountPath:        String("K8SServiceMountPath"),
+				Auth: &VaultAuthConfig{
+					Method:          String(""),
+					MountPath:       String(""),
+					RoleIDFile:      String(""),
+					SecretIDFile:    String(""),
+					SecretIDWrapped: Bool(false),
+					Role:            String(""),
+					JWTFile:         String(""),
+					Username:        String(""),
+					Password:        String(""),
+					PasswordFile:    String(""),
+				},
+				LeaseStore: &VaultLeaseStoreConfig{
+					Enabled:           Bool(false),
+					Path:              String(""),
+					EncryptionKeyFile: String(""),
+				},
 			},
 		},
 	}
@@ -925,6 +1126,16 @@ func TestVaultConfig_TokenRenew(t *testing.T) {
 			},
 			[]string{"RenewToken"},
 		},
+		{
+			"base_renew_w_auth",
+			&VaultConfig{
+				Auth: &VaultAuthConfig{Method: String("approle")},
+			},
+			&VaultConfig{
+				RenewToken: Bool(true),
+			},
+			[]string{"RenewToken"},
+		},
 		{
 			"token_file_w_no_renew",
 			&VaultConfig{
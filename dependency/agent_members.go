@@ -0,0 +1,217 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"encoding/gob"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+)
+
+const (
+	// MembersQuerySegment is the query parameter for the LAN segment.
+	MembersQuerySegment = "segment"
+
+	poolLAN = "lan"
+	poolWAN = "wan"
+)
+
+var (
+	// Ensure implements
+	_ Dependency = (*AgentMembersQuery)(nil)
+
+	// AgentMembersQueryRe is the regular expression to use.
+	AgentMembersQueryRe = regexp.MustCompile(`\A(?P<pool>lan|wan)?` + queryRe + `\z`)
+
+	// AgentMembersQuerySleepTime is the amount of time to sleep between
+	// queries, since the endpoint does not support blocking queries.
+	AgentMembersQuerySleepTime = DefaultNonBlockingQuerySleepTime
+
+	// memberStatuses are the names of the gossip statuses of a member, see
+	// api.AgentMember.
+	memberStatuses = map[int]string{
+		0: "none",
+		1: "alive",
+		2: "leaving",
+		3: "left",
+		4: "failed",
+	}
+)
+
+func init() {
+	gob.Register([]*AgentMember{})
+}
+
+// AgentMember is a member of the LAN or WAN gossip pool of the local agent.
+type AgentMember struct {
+	Name string
+	Addr string
+	Port uint16
+	Tags map[string]string
+
+	// Status is one of none, alive, leaving, left or failed.
+	Status string
+
+	ProtocolMin uint8
+	ProtocolMax uint8
+	ProtocolCur uint8
+	DelegateMin uint8
+	DelegateMax uint8
+	DelegateCur uint8
+}
+
+// AgentMembersQuery is the dependency to query the gossip pool members known
+// to the local agent.
+type AgentMembersQuery struct {
+	stopCh chan struct{}
+
+	wan     bool
+	segment string
+}
+
+// NewAgentMembersQuery parses a string of the format pool?query, where the
+// pool is lan or wan and defaults to lan. The only supported query parameter
+// is the LAN segment, which may be "_all" for all segments.
+func NewAgentMembersQuery(s string) (*AgentMembersQuery, error) {
+	if !AgentMembersQueryRe.MatchString(s) {
+		return nil, fmt.Errorf("agent.members: invalid format: %q", s)
+	}
+
+	m := regexpMatch(AgentMembersQueryRe, s)
+
+	queryParams := url.Values{}
+	if queryRaw := m["query"]; queryRaw != "" {
+		var err error
+		queryParams, err = url.ParseQuery(queryRaw)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"agent.members: invalid query: %q: %s", queryRaw, err)
+		}
+		for key := range queryParams {
+			if key != MembersQuerySegment {
+				return nil, fmt.Errorf("agent.members: invalid query parameter key %q in query %q: supported keys: %s",
+					key, queryRaw, MembersQuerySegment)
+			}
+		}
+	}
+
+	wan := m["pool"] == poolWAN
+	segment := queryParams.Get(MembersQuerySegment)
+	if wan && segment != "" {
+		return nil, fmt.Errorf("agent.members: segments are not supported for the wan pool: %q", s)
+	}
+
+	return &AgentMembersQuery{
+		stopCh:  make(chan struct{}, 1),
+		wan:     wan,
+		segment: segment,
+	}, nil
+}
+
+// Fetch queries the Consul API defined by the given client and returns a slice
+// of AgentMember objects.
+func (d *AgentMembersQuery) Fetch(clients *ClientSet, opts *QueryOptions) (interface{}, *ResponseMetadata, error) {
+	opts = opts.Merge(&QueryOptions{})
+
+	log.Printf("[TRACE] %s: GET %s", d, &url.URL{
+		Path:     "/v1/agent/members",
+		RawQuery: opts.String(),
+	})
+
+	// The members endpoint does not support blocking queries, so after the
+	// first query, sleep before asking the agent again.
+	if opts.WaitIndex != 0 {
+		log.Printf("[TRACE] %s: long polling for %s", d, AgentMembersQuerySleepTime)
+
+		select {
+		case <-d.stopCh:
+			return nil, nil, ErrStopped
+		case <-time.After(AgentMembersQuerySleepTime):
+		}
+	}
+
+	members, err := clients.Consul().Agent().MembersOpts(api.MembersOpts{
+		WAN:     d.wan,
+		Segment: d.segment,
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, d.String())
+	}
+
+	log.Printf("[TRACE] %s: returned %d results", d, len(members))
+
+	list := make([]*AgentMember, 0, len(members))
+	for _, member := range members {
+		list = append(list, &AgentMember{
+			Name:        member.Name,
+			Addr:        member.Addr,
+			Port:        member.Port,
+			Tags:        member.Tags,
+			Status:      memberStatus(member.Status),
+			ProtocolMin: member.ProtocolMin,
+			ProtocolMax: member.ProtocolMax,
+			ProtocolCur: member.ProtocolCur,
+			DelegateMin: member.DelegateMin,
+			DelegateMax: member.DelegateMax,
+			DelegateCur: member.DelegateCur,
+		})
+	}
+
+	sort.Stable(ByMemberName(list))
+
+	return respWithMetadata(list)
+}
+
+func memberStatus(status int) string {
+	if s, ok := memberStatuses[status]; ok {
+		return s
+	}
+	return fmt.Sprintf("unknown(%d)", status)
+}
+
+// CanShare returns if this dependency is shareable.
+func (d *AgentMembersQuery) CanShare() bool {
+	return false
+}
+
+// String returns the human-friendly version of this dependency.
+func (d *AgentMembersQuery) String() string {
+	if d.wan {
+		return "agent.members(wan)"
+	}
+	if d.segment != "" {
+		return fmt.Sprintf("agent.members(lan?segment=%s)", d.segment)
+	}
+	return "agent.members(lan)"
+}
+
+// Stop terminates this dependency's fetch.
+func (d *AgentMembersQuery) Stop() {
+	close(d.stopCh)
+}
+
+// Type returns the type of this dependency.
+func (d *AgentMembersQuery) Type() Type {
+	return TypeConsul
+}
+
+// ByMemberName is a sortable slice of AgentMember.
+type ByMemberName []*AgentMember
+
+// Len, Swap, and Less are used to implement the sort.Sort interface.
+func (s ByMemberName) Len() int      { return len(s) }
+func (s ByMemberName) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s ByMemberName) Less(i, j int) bool {
+	if s[i].Name != s[j].Name {
+		return s[i].Name < s[j].Name
+	}
+	return s[i].Addr < s[j].Addr
+}
@@ -0,0 +1,181 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	AgentMembersQuerySleepTime = 50 * time.Millisecond
+}
+
+func TestNewAgentMembersQuery(t *testing.T) {
+	cases := []struct {
+		name string
+		i    string
+		exp  *AgentMembersQuery
+		err  bool
+	}{
+		{
+			"empty",
+			"",
+			&AgentMembersQuery{},
+			false,
+		},
+		{
+			"lan",
+			"lan",
+			&AgentMembersQuery{},
+			false,
+		},
+		{
+			"wan",
+			"wan",
+			&AgentMembersQuery{
+				wan: true,
+			},
+			false,
+		},
+		{
+			"segment",
+			"?segment=alpha",
+			&AgentMembersQuery{
+				segment: "alpha",
+			},
+			false,
+		},
+		{
+			"all_segments",
+			"lan?segment=_all",
+			&AgentMembersQuery{
+				segment: "_all",
+			},
+			false,
+		},
+		{
+			"wan_segment",
+			"wan?segment=alpha",
+			nil,
+			true,
+		},
+		{
+			"invalid_key",
+			"?ns=foo",
+			nil,
+			true,
+		},
+		{
+			"invalid_pool",
+			"foo",
+			nil,
+			true,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			act, err := NewAgentMembersQuery(tc.i)
+			if (err != nil) != tc.err {
+				t.Fatal(err)
+			}
+
+			if act != nil {
+				act.stopCh = nil
+			}
+
+			assert.Equal(t, tc.exp, act)
+		})
+	}
+}
+
+func TestAgentMembersQuery_Fetch(t *testing.T) {
+	cases := []struct {
+		name string
+		i    string
+	}{
+		{
+			"lan",
+			"",
+		},
+		{
+			"wan",
+			"wan",
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			d, err := NewAgentMembersQuery(tc.i)
+			require.NoError(t, err)
+
+			act, _, err := d.Fetch(testClients, nil)
+			require.NoError(t, err)
+
+			members := act.([]*AgentMember)
+			require.Len(t, members, 1)
+			assert.Equal(t, "alive", members[0].Status)
+			assert.Equal(t, "consul", members[0].Tags["role"])
+		})
+	}
+
+	t.Run("stops", func(t *testing.T) {
+		d, err := NewAgentMembersQuery("")
+		require.NoError(t, err)
+
+		errCh := make(chan error, 1)
+		go func() {
+			_, _, err := d.Fetch(testClients, &QueryOptions{WaitIndex: 10})
+			errCh <- err
+		}()
+
+		d.Stop()
+
+		select {
+		case err := <-errCh:
+			if err != ErrStopped {
+				t.Fatal(err)
+			}
+		case <-time.After(500 * time.Millisecond):
+			t.Errorf("did not stop")
+		}
+	})
+}
+
+func TestAgentMembersQuery_String(t *testing.T) {
+	cases := []struct {
+		name string
+		i    string
+		exp  string
+	}{
+		{
+			"empty",
+			"",
+			"agent.members(lan)",
+		},
+		{
+			"wan",
+			"wan",
+			"agent.members(wan)",
+		},
+		{
+			"segment",
+			"lan?segment=alpha",
+			"agent.members(lan?segment=alpha)",
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			d, err := NewAgentMembersQuery(tc.i)
+			require.NoError(t, err)
+			assert.Equal(t, tc.exp, d.String())
+		})
+	}
+}
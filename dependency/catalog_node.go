@@ -35,6 +35,7 @@ type CatalogNodeQuery struct {
 	name      string
 	namespace string
 	partition string
+	filter    string
 }
 
 // CatalogNode is a wrapper around the node and its services.
@@ -89,6 +90,7 @@ func (d *CatalogNodeQuery) Fetch(clients *ClientSet, opts *QueryOptions) (interf
 		Datacenter:      d.dc,
 		ConsulPartition: d.partition,
 		ConsulNamespace: d.namespace,
+		Filter:          d.filter,
 	})
 
 	// Grab the name
@@ -154,6 +156,12 @@ func (d *CatalogNodeQuery) Fetch(clients *ClientSet, opts *QueryOptions) (interf
 	return detail, rm, nil
 }
 
+// SetFilter sets a filter expression that Consul evaluates to filter the
+// results server-side.
+func (d *CatalogNodeQuery) SetFilter(filter string) {
+	d.filter = filter
+}
+
 // CanShare returns a boolean if this dependency is shareable.
 func (d *CatalogNodeQuery) CanShare() bool {
 	return false
@@ -171,6 +179,9 @@ func (d *CatalogNodeQuery) String() string {
 	if d.namespace != "" {
 		name = name + "@ns=" + d.namespace
 	}
+	if d.filter != "" {
+		name = name + "@filter=" + d.filter
+	}
 
 	if name == "" {
 		return "catalog.node"
@@ -44,6 +44,7 @@ type CatalogNodesQuery struct {
 	near      string
 	namespace string
 	partition string
+	filter    string
 }
 
 // NewCatalogNodesQuery parses the given string into a dependency. If the name is
@@ -82,6 +83,7 @@ func (d *CatalogNodesQuery) Fetch(clients *ClientSet, opts *QueryOptions) (inter
 		Near:            d.near,
 		ConsulPartition: d.partition,
 		ConsulNamespace: d.namespace,
+		Filter:          d.filter,
 	})
 
 	log.Printf("[TRACE] %s: GET %s", d, &url.URL{
@@ -120,6 +122,12 @@ func (d *CatalogNodesQuery) Fetch(clients *ClientSet, opts *QueryOptions) (inter
 	return nodes, rm, nil
 }
 
+// SetFilter sets a filter expression that Consul evaluates to filter the
+// results server-side.
+func (d *CatalogNodesQuery) SetFilter(filter string) {
+	d.filter = filter
+}
+
 // CanShare returns a boolean if this dependency is shareable.
 func (d *CatalogNodesQuery) CanShare() bool {
 	return true
@@ -137,6 +145,9 @@ func (d *CatalogNodesQuery) String() string {
 	if d.namespace != "" {
 		name = name + "@ns=" + d.namespace
 	}
+	if d.filter != "" {
+		name = name + "@filter=" + d.filter
+	}
 	if d.near != "" {
 		name = name + "~" + d.near
 	}
@@ -358,3 +358,25 @@ func TestCatalogNodesQuery_String(t *testing.T) {
 		})
 	}
 }
+
+func TestCatalogNodesQuery_filter(t *testing.T) {
+	d, err := NewCatalogNodesQuery("@dc1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter := fmt.Sprintf("Node == %q", testConsul.Config.NodeName)
+	d.SetFilter(filter)
+
+	assert.Equal(t, fmt.Sprintf("catalog.nodes(@dc1@filter=%s)", filter), d.String())
+
+	act, _, err := d.Fetch(testClients, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nodes := act.([]*Node)
+	if len(nodes) != 1 {
+		t.Fatalf("expected 1 node, got %d", len(nodes))
+	}
+	assert.Equal(t, testConsul.Config.NodeName, nodes[0].Node)
+}
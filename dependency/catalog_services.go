@@ -40,6 +40,7 @@ type CatalogServicesQuery struct {
 	dc        string
 	namespace string
 	partition string
+	filter    string
 }
 
 // NewCatalogServicesQuery parses a string of the format @dc.
@@ -77,6 +78,7 @@ func (d *CatalogServicesQuery) Fetch(clients *ClientSet, opts *QueryOptions) (in
 		Datacenter:      d.dc,
 		ConsulPartition: d.partition,
 		ConsulNamespace: d.namespace,
+		Filter:          d.filter,
 	}
 
 	opts = defaultOpts.Merge(opts)
@@ -111,6 +113,12 @@ func (d *CatalogServicesQuery) Fetch(clients *ClientSet, opts *QueryOptions) (in
 	return catalogServices, rm, nil
 }
 
+// SetFilter sets a filter expression that Consul evaluates to filter the
+// results server-side.
+func (d *CatalogServicesQuery) SetFilter(filter string) {
+	d.filter = filter
+}
+
 // CanShare returns a boolean if this dependency is shareable.
 func (d *CatalogServicesQuery) CanShare() bool {
 	return true
@@ -128,6 +136,9 @@ func (d *CatalogServicesQuery) String() string {
 	if d.namespace != "" {
 		name = name + "@ns=" + d.namespace
 	}
+	if d.filter != "" {
+		name = name + "@filter=" + d.filter
+	}
 
 	if len(name) == 0 {
 		return "catalog.services"
@@ -40,6 +40,12 @@ type consulClient struct {
 type vaultClient struct {
 	client     *vaultapi.Client
 	httpClient *http.Client
+
+	// auth is the auth method the client logged in with, if any.
+	auth vaultapi.AuthMethod
+
+	// leaseStore stores the secrets read with the client, if any.
+	leaseStore VaultLeaseStore
 }
 
 // nomadClient is a wrapper around a real Nomad API client.
@@ -110,6 +116,13 @@ type CreateVaultClientInput struct {
 	K8SServiceAccountToken     string
 	K8SServiceMountPath        string
 
+	// Auth is the auth method to log in with if no token is given.
+	Auth *VaultAuth
+
+	// LeaseStore, if set, stores the secrets read from Vault so their leases
+	// can be reused after a restart.
+	LeaseStore VaultLeaseStore
+
 	TransportCustomDialer        TransportDialer
 	TransportDialKeepAlive       time.Duration
 	TransportDialTimeout         time.Duration
@@ -371,6 +384,17 @@ func (c *ClientSet) CreateVaultClient(i *CreateVaultClientInput) error {
 		}
 	}
 
+	// Set token using the configured auth method.
+	var auth vaultapi.AuthMethod
+	if i.Auth != nil && i.Token == "" {
+		sec, err := vaultLogin(context.TODO(), client, i.Auth)
+		if err != nil {
+			return fmt.Errorf("client set: vault: %w", err)
+		}
+		auth = i.Auth
+		i.Token = sec.Auth.ClientToken
+	}
+
 	if i.Token != "" {
 		client.SetToken(i.Token)
 	}
@@ -380,6 +404,8 @@ func (c *ClientSet) CreateVaultClient(i *CreateVaultClientInput) error {
 	c.vault = &vaultClient{
 		client:     client,
 		httpClient: vaultConfig.HttpClient,
+		auth:       auth,
+		leaseStore: i.LeaseStore,
 	}
 	c.Unlock()
 
@@ -513,6 +539,31 @@ func (c *ClientSet) Vault() *vaultapi.Client {
 	return c.vault.client
 }
 
+// VaultLeaseStore returns the lease store of the Vault client, or nil if there
+// is none.
+func (c *ClientSet) VaultLeaseStore() VaultLeaseStore {
+	c.RLock()
+	defer c.RUnlock()
+
+	if c.vault == nil {
+		return nil
+	}
+	return c.vault.leaseStore
+}
+
+// VaultLogin logs in to Vault again with the auth method the Vault client was
+// created with, and sets the new token on the client.
+func (c *ClientSet) VaultLogin(ctx context.Context) (*vaultapi.Secret, error) {
+	c.RLock()
+	vault := c.vault
+	c.RUnlock()
+
+	if vault == nil || vault.auth == nil {
+		return nil, fmt.Errorf("client set: vault: no auth method to log in with")
+	}
+	return vaultLogin(ctx, vault.client, vault.auth)
+}
+
 // Nomad returns the Nomad client for this set.
 func (c *ClientSet) Nomad() *nomadapi.Client {
 	c.RLock()
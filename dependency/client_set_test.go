@@ -4,6 +4,7 @@
 package dependency
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"io"
@@ -12,6 +13,7 @@ import (
 	"net/http/httputil"
 	"net/url"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/hashicorp/consul-template/test"
@@ -194,6 +196,320 @@ func TestClientSet_K8SServiceTokenAuth(t *testing.T) {
 	})
 }
 
+func TestClientSet_VaultAuth(t *testing.T) {
+	t.Parallel()
+
+	validSecret := &api.Secret{Auth: &api.SecretAuth{ClientToken: vaultToken}}
+
+	loginPathCond := func(path string) func(r *http.Request) bool {
+		return func(r *http.Request) bool {
+			return r.URL.Path == "/v1/auth/"+path
+		}
+	}
+
+	t.Run("approle", func(t *testing.T) {
+		t.Parallel()
+
+		testServerAddr := newVaultMockReversedProxy(t, vaultMock{
+			HandleCond: loginPathCond("approle/login"),
+			HandleJSON: func(_ *http.Request, data map[string]interface{}) interface{} {
+				assert.Equal(t, "role_id", data["role_id"], data)
+				assert.Equal(t, "secret_id", data["secret_id"], data)
+
+				return validSecret
+			},
+		})
+
+		roleID := test.CreateTempfile(t, []byte("role_id\n"))
+		secretID := test.CreateTempfile(t, []byte("secret_id\n"))
+
+		clientSet := NewClientSet()
+		err := clientSet.CreateVaultClient(&CreateVaultClientInput{
+			Address:         testServerAddr,
+			ClientUserAgent: userAgent,
+			Auth: &VaultAuth{
+				Method:       VaultAuthMethodAppRole,
+				RoleIDFile:   roleID.Name(),
+				SecretIDFile: secretID.Name(),
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, vaultToken, clientSet.Vault().Token())
+	})
+
+	t.Run("approle_wrapped_secret_id", func(t *testing.T) {
+		t.Parallel()
+
+		testServerAddr := newVaultMockReversedProxy(t,
+			vaultMock{
+				HandleCond: func(r *http.Request) bool {
+					return r.URL.Path == "/v1/sys/wrapping/unwrap"
+				},
+				HandleJSON: func(r *http.Request, _ map[string]interface{}) interface{} {
+					assert.Equal(t, "wrapping_token", r.Header.Get("X-Vault-Token"))
+
+					return &api.Secret{Data: map[string]interface{}{"secret_id": "secret_id"}}
+				},
+			},
+			vaultMock{
+				HandleCond: loginPathCond("approle/login"),
+				HandleJSON: func(r *http.Request, data map[string]interface{}) interface{} {
+					assert.Empty(t, r.Header.Get("X-Vault-Token"))
+					assert.Equal(t, "secret_id", data["secret_id"], data)
+
+					return validSecret
+				},
+			},
+		)
+
+		roleID := test.CreateTempfile(t, []byte("role_id"))
+		secretID := test.CreateTempfile(t, []byte("wrapping_token"))
+
+		clientSet := NewClientSet()
+		err := clientSet.CreateVaultClient(&CreateVaultClientInput{
+			Address:         testServerAddr,
+			ClientUserAgent: userAgent,
+			Auth: &VaultAuth{
+				Method:          VaultAuthMethodAppRole,
+				RoleIDFile:      roleID.Name(),
+				SecretIDFile:    secretID.Name(),
+				SecretIDWrapped: true,
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, vaultToken, clientSet.Vault().Token())
+	})
+
+	t.Run("jwt", func(t *testing.T) {
+		t.Parallel()
+
+		testServerAddr := newVaultMockReversedProxy(t, vaultMock{
+			HandleCond: loginPathCond("oidc/login"),
+			HandleJSON: func(_ *http.Request, data map[string]interface{}) interface{} {
+				assert.Equal(t, "jwt", data["jwt"], data)
+				assert.Equal(t, "role", data["role"], data)
+
+				return validSecret
+			},
+		})
+
+		jwt := test.CreateTempfile(t, []byte("jwt"))
+
+		clientSet := NewClientSet()
+		err := clientSet.CreateVaultClient(&CreateVaultClientInput{
+			Address:         testServerAddr,
+			ClientUserAgent: userAgent,
+			Auth: &VaultAuth{
+				Method:    VaultAuthMethodJWT,
+				MountPath: "oidc",
+				Role:      "role",
+				JWTFile:   jwt.Name(),
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, vaultToken, clientSet.Vault().Token())
+	})
+
+	t.Run("cert", func(t *testing.T) {
+		t.Parallel()
+
+		testServerAddr := newVaultMockReversedProxy(t, vaultMock{
+			HandleCond: loginPathCond("cert/login"),
+			HandleJSON: func(_ *http.Request, data map[string]interface{}) interface{} {
+				assert.Equal(t, "web", data["name"], data)
+
+				return validSecret
+			},
+		})
+
+		clientSet := NewClientSet()
+		err := clientSet.CreateVaultClient(&CreateVaultClientInput{
+			Address:         testServerAddr,
+			ClientUserAgent: userAgent,
+			Auth: &VaultAuth{
+				Method: VaultAuthMethodCert,
+				Role:   "web",
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, vaultToken, clientSet.Vault().Token())
+	})
+
+	t.Run("userpass", func(t *testing.T) {
+		t.Parallel()
+
+		testServerAddr := newVaultMockReversedProxy(t, vaultMock{
+			HandleCond: loginPathCond("userpass/login/user"),
+			HandleJSON: func(_ *http.Request, data map[string]interface{}) interface{} {
+				assert.Equal(t, "password", data["password"], data)
+
+				return validSecret
+			},
+		})
+
+		password := test.CreateTempfile(t, []byte("password"))
+
+		clientSet := NewClientSet()
+		err := clientSet.CreateVaultClient(&CreateVaultClientInput{
+			Address:         testServerAddr,
+			ClientUserAgent: userAgent,
+			Auth: &VaultAuth{
+				Method:       VaultAuthMethodUserpass,
+				Username:     "user",
+				PasswordFile: password.Name(),
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, vaultToken, clientSet.Vault().Token())
+	})
+
+	t.Run("login_again", func(t *testing.T) {
+		t.Parallel()
+
+		var logins int
+		testServerAddr := newVaultMockReversedProxy(t, vaultMock{
+			HandleCond: loginPathCond("userpass/login/user"),
+			HandleJSON: func(r *http.Request, _ map[string]interface{}) interface{} {
+				assert.Empty(t, r.Header.Get("X-Vault-Token"))
+				logins++
+
+				return validSecret
+			},
+		})
+
+		clientSet := NewClientSet()
+		err := clientSet.CreateVaultClient(&CreateVaultClientInput{
+			Address:         testServerAddr,
+			ClientUserAgent: userAgent,
+			Auth: &VaultAuth{
+				Method:   VaultAuthMethodUserpass,
+				Username: "user",
+				Password: "password",
+			},
+		})
+		require.NoError(t, err)
+
+		clientSet.Vault().SetToken("expired")
+		_, err = clientSet.VaultLogin(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, vaultToken, clientSet.Vault().Token())
+		require.Equal(t, 2, logins)
+	})
+
+	t.Run("request_during_login", func(t *testing.T) {
+		t.Parallel()
+
+		// Requests sent while logging in again must still use the current
+		// token, not an empty one.
+		var logins atomic.Int32
+		loginStarted := make(chan struct{})
+		readDone := make(chan struct{})
+		testServerAddr := newVaultMockReversedProxy(t,
+			vaultMock{
+				HandleCond: loginPathCond("userpass/login/user"),
+				HandleJSON: func(r *http.Request, _ map[string]interface{}) interface{} {
+					assert.Empty(t, r.Header.Get("X-Vault-Token"))
+					if logins.Add(1) == 1 {
+						return validSecret
+					}
+					close(loginStarted)
+					<-readDone
+
+					return validSecret
+				},
+			},
+			vaultMock{
+				HandleCond: func(r *http.Request) bool {
+					return r.URL.Path == "/v1/secret/foo"
+				},
+				HandleJSON: func(r *http.Request, _ map[string]interface{}) interface{} {
+					assert.Equal(t, "current", r.Header.Get("X-Vault-Token"))
+
+					return &api.Secret{Data: map[string]interface{}{"foo": "bar"}}
+				},
+			},
+		)
+
+		clientSet := NewClientSet()
+		err := clientSet.CreateVaultClient(&CreateVaultClientInput{
+			Address:         testServerAddr,
+			ClientUserAgent: userAgent,
+			Auth: &VaultAuth{
+				Method:   VaultAuthMethodUserpass,
+				Username: "user",
+				Password: "password",
+			},
+		})
+		require.NoError(t, err)
+		clientSet.Vault().SetToken("current")
+
+		loginErr := make(chan error, 1)
+		go func() {
+			_, err := clientSet.VaultLogin(context.Background())
+			loginErr <- err
+		}()
+
+		select {
+		case <-loginStarted:
+		case err := <-loginErr:
+			t.Fatalf("login finished early: %v", err)
+		}
+		_, err = clientSet.Vault().Logical().Read("secret/foo")
+		token := clientSet.Vault().Token()
+		close(readDone)
+		require.NoError(t, err)
+		require.Equal(t, "current", token)
+
+		require.NoError(t, <-loginErr)
+		require.Equal(t, vaultToken, clientSet.Vault().Token())
+	})
+
+	t.Run("token_already_set", func(t *testing.T) {
+		t.Parallel()
+
+		clientSet := NewClientSet()
+		err := clientSet.CreateVaultClient(&CreateVaultClientInput{
+			Address: newVaultMockReversedProxy(t),
+			Token:   "token",
+			Auth: &VaultAuth{
+				Method:   VaultAuthMethodUserpass,
+				Username: "user",
+				Password: "password",
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "token", clientSet.Vault().Token())
+
+		_, err = clientSet.VaultLogin(context.Background())
+		require.Error(t, err)
+	})
+
+	t.Run("missing_file", func(t *testing.T) {
+		t.Parallel()
+
+		clientSet := NewClientSet()
+		err := clientSet.CreateVaultClient(&CreateVaultClientInput{
+			Address: newVaultMockReversedProxy(t),
+			Auth: &VaultAuth{
+				Method: VaultAuthMethodJWT,
+				Role:   "role",
+			},
+		})
+		require.ErrorContains(t, err, "jwt_file is required")
+	})
+
+	t.Run("unsupported_method", func(t *testing.T) {
+		t.Parallel()
+
+		clientSet := NewClientSet()
+		err := clientSet.CreateVaultClient(&CreateVaultClientInput{
+			Address: newVaultMockReversedProxy(t),
+			Auth:    &VaultAuth{Method: "ldap"},
+		})
+		require.ErrorContains(t, err, `unsupported auth method "ldap"`)
+	})
+}
+
 type vaultMock struct {
 	HandleCond func(r *http.Request) bool
 	HandleJSON func(r *http.Request, data map[string]interface{}) interface{}
@@ -0,0 +1,139 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"encoding/gob"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+)
+
+var (
+	// Ensure implements
+	_ Dependency = (*ConfigEntriesQuery)(nil)
+
+	// ConfigEntriesQueryRe is the regular expression to use.
+	ConfigEntriesQueryRe = regexp.MustCompile(`\A` + queryRe + dcRe + `\z`)
+)
+
+func init() {
+	gob.Register([]api.ConfigEntry{})
+}
+
+// ConfigEntriesQuery queries all config entries of a kind in Consul.
+type ConfigEntriesQuery struct {
+	stopCh chan struct{}
+
+	kind      string
+	dc        string
+	namespace string
+	partition string
+}
+
+// NewConfigEntriesQuery parses a string of the format ?query@dc into a
+// dependency on all config entries of the given kind, for example
+// "service-defaults".
+func NewConfigEntriesQuery(kind, s string) (*ConfigEntriesQuery, error) {
+	if _, err := api.MakeConfigEntry(kind, ""); err != nil {
+		return nil, fmt.Errorf("config_entries: %s", err)
+	}
+
+	if !ConfigEntriesQueryRe.MatchString(s) {
+		return nil, fmt.Errorf("config_entries: invalid format: %q", s)
+	}
+
+	m := regexpMatch(ConfigEntriesQueryRe, s)
+	queryParams, err := configEntryQueryOpts(m, "config_entries")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConfigEntriesQuery{
+		stopCh:    make(chan struct{}, 1),
+		kind:      kind,
+		dc:        m["dc"],
+		namespace: queryParams.Get(QueryNamespace),
+		partition: queryParams.Get(QueryPartition),
+	}, nil
+}
+
+// Fetch queries the Consul API defined by the given client and returns the
+// decoded config entries, sorted by name.
+func (d *ConfigEntriesQuery) Fetch(clients *ClientSet, opts *QueryOptions) (interface{}, *ResponseMetadata, error) {
+	select {
+	case <-d.stopCh:
+		return nil, nil, ErrStopped
+	default:
+	}
+
+	opts = opts.Merge(&QueryOptions{
+		Datacenter:      d.dc,
+		ConsulPartition: d.partition,
+		ConsulNamespace: d.namespace,
+	})
+
+	log.Printf("[TRACE] %s: GET %s", d, &url.URL{
+		Path:     "/v1/config/" + d.kind,
+		RawQuery: opts.String(),
+	})
+
+	entries, qm, err := clients.Consul().ConfigEntries().List(d.kind, opts.ToConsulOpts())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, d.String())
+	}
+
+	log.Printf("[TRACE] %s: returned %d results", d, len(entries))
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].GetName() < entries[j].GetName()
+	})
+
+	rm := &ResponseMetadata{
+		LastIndex:   qm.LastIndex,
+		LastContact: qm.LastContact,
+	}
+
+	return entries, rm, nil
+}
+
+// Kind returns the kind of the config entries.
+func (d *ConfigEntriesQuery) Kind() string {
+	return d.kind
+}
+
+// CanShare returns a boolean if this dependency is shareable.
+func (d *ConfigEntriesQuery) CanShare() bool {
+	return true
+}
+
+// Stop halts the dependency's fetch function.
+func (d *ConfigEntriesQuery) Stop() {
+	close(d.stopCh)
+}
+
+// String returns the human-friendly version of this dependency.
+func (d *ConfigEntriesQuery) String() string {
+	name := d.kind
+	if d.dc != "" {
+		name = name + "@" + d.dc
+	}
+	if d.partition != "" {
+		name = name + "@partition=" + d.partition
+	}
+	if d.namespace != "" {
+		name = name + "@ns=" + d.namespace
+	}
+	return fmt.Sprintf("config_entries(%s)", name)
+}
+
+// Type returns the type of this dependency.
+func (d *ConfigEntriesQuery) Type() Type {
+	return TypeConsul
+}
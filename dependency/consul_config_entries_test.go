@@ -0,0 +1,136 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConfigEntriesQuery(t *testing.T) {
+	cases := []struct {
+		name string
+		kind string
+		i    string
+		exp  *ConfigEntriesQuery
+		err  bool
+	}{
+		{
+			"invalid_kind",
+			"nope",
+			"",
+			nil,
+			true,
+		},
+		{
+			"sameness_group",
+			api.ServiceDefaults,
+			"?sameness-group=foo",
+			nil,
+			true,
+		},
+		{
+			"empty",
+			api.ServiceDefaults,
+			"",
+			&ConfigEntriesQuery{
+				kind: api.ServiceDefaults,
+			},
+			false,
+		},
+		{
+			"query_dc",
+			api.ServiceResolver,
+			"?ns=foo&partition=bar@dc1",
+			&ConfigEntriesQuery{
+				kind:      api.ServiceResolver,
+				dc:        "dc1",
+				namespace: "foo",
+				partition: "bar",
+			},
+			false,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			act, err := NewConfigEntriesQuery(tc.kind, tc.i)
+			if (err != nil) != tc.err {
+				t.Fatal(err)
+			}
+
+			if act != nil {
+				act.stopCh = nil
+			}
+
+			assert.Equal(t, tc.exp, act)
+		})
+	}
+}
+
+func TestConfigEntriesQuery_Fetch(t *testing.T) {
+	for _, name := range []string{"config-entries-b", "config-entries-a"} {
+		entry := &api.ServiceConfigEntry{
+			Kind:     api.ServiceDefaults,
+			Name:     name,
+			Protocol: "grpc",
+		}
+		if _, _, err := testClients.Consul().ConfigEntries().Set(entry, nil); err != nil {
+			t.Fatal(err)
+		}
+		defer testClients.Consul().ConfigEntries().Delete(entry.Kind, entry.Name, nil)
+	}
+
+	d, err := NewConfigEntriesQuery(api.ServiceDefaults, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	act, _, err := d.Fetch(testClients, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, entry := range act.([]api.ConfigEntry) {
+		names = append(names, entry.GetName())
+	}
+	assert.Subset(t, names, []string{"config-entries-a", "config-entries-b"})
+	assert.IsNonDecreasing(t, names)
+}
+
+func TestConfigEntriesQuery_String(t *testing.T) {
+	cases := []struct {
+		name string
+		kind string
+		i    string
+		exp  string
+	}{
+		{
+			"empty",
+			api.ServiceDefaults,
+			"",
+			"config_entries(service-defaults)",
+		},
+		{
+			"query_dc",
+			api.ServiceResolver,
+			"?ns=foo@dc1",
+			"config_entries(service-resolver@dc1@ns=foo)",
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			d, err := NewConfigEntriesQuery(tc.kind, tc.i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.exp, d.String())
+		})
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"encoding/gob"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+)
+
+var (
+	// Ensure implements
+	_ Dependency = (*ConfigEntryQuery)(nil)
+
+	// ConfigEntryQueryRe is the regular expression to use.
+	ConfigEntryQueryRe = regexp.MustCompile(`\A` + nodeNameRe + queryRe + dcRe + `\z`)
+)
+
+func init() {
+	// Every kind returned by api.MakeConfigEntry is registered so that any
+	// config entry can be cached.
+	gob.Register(&api.ServiceConfigEntry{})
+	gob.Register(&api.ProxyConfigEntry{})
+	gob.Register(&api.ServiceRouterConfigEntry{})
+	gob.Register(&api.ServiceSplitterConfigEntry{})
+	gob.Register(&api.ServiceResolverConfigEntry{})
+	gob.Register(&api.IngressGatewayConfigEntry{})
+	gob.Register(&api.TerminatingGatewayConfigEntry{})
+	gob.Register(&api.ServiceIntentionsConfigEntry{})
+	gob.Register(&api.MeshConfigEntry{})
+	gob.Register(&api.ExportedServicesConfigEntry{})
+	gob.Register(&api.SamenessGroupConfigEntry{})
+	gob.Register(&api.APIGatewayConfigEntry{})
+	gob.Register(&api.TCPRouteConfigEntry{})
+	gob.Register(&api.FileSystemCertificateConfigEntry{})
+	gob.Register(&api.InlineCertificateConfigEntry{})
+	gob.Register(&api.HTTPRouteConfigEntry{})
+	gob.Register(&api.RateLimitIPConfigEntry{})
+	gob.Register(&api.GlobalRateLimitConfigEntry{})
+	gob.Register(&api.JWTProviderConfigEntry{})
+}
+
+// ConfigEntryQuery queries a single config entry in Consul.
+type ConfigEntryQuery struct {
+	stopCh chan struct{}
+
+	kind      string
+	name      string
+	dc        string
+	namespace string
+	partition string
+}
+
+// NewConfigEntryQuery parses a string of the format name?query@dc into a
+// dependency on the config entry of the given kind, for example
+// "service-resolver".
+func NewConfigEntryQuery(kind, s string) (*ConfigEntryQuery, error) {
+	if _, err := api.MakeConfigEntry(kind, ""); err != nil {
+		return nil, fmt.Errorf("config_entry: %s", err)
+	}
+
+	if !ConfigEntryQueryRe.MatchString(s) {
+		return nil, fmt.Errorf("config_entry: invalid format: %q", s)
+	}
+
+	m := regexpMatch(ConfigEntryQueryRe, s)
+	queryParams, err := configEntryQueryOpts(m, "config_entry")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ConfigEntryQuery{
+		stopCh:    make(chan struct{}, 1),
+		kind:      kind,
+		name:      m["name"],
+		dc:        m["dc"],
+		namespace: queryParams.Get(QueryNamespace),
+		partition: queryParams.Get(QueryPartition),
+	}, nil
+}
+
+// Fetch queries the Consul API defined by the given client and returns the
+// decoded config entry, or nil if it does not exist.
+func (d *ConfigEntryQuery) Fetch(clients *ClientSet, opts *QueryOptions) (interface{}, *ResponseMetadata, error) {
+	select {
+	case <-d.stopCh:
+		return nil, nil, ErrStopped
+	default:
+	}
+
+	opts = opts.Merge(&QueryOptions{
+		Datacenter:      d.dc,
+		ConsulPartition: d.partition,
+		ConsulNamespace: d.namespace,
+	})
+
+	log.Printf("[TRACE] %s: GET %s", d, &url.URL{
+		Path:     "/v1/config/" + d.kind + "/" + d.name,
+		RawQuery: opts.String(),
+	})
+
+	entry, qm, err := clients.Consul().ConfigEntries().Get(d.kind, d.name, opts.ToConsulOpts())
+	if err != nil {
+		var statusErr api.StatusError
+		if !errors.As(err, &statusErr) || statusErr.Code != http.StatusNotFound {
+			return nil, nil, errors.Wrap(err, d.String())
+		}
+
+		// The Consul API package does not return the QueryMeta of a 404, so
+		// block on the entries of this kind instead to learn when the entry
+		// is created.
+		_, qm, err = clients.Consul().ConfigEntries().List(d.kind, opts.ToConsulOpts())
+		if err != nil {
+			return nil, nil, errors.Wrap(err, d.String())
+		}
+
+		log.Printf("[TRACE] %s: returned nil", d)
+		return nil, &ResponseMetadata{
+			LastIndex:   qm.LastIndex,
+			LastContact: qm.LastContact,
+		}, nil
+	}
+
+	log.Printf("[TRACE] %s: returned response", d)
+
+	rm := &ResponseMetadata{
+		LastIndex:   qm.LastIndex,
+		LastContact: qm.LastContact,
+	}
+
+	return entry, rm, nil
+}
+
+// Kind returns the kind of the config entry.
+func (d *ConfigEntryQuery) Kind() string {
+	return d.kind
+}
+
+// CanShare returns a boolean if this dependency is shareable.
+func (d *ConfigEntryQuery) CanShare() bool {
+	return true
+}
+
+// Stop halts the dependency's fetch function.
+func (d *ConfigEntryQuery) Stop() {
+	close(d.stopCh)
+}
+
+// String returns the human-friendly version of this dependency.
+func (d *ConfigEntryQuery) String() string {
+	name := d.kind + "/" + d.name
+	if d.dc != "" {
+		name = name + "@" + d.dc
+	}
+	if d.partition != "" {
+		name = name + "@partition=" + d.partition
+	}
+	if d.namespace != "" {
+		name = name + "@ns=" + d.namespace
+	}
+	return fmt.Sprintf("config_entry(%s)", name)
+}
+
+// Type returns the type of this dependency.
+func (d *ConfigEntryQuery) Type() Type {
+	return TypeConsul
+}
+
+// configEntryQueryOpts parses the query parameters of a config entry query,
+// which only support a namespace and partition.
+func configEntryQueryOpts(m map[string]string, endpointLabel string) (url.Values, error) {
+	queryParams, err := GetConsulQueryOpts(m, endpointLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range []string{QueryPeer, QuerySamenessGroup} {
+		if queryParams.Get(key) != "" {
+			return nil, fmt.Errorf("%s: unsupported query parameter %q", endpointLabel, key)
+		}
+	}
+	return queryParams, nil
+}
@@ -0,0 +1,206 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConfigEntryQuery(t *testing.T) {
+	cases := []struct {
+		name string
+		kind string
+		i    string
+		exp  *ConfigEntryQuery
+		err  bool
+	}{
+		{
+			"invalid_kind",
+			"nope",
+			"web",
+			nil,
+			true,
+		},
+		{
+			"empty",
+			api.ServiceDefaults,
+			"",
+			nil,
+			true,
+		},
+		{
+			"peer",
+			api.ServiceDefaults,
+			"web?peer=foo",
+			nil,
+			true,
+		},
+		{
+			"name",
+			api.ServiceDefaults,
+			"web",
+			&ConfigEntryQuery{
+				kind: api.ServiceDefaults,
+				name: "web",
+			},
+			false,
+		},
+		{
+			"name_query_dc",
+			api.ServiceResolver,
+			"web?ns=foo&partition=bar@dc1",
+			&ConfigEntryQuery{
+				kind:      api.ServiceResolver,
+				name:      "web",
+				dc:        "dc1",
+				namespace: "foo",
+				partition: "bar",
+			},
+			false,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			act, err := NewConfigEntryQuery(tc.kind, tc.i)
+			if (err != nil) != tc.err {
+				t.Fatal(err)
+			}
+
+			if act != nil {
+				act.stopCh = nil
+			}
+
+			assert.Equal(t, tc.exp, act)
+		})
+	}
+}
+
+func TestConfigEntryQuery_Fetch(t *testing.T) {
+	entry := &api.ServiceConfigEntry{
+		Kind:     api.ServiceDefaults,
+		Name:     "config-entry-web",
+		Protocol: "http",
+	}
+	if _, _, err := testClients.Consul().ConfigEntries().Set(entry, nil); err != nil {
+		t.Fatal(err)
+	}
+	defer testClients.Consul().ConfigEntries().Delete(entry.Kind, entry.Name, nil)
+
+	t.Run("exists", func(t *testing.T) {
+		d, err := NewConfigEntryQuery(api.ServiceDefaults, "config-entry-web")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		act, _, err := d.Fetch(testClients, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, "http", act.(*api.ServiceConfigEntry).Protocol)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		d, err := NewConfigEntryQuery(api.ServiceDefaults, "config-entry-missing")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		act, rm, err := d.Fetch(testClients, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Nil(t, act)
+		assert.NotZero(t, rm.LastIndex)
+	})
+}
+
+func TestConfigEntryQuery_String(t *testing.T) {
+	cases := []struct {
+		name string
+		kind string
+		i    string
+		exp  string
+	}{
+		{
+			"name",
+			api.ServiceDefaults,
+			"web",
+			"config_entry(service-defaults/web)",
+		},
+		{
+			"name_query_dc",
+			api.ServiceResolver,
+			"web?ns=foo&partition=bar@dc1",
+			"config_entry(service-resolver/web@dc1@partition=bar@ns=foo)",
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			d, err := NewConfigEntryQuery(tc.kind, tc.i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.exp, d.String())
+		})
+	}
+}
+
+func TestConfigEntryQuery_gob(t *testing.T) {
+	kinds := []string{
+		api.ServiceDefaults,
+		api.ProxyDefaults,
+		api.ServiceRouter,
+		api.ServiceSplitter,
+		api.ServiceResolver,
+		api.IngressGateway,
+		api.TerminatingGateway,
+		api.ServiceIntentions,
+		api.MeshConfig,
+		api.ExportedServices,
+		api.SamenessGroup,
+		api.APIGateway,
+		api.TCPRoute,
+		api.FileSystemCertificate,
+		api.InlineCertificate,
+		api.HTTPRoute,
+		api.RateLimitIPConfig,
+		api.RateLimit,
+		api.JWTProvider,
+	}
+
+	for _, kind := range kinds {
+		t.Run(kind, func(t *testing.T) {
+			entry, err := api.MakeConfigEntry(kind, "web")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The cache encodes the data of each dependency as an interface.
+			var buf bytes.Buffer
+			var value interface{} = entry
+			if err := gob.NewEncoder(&buf).Encode(&value); err != nil {
+				t.Fatal(err)
+			}
+			var decoded interface{}
+			if err := gob.NewDecoder(&buf).Decode(&decoded); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(decoded, value) {
+				t.Errorf("expected %#v to be %#v", decoded, value)
+			}
+		})
+	}
+}
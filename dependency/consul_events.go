@@ -0,0 +1,155 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"encoding/gob"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// Ensure implements
+	_ Dependency = (*EventListQuery)(nil)
+
+	// EventListQueryRe is the regular expression to use.
+	EventListQueryRe = regexp.MustCompile(`\A(?P<name>[[:word:]\.\-\_]+)?\z`)
+)
+
+func init() {
+	gob.Register([]*UserEvent{})
+}
+
+// UserEvent is a user event fired with "consul event".
+type UserEvent struct {
+	ID      string
+	Name    string
+	Payload string
+
+	// NodeFilter, ServiceFilter and TagFilter are the filters the event was
+	// fired with.
+	NodeFilter    string
+	ServiceFilter string
+	TagFilter     string
+
+	Version int
+
+	// LTime is the Lamport time of the event, which orders events fired
+	// across the cluster.
+	LTime uint64
+}
+
+// EventListQuery is the representation of a query for the most recent user
+// events received by the local agent.
+type EventListQuery struct {
+	stopCh chan struct{}
+
+	name string
+
+	// The index of the event list endpoint is a hash of the ID of the last
+	// event, so it is not monotonic. consulIndex is the last index returned
+	// by Consul and index is the monotonic index returned to the view in its
+	// place.
+	consulIndex uint64
+	index       uint64
+}
+
+// NewEventListQuery parses a string into a dependency. If the name is empty,
+// events of all names are returned.
+func NewEventListQuery(s string) (*EventListQuery, error) {
+	if !EventListQueryRe.MatchString(s) {
+		return nil, fmt.Errorf("event.list: invalid format: %q", s)
+	}
+
+	m := regexpMatch(EventListQueryRe, s)
+	return &EventListQuery{
+		stopCh: make(chan struct{}, 1),
+		name:   m["name"],
+	}, nil
+}
+
+// Fetch queries the Consul API defined by the given client and returns a slice
+// of UserEvent objects, oldest first.
+func (d *EventListQuery) Fetch(clients *ClientSet, opts *QueryOptions) (interface{}, *ResponseMetadata, error) {
+	select {
+	case <-d.stopCh:
+		return nil, nil, ErrStopped
+	default:
+	}
+
+	opts = opts.Merge(&QueryOptions{})
+
+	// Translate the index of the view back to the index of Consul. If the view
+	// has reset its index, query without blocking.
+	consulOpts := opts.ToConsulOpts()
+	consulOpts.WaitIndex = 0
+	if opts.WaitIndex != 0 && opts.WaitIndex == d.index {
+		consulOpts.WaitIndex = d.consulIndex
+	}
+
+	log.Printf("[TRACE] %s: GET %s", d, &url.URL{
+		Path:     "/v1/event/list",
+		RawQuery: opts.String(),
+	})
+
+	events, qm, err := clients.Consul().Event().List(d.name, consulOpts)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, d.String())
+	}
+
+	log.Printf("[TRACE] %s: returned %d results", d, len(events))
+
+	if d.index == 0 || qm.LastIndex != d.consulIndex {
+		d.consulIndex = qm.LastIndex
+		d.index++
+	}
+
+	list := make([]*UserEvent, 0, len(events))
+	for _, event := range events {
+		list = append(list, &UserEvent{
+			ID:            event.ID,
+			Name:          event.Name,
+			Payload:       string(event.Payload),
+			NodeFilter:    event.NodeFilter,
+			ServiceFilter: event.ServiceFilter,
+			TagFilter:     event.TagFilter,
+			Version:       event.Version,
+			LTime:         event.LTime,
+		})
+	}
+
+	rm := &ResponseMetadata{
+		LastIndex:   d.index,
+		LastContact: qm.LastContact,
+	}
+
+	return list, rm, nil
+}
+
+// CanShare returns a boolean if this dependency is shareable.
+func (d *EventListQuery) CanShare() bool {
+	return true
+}
+
+// Stop halts the dependency's fetch function.
+func (d *EventListQuery) Stop() {
+	close(d.stopCh)
+}
+
+// String returns the human-friendly version of this dependency.
+func (d *EventListQuery) String() string {
+	if d.name == "" {
+		return "event.list"
+	}
+	return fmt.Sprintf("event.list(%s)", d.name)
+}
+
+// Type returns the type of this dependency.
+func (d *EventListQuery) Type() Type {
+	return TypeConsul
+}
@@ -0,0 +1,136 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEventListQuery(t *testing.T) {
+	cases := []struct {
+		name string
+		i    string
+		exp  *EventListQuery
+		err  bool
+	}{
+		{
+			"empty",
+			"",
+			&EventListQuery{},
+			false,
+		},
+		{
+			"name",
+			"deploy",
+			&EventListQuery{
+				name: "deploy",
+			},
+			false,
+		},
+		{
+			"invalid",
+			"deploy@dc1",
+			nil,
+			true,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			act, err := NewEventListQuery(tc.i)
+			if (err != nil) != tc.err {
+				t.Fatal(err)
+			}
+
+			if act != nil {
+				act.stopCh = nil
+			}
+
+			assert.Equal(t, tc.exp, act)
+		})
+	}
+}
+
+func TestEventListQuery_Fetch(t *testing.T) {
+	_, _, err := testClients.Consul().Event().Fire(&api.UserEvent{
+		Name:    "event-list-test",
+		Payload: []byte("v1"),
+	}, nil)
+	require.NoError(t, err)
+
+	d, err := NewEventListQuery("event-list-test")
+	require.NoError(t, err)
+
+	var rm *ResponseMetadata
+	require.Eventually(t, func() bool {
+		var act interface{}
+		act, rm, err = d.Fetch(testClients, nil)
+		require.NoError(t, err)
+		return len(act.([]*UserEvent)) == 1
+	}, 5*time.Second, 100*time.Millisecond)
+
+	t.Run("fires_changes", func(t *testing.T) {
+		dataCh := make(chan interface{}, 1)
+		errCh := make(chan error, 1)
+		go func() {
+			data, _, err := d.Fetch(testClients, &QueryOptions{WaitIndex: rm.LastIndex})
+			if err != nil {
+				errCh <- err
+				return
+			}
+			dataCh <- data
+		}()
+
+		_, _, err := testClients.Consul().Event().Fire(&api.UserEvent{
+			Name:    "event-list-test",
+			Payload: []byte("v2"),
+		}, nil)
+		require.NoError(t, err)
+
+		select {
+		case err := <-errCh:
+			t.Fatal(err)
+		case data := <-dataCh:
+			events := data.([]*UserEvent)
+			require.Len(t, events, 2)
+			assert.Equal(t, "v2", events[1].Payload)
+			assert.Greater(t, events[1].LTime, events[0].LTime)
+		case <-time.After(5 * time.Second):
+			t.Fatal("did not fire")
+		}
+	})
+}
+
+func TestEventListQuery_String(t *testing.T) {
+	cases := []struct {
+		name string
+		i    string
+		exp  string
+	}{
+		{
+			"empty",
+			"",
+			"event.list",
+		},
+		{
+			"name",
+			"deploy",
+			"event.list(deploy)",
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			d, err := NewEventListQuery(tc.i)
+			require.NoError(t, err)
+			assert.Equal(t, tc.exp, d.String())
+		})
+	}
+}
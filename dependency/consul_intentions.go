@@ -0,0 +1,286 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"encoding/gob"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+)
+
+const (
+	// IntentionsQuerySource is the query parameter that filters intentions by
+	// the name of their source service.
+	IntentionsQuerySource = "source"
+
+	// IntentionsQueryDestination is the query parameter that filters
+	// intentions by the name of their destination service.
+	IntentionsQueryDestination = "destination"
+)
+
+var (
+	// Ensure implements
+	_ Dependency = (*ListIntentionsQuery)(nil)
+
+	// ListIntentionsQueryRe is the regular expression to use. Unlike other
+	// queries, the values may be the "*" wildcard.
+	ListIntentionsQueryRe = regexp.MustCompile(`\A(\?(?P<query>[[:word:]\-\_\=\&\.\*]+))?\z`)
+)
+
+func init() {
+	gob.Register([]*Intention{})
+}
+
+// ListIntentionsQuery fetches the service intentions in Consul, optionally
+// filtered by source or destination.
+// https://developer.hashicorp.com/consul/api-docs/connect/intentions#list-intentions
+type ListIntentionsQuery struct {
+	stopCh chan struct{}
+
+	source      string
+	destination string
+	namespace   string
+	partition   string
+	peer        string
+}
+
+// Intention is a service intention in Consul.
+type Intention struct {
+	ID          string
+	Description string
+	Source      IntentionTarget
+	Destination IntentionTarget
+
+	// Action is "allow" or "deny" for L4 intentions, and empty for L7
+	// intentions, which have Permissions instead.
+	Action      string
+	Permissions []*IntentionPermission
+
+	// Precedence is the order the intention is matched in, higher first.
+	Precedence int
+	Meta       map[string]string
+}
+
+// IntentionTarget is the source or destination of an intention. Only a
+// source can have a Peer or SamenessGroup.
+type IntentionTarget struct {
+	Name          string
+	Namespace     string
+	Partition     string
+	Peer          string
+	SamenessGroup string
+}
+
+// IntentionPermission is an L7 permission of an intention.
+type IntentionPermission struct {
+	Action string
+	HTTP   *api.IntentionHTTPPermission
+}
+
+// NewListIntentionsQuery parses a string of the format ?query, where the
+// query may contain source, destination, ns, partition and peer.
+func NewListIntentionsQuery(s string) (*ListIntentionsQuery, error) {
+	if !ListIntentionsQueryRe.MatchString(s) {
+		return nil, fmt.Errorf("list.intentions: invalid format: %q", s)
+	}
+
+	m := regexpMatch(ListIntentionsQueryRe, s)
+
+	queryParams := url.Values{}
+	if queryRaw := m["query"]; queryRaw != "" {
+		var err error
+		queryParams, err = url.ParseQuery(queryRaw)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"list.intentions: invalid query: %q: %s", queryRaw, err)
+		}
+		for key := range queryParams {
+			switch key {
+			case IntentionsQuerySource,
+				IntentionsQueryDestination,
+				QueryNamespace,
+				QueryPartition,
+				QueryPeer:
+			default:
+				return nil, fmt.Errorf("list.intentions: invalid query parameter key %q in query %q: supported keys: %s,%s,%s,%s,%s",
+					key, queryRaw, IntentionsQuerySource, IntentionsQueryDestination, QueryNamespace, QueryPartition, QueryPeer)
+			}
+		}
+	}
+
+	return &ListIntentionsQuery{
+		stopCh:      make(chan struct{}, 1),
+		source:      queryParams.Get(IntentionsQuerySource),
+		destination: queryParams.Get(IntentionsQueryDestination),
+		namespace:   queryParams.Get(QueryNamespace),
+		partition:   queryParams.Get(QueryPartition),
+		peer:        queryParams.Get(QueryPeer),
+	}, nil
+}
+
+// Fetch queries the Consul API defined by the given client and returns a slice
+// of Intention objects, in precedence order.
+func (l *ListIntentionsQuery) Fetch(clients *ClientSet, opts *QueryOptions) (interface{}, *ResponseMetadata, error) {
+	select {
+	case <-l.stopCh:
+		return nil, nil, ErrStopped
+	default:
+	}
+
+	opts = opts.Merge(&QueryOptions{
+		ConsulNamespace: l.namespace,
+		ConsulPartition: l.partition,
+	})
+
+	log.Printf("[TRACE] %s: GET %s", l, &url.URL{
+		Path:     "/v1/connect/intentions",
+		RawQuery: opts.String(),
+	})
+
+	entries, qm, err := clients.Consul().Connect().Intentions(opts.ToConsulOpts())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, l.String())
+	}
+
+	log.Printf("[TRACE] %s: returned %d results", l, len(entries))
+
+	intentions := make([]*Intention, 0, len(entries))
+	for _, entry := range entries {
+		if l.source != "" && entry.SourceName != l.source {
+			continue
+		}
+		if l.destination != "" && entry.DestinationName != l.destination {
+			continue
+		}
+		// Only the source of an intention can be in a cluster peer; the
+		// destination is always a service of the local cluster.
+		if l.peer != "" && entry.SourcePeer != l.peer {
+			continue
+		}
+		intentions = append(intentions, toIntention(entry))
+	}
+
+	log.Printf("[TRACE] %s: returned %d results after filtering", l, len(intentions))
+
+	// sort so that the result is deterministic
+	sort.Stable(ByPrecedence(intentions))
+
+	rm := &ResponseMetadata{
+		LastIndex:   qm.LastIndex,
+		LastContact: qm.LastContact,
+	}
+
+	return intentions, rm, nil
+}
+
+// toIntention converts an intention returned by the Consul API to the
+// Intention given to templates.
+func toIntention(i *api.Intention) *Intention {
+	var permissions []*IntentionPermission
+	for _, p := range i.Permissions {
+		permissions = append(permissions, &IntentionPermission{
+			Action: string(p.Action),
+			HTTP:   p.HTTP,
+		})
+	}
+
+	return &Intention{
+		ID:          i.ID,
+		Description: i.Description,
+		Source: IntentionTarget{
+			Name:          i.SourceName,
+			Namespace:     i.SourceNS,
+			Partition:     i.SourcePartition,
+			Peer:          i.SourcePeer,
+			SamenessGroup: i.SourceSamenessGroup,
+		},
+		Destination: IntentionTarget{
+			Name:      i.DestinationName,
+			Namespace: i.DestinationNS,
+			Partition: i.DestinationPartition,
+		},
+		Action:      string(i.Action),
+		Permissions: permissions,
+		Precedence:  i.Precedence,
+		Meta:        i.Meta,
+	}
+}
+
+// String returns the human-friendly version of this dependency.
+func (l *ListIntentionsQuery) String() string {
+	q := url.Values{}
+	if l.source != "" {
+		q.Set(IntentionsQuerySource, l.source)
+	}
+	if l.destination != "" {
+		q.Set(IntentionsQueryDestination, l.destination)
+	}
+	if l.namespace != "" {
+		q.Set(QueryNamespace, l.namespace)
+	}
+	if l.partition != "" {
+		q.Set(QueryPartition, l.partition)
+	}
+	if l.peer != "" {
+		q.Set(QueryPeer, l.peer)
+	}
+
+	if len(q) == 0 {
+		return "list.intentions"
+	}
+	return "list.intentions?" + q.Encode()
+}
+
+// Stop halts the dependency's fetch function.
+func (l *ListIntentionsQuery) Stop() {
+	close(l.stopCh)
+}
+
+// Type returns the type of this dependency.
+func (l *ListIntentionsQuery) Type() Type {
+	return TypeConsul
+}
+
+// CanShare returns a boolean if this dependency is shareable.
+func (l *ListIntentionsQuery) CanShare() bool {
+	return true
+}
+
+// ByPrecedence is a sortable list of intentions in the order they are
+// matched: highest precedence first, then by destination and source.
+type ByPrecedence []*Intention
+
+func (p ByPrecedence) Len() int      { return len(p) }
+func (p ByPrecedence) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p ByPrecedence) Less(i, j int) bool {
+	if p[i].Precedence != p[j].Precedence {
+		return p[i].Precedence > p[j].Precedence
+	}
+	if p[i].Destination != p[j].Destination {
+		return intentionTargetLess(p[i].Destination, p[j].Destination)
+	}
+	return intentionTargetLess(p[i].Source, p[j].Source)
+}
+
+func intentionTargetLess(a, b IntentionTarget) bool {
+	if a.Partition != b.Partition {
+		return a.Partition < b.Partition
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	if a.Peer != b.Peer {
+		return a.Peer < b.Peer
+	}
+	if a.SamenessGroup != b.SamenessGroup {
+		return a.SamenessGroup < b.SamenessGroup
+	}
+	return a.Name < b.Name
+}
@@ -0,0 +1,167 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewListIntentionsQuery(t *testing.T) {
+	cases := []struct {
+		name string
+		i    string
+		exp  *ListIntentionsQuery
+		err  bool
+	}{
+		{
+			"empty",
+			"",
+			&ListIntentionsQuery{},
+			false,
+		},
+		{
+			"invalid_key",
+			"?sameness-group=foo",
+			nil,
+			true,
+		},
+		{
+			"invalid_format",
+			"web",
+			nil,
+			true,
+		},
+		{
+			"all",
+			"?source=web&destination=db&ns=*&partition=team1&peer=cluster-2",
+			&ListIntentionsQuery{
+				source:      "web",
+				destination: "db",
+				namespace:   "*",
+				partition:   "team1",
+				peer:        "cluster-2",
+			},
+			false,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			act, err := NewListIntentionsQuery(tc.i)
+			if (err != nil) != tc.err {
+				t.Fatal(err)
+			}
+
+			if act != nil {
+				act.stopCh = nil
+			}
+
+			assert.Equal(t, tc.exp, act)
+		})
+	}
+}
+
+func TestListIntentionsQuery_Fetch(t *testing.T) {
+	entry := &api.ServiceIntentionsConfigEntry{
+		Kind: api.ServiceIntentions,
+		Name: "intentions-db",
+		Sources: []*api.SourceIntention{
+			{
+				Name:   "intentions-web",
+				Action: api.IntentionActionAllow,
+			},
+			{
+				Name:   "*",
+				Action: api.IntentionActionDeny,
+			},
+		},
+	}
+	if _, _, err := testClients.Consul().ConfigEntries().Set(entry, nil); err != nil {
+		t.Fatal(err)
+	}
+	defer testClients.Consul().ConfigEntries().Delete(entry.Kind, entry.Name, nil)
+
+	cases := []struct {
+		name string
+		i    string
+		exp  []string
+	}{
+		{
+			"destination",
+			"?destination=intentions-db",
+			[]string{"intentions-web allow", "* deny"},
+		},
+		{
+			"source",
+			"?source=intentions-web",
+			[]string{"intentions-web allow"},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			d, err := NewListIntentionsQuery(tc.i)
+			require.NoError(t, err)
+
+			act, _, err := d.Fetch(testClients, nil)
+			require.NoError(t, err)
+
+			var intentions []string
+			for _, intention := range act.([]*Intention) {
+				assert.Equal(t, "intentions-db", intention.Destination.Name)
+				intentions = append(intentions, intention.Source.Name+" "+intention.Action)
+			}
+			assert.Equal(t, tc.exp, intentions)
+		})
+	}
+}
+
+func TestListIntentionsQuery_String(t *testing.T) {
+	cases := []struct {
+		name string
+		i    string
+		exp  string
+	}{
+		{
+			"empty",
+			"",
+			"list.intentions",
+		},
+		{
+			"filters",
+			"?source=web&destination=db&partition=team1",
+			"list.intentions?destination=db&partition=team1&source=web",
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			d, err := NewListIntentionsQuery(tc.i)
+			require.NoError(t, err)
+			assert.Equal(t, tc.exp, d.String())
+		})
+	}
+}
+
+func TestByPrecedence(t *testing.T) {
+	intentions := []*Intention{
+		{Source: IntentionTarget{Name: "*"}, Destination: IntentionTarget{Name: "db"}, Precedence: 8},
+		{Source: IntentionTarget{Name: "web"}, Destination: IntentionTarget{Name: "db"}, Precedence: 9},
+		{Source: IntentionTarget{Name: "api"}, Destination: IntentionTarget{Name: "db"}, Precedence: 9},
+		{Source: IntentionTarget{Name: "web"}, Destination: IntentionTarget{Name: "api"}, Precedence: 9},
+	}
+	sort.Stable(ByPrecedence(intentions))
+
+	var act []string
+	for _, i := range intentions {
+		act = append(act, i.Source.Name+"->"+i.Destination.Name)
+	}
+	assert.Equal(t, []string{"web->api", "api->db", "web->db", "*->db"}, act)
+}
@@ -0,0 +1,286 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"context"
+	"encoding/gob"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+)
+
+var (
+	// Ensure implements
+	_ Dependency = (*LockHolderQuery)(nil)
+
+	// LockHolderQueryRe is the regular expression to use.
+	LockHolderQueryRe = regexp.MustCompile(`\A` + keyRe + queryRe + dcRe + `\z`)
+)
+
+func init() {
+	gob.Register(&LockHolder{})
+}
+
+// LockHolder is the session holding the lock on a key in Consul.
+type LockHolder struct {
+	// Key and Value are the locked key and its value, which is often the
+	// address of the lock holder.
+	Key       string
+	Value     string
+	LockIndex uint64
+
+	// Session is the ID of the session holding the lock, and Name, Node,
+	// Behavior and TTL are the fields of that session.
+	Session  string
+	Name     string
+	Node     string
+	Behavior string
+	TTL      string
+
+	// Checks are the health checks the session is tied to, and Status is
+	// their aggregated status.
+	Checks api.HealthChecks
+	Status string
+}
+
+// LockHolderQuery queries the session holding the lock on a key. It blocks on
+// both the key and the session, so it returns as soon as the lock is released,
+// acquired by another session or the health of the session changes.
+type LockHolderQuery struct {
+	stopCh chan struct{}
+
+	dc        string
+	key       string
+	namespace string
+	partition string
+
+	// session and node are the session and its node from the last fetch,
+	// which are also watched while blocking.
+	session string
+	node    string
+}
+
+// NewLockHolderQuery parses a string of the format key?query@dc into a
+// dependency.
+func NewLockHolderQuery(s string) (*LockHolderQuery, error) {
+	if !LockHolderQueryRe.MatchString(s) {
+		return nil, fmt.Errorf("lock_holder: invalid format: %q", s)
+	}
+
+	m := regexpMatch(LockHolderQueryRe, s)
+	queryParams, err := GetConsulQueryOpts(m, "lock_holder")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range []string{QueryPeer, QuerySamenessGroup} {
+		if queryParams.Get(key) != "" {
+			return nil, fmt.Errorf("lock_holder: unsupported query parameter %q", key)
+		}
+	}
+
+	return &LockHolderQuery{
+		stopCh:    make(chan struct{}, 1),
+		dc:        m["dc"],
+		key:       m["key"],
+		namespace: queryParams.Get(QueryNamespace),
+		partition: queryParams.Get(QueryPartition),
+	}, nil
+}
+
+// Fetch queries the Consul API defined by the given client and returns the
+// LockHolder of the key, or nil if the key does not exist or is not locked.
+func (d *LockHolderQuery) Fetch(clients *ClientSet, opts *QueryOptions) (interface{}, *ResponseMetadata, error) {
+	select {
+	case <-d.stopCh:
+		return nil, nil, ErrStopped
+	default:
+	}
+
+	opts = opts.Merge(&QueryOptions{
+		Datacenter:      d.dc,
+		ConsulPartition: d.partition,
+		ConsulNamespace: d.namespace,
+	})
+
+	log.Printf("[TRACE] %s: GET %s", d, &url.URL{
+		Path:     "/v1/kv/" + d.key,
+		RawQuery: opts.String(),
+	})
+
+	if opts.WaitIndex != 0 {
+		if err := d.wait(clients, opts); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Read the current state of the key and its session without blocking.
+	consulOpts := opts.ToConsulOpts()
+	consulOpts.WaitIndex = 0
+	consulOpts.WaitTime = 0
+
+	pair, qm, err := clients.Consul().KV().Get(d.key, consulOpts)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, d.String())
+	}
+
+	rm := &ResponseMetadata{
+		LastIndex:   qm.LastIndex,
+		LastContact: qm.LastContact,
+	}
+
+	d.session, d.node = "", ""
+
+	if pair == nil || pair.Session == "" {
+		log.Printf("[TRACE] %s: returned no lock holder", d)
+		return nil, rm, nil
+	}
+
+	session, qm, err := clients.Consul().Session().Info(pair.Session, consulOpts)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, d.String())
+	}
+	rm.LastIndex = max(rm.LastIndex, qm.LastIndex)
+
+	// The session was invalidated between the two reads, so the next fetch
+	// returns the key without it.
+	if session == nil {
+		log.Printf("[TRACE] %s: returned no lock holder", d)
+		return nil, rm, nil
+	}
+
+	nodeOpts := &api.QueryOptions{
+		Datacenter: consulOpts.Datacenter,
+		Partition:  consulOpts.Partition,
+		AllowStale: consulOpts.AllowStale,
+	}
+	checks, qm, err := clients.Consul().Health().Node(session.Node, nodeOpts)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, d.String())
+	}
+	rm.LastIndex = max(rm.LastIndex, qm.LastIndex)
+
+	d.session, d.node = session.ID, session.Node
+
+	holder := &LockHolder{
+		Key:       pair.Key,
+		Value:     string(pair.Value),
+		LockIndex: pair.LockIndex,
+		Session:   session.ID,
+		Name:      session.Name,
+		Node:      session.Node,
+		Behavior:  session.Behavior,
+		TTL:       session.TTL,
+		Checks:    sessionChecks(session, checks),
+	}
+	holder.Status = holder.Checks.AggregatedStatus()
+
+	log.Printf("[TRACE] %s: returned session %s on %s", d, holder.Session, holder.Node)
+
+	return holder, rm, nil
+}
+
+// wait blocks until the key, the session from the last fetch or the health of
+// its node changes. Raft indexes are global, so the same wait index can be
+// used for all three queries.
+func (d *LockHolderQuery) wait(clients *ClientSet, opts *QueryOptions) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	consulOpts := opts.ToConsulOpts().WithContext(ctx)
+
+	// The queries can still be running after wait returns, so they must not
+	// read the session and node that the next fetch replaces.
+	session, node := d.session, d.node
+
+	errCh := make(chan error, 3)
+	go func() {
+		_, _, err := clients.Consul().KV().Get(d.key, consulOpts)
+		errCh <- err
+	}()
+	if session != "" {
+		go func(session string) {
+			_, _, err := clients.Consul().Session().Info(session, consulOpts)
+			errCh <- err
+		}(session)
+		go func(node string) {
+			nodeOpts := &api.QueryOptions{
+				Datacenter: consulOpts.Datacenter,
+				Partition:  consulOpts.Partition,
+				AllowStale: consulOpts.AllowStale,
+				WaitIndex:  consulOpts.WaitIndex,
+				WaitTime:   consulOpts.WaitTime,
+			}
+			_, _, err := clients.Consul().Health().Node(node, nodeOpts.WithContext(ctx))
+			errCh <- err
+		}(node)
+	}
+
+	select {
+	case <-d.stopCh:
+		return ErrStopped
+	case err := <-errCh:
+		if err != nil {
+			return errors.Wrap(err, d.String())
+		}
+		return nil
+	}
+}
+
+// sessionChecks returns the health checks the session is tied to.
+func sessionChecks(session *api.SessionEntry, checks api.HealthChecks) api.HealthChecks {
+	ids := make(map[string]struct{})
+	for _, id := range session.Checks {
+		ids[id] = struct{}{}
+	}
+	for _, id := range session.NodeChecks {
+		ids[id] = struct{}{}
+	}
+	for _, check := range session.ServiceChecks {
+		ids[check.ID] = struct{}{}
+	}
+
+	list := make(api.HealthChecks, 0, len(ids))
+	for _, check := range checks {
+		if _, ok := ids[check.CheckID]; ok {
+			list = append(list, check)
+		}
+	}
+	return list
+}
+
+// CanShare returns a boolean if this dependency is shareable.
+func (d *LockHolderQuery) CanShare() bool {
+	return true
+}
+
+// Stop halts the dependency's fetch function.
+func (d *LockHolderQuery) Stop() {
+	close(d.stopCh)
+}
+
+// String returns the human-friendly version of this dependency.
+func (d *LockHolderQuery) String() string {
+	key := d.key
+	if d.dc != "" {
+		key = key + "@" + d.dc
+	}
+	if d.partition != "" {
+		key = key + "@partition=" + d.partition
+	}
+	if d.namespace != "" {
+		key = key + "@ns=" + d.namespace
+	}
+	return fmt.Sprintf("lock_holder(%s)", key)
+}
+
+// Type returns the type of this dependency.
+func (d *LockHolderQuery) Type() Type {
+	return TypeConsul
+}
@@ -0,0 +1,175 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLockHolderQuery(t *testing.T) {
+	cases := []struct {
+		name string
+		i    string
+		exp  *LockHolderQuery
+		err  bool
+	}{
+		{
+			"empty",
+			"",
+			nil,
+			true,
+		},
+		{
+			"peer",
+			"service/leader?peer=foo",
+			nil,
+			true,
+		},
+		{
+			"key",
+			"service/leader",
+			&LockHolderQuery{
+				key: "service/leader",
+			},
+			false,
+		},
+		{
+			"key_query_dc",
+			"service/leader?ns=foo&partition=bar@dc1",
+			&LockHolderQuery{
+				key:       "service/leader",
+				dc:        "dc1",
+				namespace: "foo",
+				partition: "bar",
+			},
+			false,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			act, err := NewLockHolderQuery(tc.i)
+			if (err != nil) != tc.err {
+				t.Fatal(err)
+			}
+
+			if act != nil {
+				act.stopCh = nil
+			}
+
+			assert.Equal(t, tc.exp, act)
+		})
+	}
+}
+
+func TestLockHolderQuery_Fetch(t *testing.T) {
+	consul := testClients.Consul()
+
+	key := "test-lock-holder/leader"
+	defer consul.KV().Delete(key, nil)
+
+	session, _, err := consul.Session().Create(&api.SessionEntry{
+		Name: "lock-holder-test",
+	}, nil)
+	require.NoError(t, err)
+	defer consul.Session().Destroy(session, nil)
+
+	t.Run("unlocked", func(t *testing.T) {
+		d, err := NewLockHolderQuery(key)
+		require.NoError(t, err)
+
+		act, _, err := d.Fetch(testClients, nil)
+		require.NoError(t, err)
+		assert.Nil(t, act)
+	})
+
+	acquired, _, err := consul.KV().Acquire(&api.KVPair{
+		Key:     key,
+		Value:   []byte("10.0.0.1:8080"),
+		Session: session,
+	}, nil)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	t.Run("locked", func(t *testing.T) {
+		d, err := NewLockHolderQuery(key)
+		require.NoError(t, err)
+
+		act, _, err := d.Fetch(testClients, nil)
+		require.NoError(t, err)
+
+		holder := act.(*LockHolder)
+		assert.Equal(t, key, holder.Key)
+		assert.Equal(t, "10.0.0.1:8080", holder.Value)
+		assert.Equal(t, session, holder.Session)
+		assert.Equal(t, "lock-holder-test", holder.Name)
+		assert.NotEmpty(t, holder.Node)
+		assert.Equal(t, api.HealthPassing, holder.Status)
+	})
+
+	t.Run("fires_changes", func(t *testing.T) {
+		d, err := NewLockHolderQuery(key)
+		require.NoError(t, err)
+
+		_, rm, err := d.Fetch(testClients, nil)
+		require.NoError(t, err)
+
+		dataCh := make(chan interface{}, 1)
+		errCh := make(chan error, 1)
+		go func() {
+			data, _, err := d.Fetch(testClients, &QueryOptions{WaitIndex: rm.LastIndex})
+			if err != nil {
+				errCh <- err
+				return
+			}
+			dataCh <- data
+		}()
+
+		// Destroying the session releases the lock.
+		_, err = consul.Session().Destroy(session, nil)
+		require.NoError(t, err)
+
+		select {
+		case err := <-errCh:
+			t.Fatal(err)
+		case data := <-dataCh:
+			assert.Nil(t, data)
+		case <-time.After(5 * time.Second):
+			t.Fatal("did not fire")
+		}
+	})
+}
+
+func TestLockHolderQuery_String(t *testing.T) {
+	cases := []struct {
+		name string
+		i    string
+		exp  string
+	}{
+		{
+			"key",
+			"service/leader",
+			"lock_holder(service/leader)",
+		},
+		{
+			"key_query_dc",
+			"service/leader?ns=foo&partition=bar@dc1",
+			"lock_holder(service/leader@dc1@partition=bar@ns=foo)",
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			d, err := NewLockHolderQuery(tc.i)
+			require.NoError(t, err)
+			assert.Equal(t, tc.exp, d.String())
+		})
+	}
+}
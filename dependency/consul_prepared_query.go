@@ -0,0 +1,143 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// Ensure implements
+	_ Dependency = (*PreparedQueryExecuteQuery)(nil)
+
+	// PreparedQueryExecuteQueryRe is the regular expression to use.
+	PreparedQueryExecuteQueryRe = regexp.MustCompile(`\A` + nodeNameRe + dcRe + nearRe + `\z`)
+
+	// PreparedQuerySleepTime is the amount of time to sleep between queries,
+	// since prepared queries do not support blocking queries.
+	PreparedQuerySleepTime     = DefaultNonBlockingQuerySleepTime
+	oncePreparedQuerySleepTime sync.Once
+)
+
+// PreparedQueryExecuteQuery is the dependency to execute a prepared query in
+// Consul.
+type PreparedQueryExecuteQuery struct {
+	stopCh chan struct{}
+
+	dc   string
+	name string
+	near string
+}
+
+// NewPreparedQueryExecuteQuery parses a string of the format name@dc~near
+// into a prepared query dependency. The name may also be the ID of the
+// prepared query.
+func NewPreparedQueryExecuteQuery(s string) (*PreparedQueryExecuteQuery, error) {
+	if !PreparedQueryExecuteQueryRe.MatchString(s) {
+		return nil, fmt.Errorf("prepared_query: invalid format: %q", s)
+	}
+
+	m := regexpMatch(PreparedQueryExecuteQueryRe, s)
+	return &PreparedQueryExecuteQuery{
+		stopCh: make(chan struct{}, 1),
+		dc:     m["dc"],
+		name:   m["name"],
+		near:   m["near"],
+	}, nil
+}
+
+// Fetch queries the Consul API defined by the given client and returns a slice
+// of HealthService objects for the nodes returned by the prepared query.
+func (d *PreparedQueryExecuteQuery) Fetch(clients *ClientSet, opts *QueryOptions) (interface{}, *ResponseMetadata, error) {
+	opts = opts.Merge(&QueryOptions{
+		Datacenter: d.dc,
+		Near:       d.near,
+	})
+
+	log.Printf("[TRACE] %s: GET %s", d, &url.URL{
+		Path:     "/v1/query/" + d.name + "/execute",
+		RawQuery: opts.String(),
+	})
+
+	// Prepared queries do not support blocking queries, so after the first
+	// query, sleep before asking Consul again.
+	if opts.WaitIndex != 0 {
+		log.Printf("[TRACE] %s: long polling for %s", d, PreparedQuerySleepTime)
+
+		select {
+		case <-d.stopCh:
+			return nil, nil, ErrStopped
+		case <-time.After(PreparedQuerySleepTime):
+		}
+	}
+
+	consulOpts := opts.ToConsulOpts()
+	consulOpts.WaitIndex = 0
+	consulOpts.WaitTime = 0
+
+	resp, _, err := clients.Consul().PreparedQuery().Execute(d.name, consulOpts)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, d.String())
+	}
+
+	log.Printf("[TRACE] %s: returned %d results from %s (%d failovers)",
+		d, len(resp.Nodes), resp.Datacenter, resp.Failovers)
+
+	list := make([]*HealthService, 0, len(resp.Nodes))
+	for i := range resp.Nodes {
+		entry := &resp.Nodes[i]
+		list = append(list, newHealthService(entry, entry.Checks.AggregatedStatus()))
+	}
+
+	// Prepared queries shuffle their results unless they sort by nearness, so
+	// sort them to avoid rendering on every poll.
+	if d.near == "" {
+		sort.Stable(ByNodeThenID(list))
+	}
+
+	return respWithMetadata(list)
+}
+
+// Make sure to only set PreparedQuerySleepTime once
+func SetPreparedQuerySleepTime(t time.Duration) {
+	set := func() {
+		PreparedQuerySleepTime = t
+	}
+	oncePreparedQuerySleepTime.Do(set)
+}
+
+// CanShare returns a boolean if this dependency is shareable.
+func (d *PreparedQueryExecuteQuery) CanShare() bool {
+	return true
+}
+
+// Stop halts the dependency's fetch function.
+func (d *PreparedQueryExecuteQuery) Stop() {
+	close(d.stopCh)
+}
+
+// String returns the human-friendly version of this dependency.
+func (d *PreparedQueryExecuteQuery) String() string {
+	name := d.name
+	if d.dc != "" {
+		name = name + "@" + d.dc
+	}
+	if d.near != "" {
+		name = name + "~" + d.near
+	}
+	return fmt.Sprintf("prepared_query(%s)", name)
+}
+
+// Type returns the type of this dependency.
+func (d *PreparedQueryExecuteQuery) Type() Type {
+	return TypeConsul
+}
@@ -0,0 +1,179 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	PreparedQuerySleepTime = 50 * time.Millisecond
+}
+
+func TestNewPreparedQueryExecuteQuery(t *testing.T) {
+	cases := []struct {
+		name string
+		i    string
+		exp  *PreparedQueryExecuteQuery
+		err  bool
+	}{
+		{
+			"empty",
+			"",
+			nil,
+			true,
+		},
+		{
+			"dc_only",
+			"@dc1",
+			nil,
+			true,
+		},
+		{
+			"name",
+			"web-failover",
+			&PreparedQueryExecuteQuery{
+				name: "web-failover",
+			},
+			false,
+		},
+		{
+			"name_dc",
+			"web-failover@dc1",
+			&PreparedQueryExecuteQuery{
+				dc:   "dc1",
+				name: "web-failover",
+			},
+			false,
+		},
+		{
+			"name_dc_near",
+			"web-failover@dc1~_agent",
+			&PreparedQueryExecuteQuery{
+				dc:   "dc1",
+				name: "web-failover",
+				near: "_agent",
+			},
+			false,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			act, err := NewPreparedQueryExecuteQuery(tc.i)
+			if (err != nil) != tc.err {
+				t.Fatal(err)
+			}
+
+			if act != nil {
+				act.stopCh = nil
+			}
+
+			assert.Equal(t, tc.exp, act)
+		})
+	}
+}
+
+func TestPreparedQueryExecuteQuery_Fetch(t *testing.T) {
+	id, _, err := testClients.Consul().PreparedQuery().Create(&api.PreparedQueryDefinition{
+		Name: "consul-failover",
+		Service: api.ServiceQuery{
+			Service: "consul",
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testClients.Consul().PreparedQuery().Delete(id, nil)
+
+	t.Run("executes", func(t *testing.T) {
+		d, err := NewPreparedQueryExecuteQuery("consul-failover")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		act, _, err := d.Fetch(testClients, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		services := act.([]*HealthService)
+		if len(services) != 1 {
+			t.Fatalf("expected 1 service, got %d", len(services))
+		}
+		assert.Equal(t, "consul", services[0].Name)
+		assert.Equal(t, HealthPassing, services[0].Status)
+	})
+
+	t.Run("stops", func(t *testing.T) {
+		d, err := NewPreparedQueryExecuteQuery("consul-failover")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		dataCh := make(chan interface{}, 1)
+		errCh := make(chan error, 1)
+		go func() {
+			for {
+				data, _, err := d.Fetch(testClients, &QueryOptions{WaitIndex: 10})
+				if err != nil {
+					errCh <- err
+					return
+				}
+				dataCh <- data
+			}
+		}()
+
+		select {
+		case err := <-errCh:
+			t.Fatal(err)
+		case <-dataCh:
+		}
+
+		d.Stop()
+
+		select {
+		case err := <-errCh:
+			if err != ErrStopped {
+				t.Fatal(err)
+			}
+		case <-time.After(100 * time.Millisecond):
+			t.Errorf("did not stop")
+		}
+	})
+}
+
+func TestPreparedQueryExecuteQuery_String(t *testing.T) {
+	cases := []struct {
+		name string
+		i    string
+		exp  string
+	}{
+		{
+			"name",
+			"web-failover",
+			"prepared_query(web-failover)",
+		},
+		{
+			"name_dc_near",
+			"web-failover@dc1~_agent",
+			"prepared_query(web-failover@dc1~_agent)",
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			d, err := NewPreparedQueryExecuteQuery(tc.i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.exp, d.String())
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"encoding/gob"
+	"log"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// Ensure implements
+	_ Dependency = (*DatacenterCoordinatesQuery)(nil)
+
+	// DatacenterCoordinatesQuerySleepTime is the amount of time to sleep
+	// between queries, since the endpoint does not support blocking queries.
+	DatacenterCoordinatesQuerySleepTime = DefaultNonBlockingQuerySleepTime
+)
+
+func init() {
+	gob.Register([]*DatacenterCoordinates{})
+}
+
+// DatacenterCoordinates are the network coordinates of the servers of a
+// datacenter in a WAN area. Coordinates are only comparable within the same
+// area.
+type DatacenterCoordinates struct {
+	Datacenter  string
+	AreaID      string
+	Coordinates []*NodeCoordinate
+}
+
+// DatacenterCoordinatesQuery is the dependency to query the network
+// coordinates of the servers of all datacenters.
+type DatacenterCoordinatesQuery struct {
+	stopCh chan struct{}
+}
+
+// NewDatacenterCoordinatesQuery creates a new datacenter coordinates
+// dependency.
+func NewDatacenterCoordinatesQuery() (*DatacenterCoordinatesQuery, error) {
+	return &DatacenterCoordinatesQuery{
+		stopCh: make(chan struct{}, 1),
+	}, nil
+}
+
+// Fetch queries the Consul API defined by the given client and returns a slice
+// of DatacenterCoordinates objects.
+func (d *DatacenterCoordinatesQuery) Fetch(clients *ClientSet, opts *QueryOptions) (interface{}, *ResponseMetadata, error) {
+	opts = opts.Merge(&QueryOptions{})
+
+	log.Printf("[TRACE] %s: GET %s", d, &url.URL{
+		Path:     "/v1/coordinate/datacenters",
+		RawQuery: opts.String(),
+	})
+
+	// The datacenters endpoint does not support blocking queries, so after
+	// the first query, sleep before asking Consul again.
+	if opts.WaitIndex != 0 {
+		log.Printf("[TRACE] %s: long polling for %s", d, DatacenterCoordinatesQuerySleepTime)
+
+		select {
+		case <-d.stopCh:
+			return nil, nil, ErrStopped
+		case <-time.After(DatacenterCoordinatesQuerySleepTime):
+		}
+	}
+
+	maps, err := clients.Consul().Coordinate().Datacenters()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, d.String())
+	}
+
+	log.Printf("[TRACE] %s: returned %d results", d, len(maps))
+
+	list := make([]*DatacenterCoordinates, 0, len(maps))
+	for _, m := range maps {
+		coords := make([]*NodeCoordinate, 0, len(m.Coordinates))
+		for _, entry := range m.Coordinates {
+			coords = append(coords, &NodeCoordinate{
+				Node:      entry.Node,
+				Segment:   entry.Segment,
+				Partition: entry.Partition,
+				Coord:     entry.Coord,
+			})
+		}
+		sort.Stable(ByNodeThenSegment(coords))
+
+		list = append(list, &DatacenterCoordinates{
+			Datacenter:  m.Datacenter,
+			AreaID:      m.AreaID,
+			Coordinates: coords,
+		})
+	}
+
+	sort.Stable(ByAreaThenDatacenter(list))
+
+	return respWithMetadata(list)
+}
+
+// CanShare returns if this dependency is shareable.
+func (d *DatacenterCoordinatesQuery) CanShare() bool {
+	return true
+}
+
+// String returns the human-friendly version of this dependency.
+func (d *DatacenterCoordinatesQuery) String() string {
+	return "coordinate.datacenters"
+}
+
+// Stop terminates this dependency's fetch.
+func (d *DatacenterCoordinatesQuery) Stop() {
+	close(d.stopCh)
+}
+
+// Type returns the type of this dependency.
+func (d *DatacenterCoordinatesQuery) Type() Type {
+	return TypeConsul
+}
+
+// ByAreaThenDatacenter is a sortable slice of DatacenterCoordinates.
+type ByAreaThenDatacenter []*DatacenterCoordinates
+
+// Len, Swap, and Less are used to implement the sort.Sort interface.
+func (s ByAreaThenDatacenter) Len() int      { return len(s) }
+func (s ByAreaThenDatacenter) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s ByAreaThenDatacenter) Less(i, j int) bool {
+	if s[i].AreaID != s[j].AreaID {
+		return s[i].AreaID < s[j].AreaID
+	}
+	return s[i].Datacenter < s[j].Datacenter
+}
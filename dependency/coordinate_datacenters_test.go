@@ -0,0 +1,65 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	DatacenterCoordinatesQuerySleepTime = 50 * time.Millisecond
+}
+
+func TestDatacenterCoordinatesQuery_Fetch(t *testing.T) {
+	d, err := NewDatacenterCoordinatesQuery()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	act, _, err := d.Fetch(testClients, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dcs []string
+	for _, dc := range act.([]*DatacenterCoordinates) {
+		dcs = append(dcs, dc.Datacenter)
+	}
+	assert.Equal(t, []string{"dc1"}, dcs)
+
+	t.Run("stops", func(t *testing.T) {
+		d, err := NewDatacenterCoordinatesQuery()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		errCh := make(chan error, 1)
+		go func() {
+			_, _, err := d.Fetch(testClients, &QueryOptions{WaitIndex: 10})
+			errCh <- err
+		}()
+
+		d.Stop()
+
+		select {
+		case err := <-errCh:
+			if err != ErrStopped {
+				t.Fatal(err)
+			}
+		case <-time.After(500 * time.Millisecond):
+			t.Errorf("did not stop")
+		}
+	})
+}
+
+func TestDatacenterCoordinatesQuery_String(t *testing.T) {
+	d, err := NewDatacenterCoordinatesQuery()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "coordinate.datacenters", d.String())
+}
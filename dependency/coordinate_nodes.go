@@ -0,0 +1,161 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"encoding/gob"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/serf/coordinate"
+	"github.com/pkg/errors"
+)
+
+var (
+	// Ensure implements
+	_ Dependency = (*NodeCoordinatesQuery)(nil)
+
+	// NodeCoordinatesQueryRe is the regular expression to use.
+	NodeCoordinatesQueryRe = regexp.MustCompile(`\A` + queryRe + dcRe + `\z`)
+)
+
+func init() {
+	gob.Register([]*NodeCoordinate{})
+}
+
+// NodeCoordinate is the network coordinate of a node in Consul.
+type NodeCoordinate struct {
+	Node      string
+	Segment   string
+	Partition string
+	Coord     *coordinate.Coordinate
+}
+
+// NodeCoordinatesQuery is the representation of a query for the network
+// coordinates of the nodes in the LAN pool of a datacenter.
+type NodeCoordinatesQuery struct {
+	stopCh chan struct{}
+
+	dc        string
+	partition string
+}
+
+// NewNodeCoordinatesQuery parses a string of the format ?query@dc, where the
+// only supported query parameter is the partition.
+func NewNodeCoordinatesQuery(s string) (*NodeCoordinatesQuery, error) {
+	if !NodeCoordinatesQueryRe.MatchString(s) {
+		return nil, fmt.Errorf("coordinate.nodes: invalid format: %q", s)
+	}
+
+	m := regexpMatch(NodeCoordinatesQueryRe, s)
+	queryParams, err := GetConsulQueryOpts(m, "coordinate.nodes")
+	if err != nil {
+		return nil, err
+	}
+
+	// Nodes are not namespaced and coordinates are not shared across peers.
+	for _, key := range []string{QueryNamespace, QueryPeer, QuerySamenessGroup} {
+		if queryParams.Get(key) != "" {
+			return nil, fmt.Errorf("coordinate.nodes: unsupported query parameter %q", key)
+		}
+	}
+
+	return &NodeCoordinatesQuery{
+		stopCh:    make(chan struct{}, 1),
+		dc:        m["dc"],
+		partition: queryParams.Get(QueryPartition),
+	}, nil
+}
+
+// Fetch queries the Consul API defined by the given client and returns a slice
+// of NodeCoordinate objects.
+func (d *NodeCoordinatesQuery) Fetch(clients *ClientSet, opts *QueryOptions) (interface{}, *ResponseMetadata, error) {
+	select {
+	case <-d.stopCh:
+		return nil, nil, ErrStopped
+	default:
+	}
+
+	opts = opts.Merge(&QueryOptions{
+		Datacenter:      d.dc,
+		ConsulPartition: d.partition,
+	})
+
+	log.Printf("[TRACE] %s: GET %s", d, &url.URL{
+		Path:     "/v1/coordinate/nodes",
+		RawQuery: opts.String(),
+	})
+
+	entries, qm, err := clients.Consul().Coordinate().Nodes(opts.ToConsulOpts())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, d.String())
+	}
+
+	log.Printf("[TRACE] %s: returned %d results", d, len(entries))
+
+	coords := make([]*NodeCoordinate, 0, len(entries))
+	for _, entry := range entries {
+		coords = append(coords, &NodeCoordinate{
+			Node:      entry.Node,
+			Segment:   entry.Segment,
+			Partition: entry.Partition,
+			Coord:     entry.Coord,
+		})
+	}
+
+	sort.Stable(ByNodeThenSegment(coords))
+
+	rm := &ResponseMetadata{
+		LastIndex:   qm.LastIndex,
+		LastContact: qm.LastContact,
+	}
+
+	return coords, rm, nil
+}
+
+// CanShare returns a boolean if this dependency is shareable.
+func (d *NodeCoordinatesQuery) CanShare() bool {
+	return true
+}
+
+// Stop halts the dependency's fetch function.
+func (d *NodeCoordinatesQuery) Stop() {
+	close(d.stopCh)
+}
+
+// String returns the human-friendly version of this dependency.
+func (d *NodeCoordinatesQuery) String() string {
+	var name string
+	if d.dc != "" {
+		name = "@" + d.dc
+	}
+	if d.partition != "" {
+		name = name + "@partition=" + d.partition
+	}
+	if name == "" {
+		return "coordinate.nodes"
+	}
+	return fmt.Sprintf("coordinate.nodes(%s)", name)
+}
+
+// Type returns the type of this dependency.
+func (d *NodeCoordinatesQuery) Type() Type {
+	return TypeConsul
+}
+
+// ByNodeThenSegment is a sortable slice of NodeCoordinate.
+type ByNodeThenSegment []*NodeCoordinate
+
+// Len, Swap, and Less are used to implement the sort.Sort interface.
+func (s ByNodeThenSegment) Len() int      { return len(s) }
+func (s ByNodeThenSegment) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s ByNodeThenSegment) Less(i, j int) bool {
+	if s[i].Node != s[j].Node {
+		return s[i].Node < s[j].Node
+	}
+	return s[i].Segment < s[j].Segment
+}
@@ -0,0 +1,142 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewNodeCoordinatesQuery(t *testing.T) {
+	cases := []struct {
+		name string
+		i    string
+		exp  *NodeCoordinatesQuery
+		err  bool
+	}{
+		{
+			"empty",
+			"",
+			&NodeCoordinatesQuery{},
+			false,
+		},
+		{
+			"invalid_format",
+			"web",
+			nil,
+			true,
+		},
+		{
+			"namespace",
+			"?ns=foo",
+			nil,
+			true,
+		},
+		{
+			"peer",
+			"?peer=foo",
+			nil,
+			true,
+		},
+		{
+			"dc",
+			"@dc1",
+			&NodeCoordinatesQuery{
+				dc: "dc1",
+			},
+			false,
+		},
+		{
+			"partition_dc",
+			"?partition=foo@dc1",
+			&NodeCoordinatesQuery{
+				dc:        "dc1",
+				partition: "foo",
+			},
+			false,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			act, err := NewNodeCoordinatesQuery(tc.i)
+			if (err != nil) != tc.err {
+				t.Fatal(err)
+			}
+
+			if act != nil {
+				act.stopCh = nil
+			}
+
+			assert.Equal(t, tc.exp, act)
+		})
+	}
+}
+
+func TestNodeCoordinatesQuery_Fetch(t *testing.T) {
+	d, err := NewNodeCoordinatesQuery("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	act, rm, err := d.Fetch(testClients, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.IsType(t, []*NodeCoordinate{}, act)
+	assert.NotZero(t, rm.LastIndex)
+}
+
+func TestNodeCoordinatesQuery_String(t *testing.T) {
+	cases := []struct {
+		name string
+		i    string
+		exp  string
+	}{
+		{
+			"empty",
+			"",
+			"coordinate.nodes",
+		},
+		{
+			"dc",
+			"@dc1",
+			"coordinate.nodes(@dc1)",
+		},
+		{
+			"partition_dc",
+			"?partition=foo@dc1",
+			"coordinate.nodes(@dc1@partition=foo)",
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			d, err := NewNodeCoordinatesQuery(tc.i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.exp, d.String())
+		})
+	}
+}
+
+func TestByNodeThenSegment(t *testing.T) {
+	coords := []*NodeCoordinate{
+		{Node: "node2"},
+		{Node: "node1", Segment: "beta"},
+		{Node: "node1", Segment: "alpha"},
+	}
+	sort.Stable(ByNodeThenSegment(coords))
+
+	var act []string
+	for _, c := range coords {
+		act = append(act, c.Node+"/"+c.Segment)
+	}
+	assert.Equal(t, []string{"node1/alpha", "node1/beta", "node2/"}, act)
+}
@@ -83,6 +83,7 @@ type QueryOptions struct {
 	ConsulPartition     string
 	ConsulNamespace     string
 	ConsulSamenessGroup string
+	Filter              string
 }
 
 func (q *QueryOptions) Merge(o *QueryOptions) *QueryOptions {
@@ -150,6 +151,10 @@ func (q *QueryOptions) Merge(o *QueryOptions) *QueryOptions {
 		r.ConsulSamenessGroup = o.ConsulSamenessGroup
 	}
 
+	if o.Filter != "" {
+		r.Filter = o.Filter
+	}
+
 	return &r
 }
 
@@ -162,6 +167,7 @@ func (q *QueryOptions) ToConsulOpts() *consulapi.QueryOptions {
 		SamenessGroup:     q.ConsulSamenessGroup,
 		Peer:              q.ConsulPeer,
 		Near:              q.Near,
+		Filter:            q.Filter,
 		RequireConsistent: q.RequireConsistent,
 		WaitIndex:         q.WaitIndex,
 		WaitTime:          q.WaitTime,
@@ -244,6 +250,10 @@ func (q *QueryOptions) String() string {
 		u.Add("near", q.Near)
 	}
 
+	if q.Filter != "" {
+		u.Add(QueryFilter, q.Filter)
+	}
+
 	if q.Choose != "" {
 		u.Add("choose", q.Choose)
 	}
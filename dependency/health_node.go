@@ -0,0 +1,171 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+)
+
+var (
+	// Ensure implements
+	_ Dependency = (*HealthNodeQuery)(nil)
+
+	// HealthNodeQueryRe is the regular expression to use.
+	HealthNodeQueryRe = regexp.MustCompile(`\A` + nodeNameRe + queryRe + dcRe + filterRe + `\z`)
+)
+
+// HealthNodeQuery is the representation of a query for the health checks of
+// a node, including the checks of the services on the node.
+type HealthNodeQuery struct {
+	stopCh chan struct{}
+
+	dc        string
+	filters   []string
+	name      string
+	namespace string
+	partition string
+	filter    string
+}
+
+// NewHealthNodeQuery parses a string of the format node?query@dc|filter,
+// where the filter is a comma separated list of check states. All checks are
+// returned if there is no filter.
+func NewHealthNodeQuery(s string) (*HealthNodeQuery, error) {
+	if !HealthNodeQueryRe.MatchString(s) {
+		return nil, fmt.Errorf("health.node: invalid format: %q", s)
+	}
+
+	m := regexpMatch(HealthNodeQueryRe, s)
+
+	var filters []string
+	if filter := m["filter"]; filter != "" {
+		for _, f := range strings.Split(filter, ",") {
+			f = strings.TrimSpace(f)
+			switch f {
+			case HealthAny,
+				HealthPassing,
+				HealthWarning,
+				HealthCritical:
+				filters = append(filters, f)
+			case "":
+			default:
+				return nil, fmt.Errorf(
+					"health.node: invalid filter: %q in %q", f, s)
+			}
+		}
+		sort.Strings(filters)
+	}
+
+	queryParams, err := GetConsulQueryOpts(m, "health.node")
+	if err != nil {
+		return nil, err
+	}
+
+	return &HealthNodeQuery{
+		stopCh:    make(chan struct{}, 1),
+		dc:        m["dc"],
+		filters:   filters,
+		name:      m["name"],
+		namespace: queryParams.Get(QueryNamespace),
+		partition: queryParams.Get(QueryPartition),
+	}, nil
+}
+
+// Fetch queries the Consul API defined by the given client and returns the
+// health checks of the node.
+func (d *HealthNodeQuery) Fetch(clients *ClientSet, opts *QueryOptions) (interface{}, *ResponseMetadata, error) {
+	select {
+	case <-d.stopCh:
+		return nil, nil, ErrStopped
+	default:
+	}
+
+	opts = opts.Merge(&QueryOptions{
+		Datacenter:      d.dc,
+		ConsulNamespace: d.namespace,
+		ConsulPartition: d.partition,
+		Filter:          d.filter,
+	})
+
+	log.Printf("[TRACE] %s: GET %s", d, &url.URL{
+		Path:     "/v1/health/node/" + d.name,
+		RawQuery: opts.String(),
+	})
+
+	checks, qm, err := clients.Consul().Health().Node(d.name, opts.ToConsulOpts())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, d.String())
+	}
+
+	log.Printf("[TRACE] %s: returned %d results", d, len(checks))
+
+	list := make(api.HealthChecks, 0, len(checks))
+	for _, check := range checks {
+		if len(d.filters) > 0 && !acceptStatus(d.filters, check.Status) {
+			continue
+		}
+		list = append(list, check)
+	}
+
+	log.Printf("[TRACE] %s: returned %d results after filtering", d, len(list))
+
+	sort.Stable(ByNodeThenCheckID(list))
+
+	rm := &ResponseMetadata{
+		LastIndex:   qm.LastIndex,
+		LastContact: qm.LastContact,
+	}
+
+	return list, rm, nil
+}
+
+// SetFilter sets a filter expression that Consul evaluates to filter the
+// results server-side.
+func (d *HealthNodeQuery) SetFilter(filter string) {
+	d.filter = filter
+}
+
+// CanShare returns a boolean if this dependency is shareable.
+func (d *HealthNodeQuery) CanShare() bool {
+	return true
+}
+
+// Stop halts the dependency's fetch function.
+func (d *HealthNodeQuery) Stop() {
+	close(d.stopCh)
+}
+
+// String returns the human-friendly version of this dependency.
+func (d *HealthNodeQuery) String() string {
+	name := d.name
+	if d.dc != "" {
+		name = name + "@" + d.dc
+	}
+	if d.partition != "" {
+		name = name + "@partition=" + d.partition
+	}
+	if d.namespace != "" {
+		name = name + "@ns=" + d.namespace
+	}
+	if d.filter != "" {
+		name = name + "@filter=" + d.filter
+	}
+	if len(d.filters) > 0 {
+		name = name + "|" + strings.Join(d.filters, ",")
+	}
+	return fmt.Sprintf("health.node(%s)", name)
+}
+
+// Type returns the type of this dependency.
+func (d *HealthNodeQuery) Type() Type {
+	return TypeConsul
+}
@@ -0,0 +1,157 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHealthNodeQuery(t *testing.T) {
+	cases := []struct {
+		name string
+		i    string
+		exp  *HealthNodeQuery
+		err  bool
+	}{
+		{
+			"empty",
+			"",
+			nil,
+			true,
+		},
+		{
+			"invalid_filter",
+			"node1|maintenance",
+			nil,
+			true,
+		},
+		{
+			"name",
+			"node1",
+			&HealthNodeQuery{
+				name: "node1",
+			},
+			false,
+		},
+		{
+			"name_query_dc_filter",
+			"node1?partition=bar@dc1|warning,critical",
+			&HealthNodeQuery{
+				dc:        "dc1",
+				filters:   []string{"critical", "warning"},
+				name:      "node1",
+				partition: "bar",
+			},
+			false,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			act, err := NewHealthNodeQuery(tc.i)
+			if (err != nil) != tc.err {
+				t.Fatal(err)
+			}
+
+			if act != nil {
+				act.stopCh = nil
+			}
+
+			assert.Equal(t, tc.exp, act)
+		})
+	}
+}
+
+func TestHealthNodeQuery_Fetch(t *testing.T) {
+	cases := []struct {
+		name string
+		i    string
+		exp  []string
+	}{
+		{
+			"all",
+			testConsul.Config.NodeName,
+			[]string{"serfHealth"},
+		},
+		{
+			"filtered",
+			testConsul.Config.NodeName + "|critical",
+			nil,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			d, err := NewHealthNodeQuery(tc.i)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			act, _, err := d.Fetch(testClients, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var ids []string
+			for _, check := range act.(api.HealthChecks) {
+				ids = append(ids, check.CheckID)
+			}
+			assert.Equal(t, tc.exp, ids)
+		})
+	}
+}
+
+func TestHealthNodeQuery_String(t *testing.T) {
+	cases := []struct {
+		name string
+		i    string
+		exp  string
+	}{
+		{
+			"name",
+			"node1",
+			"health.node(node1)",
+		},
+		{
+			"name_dc_filter",
+			"node1@dc1|warning,critical",
+			"health.node(node1@dc1|critical,warning)",
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			d, err := NewHealthNodeQuery(tc.i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.exp, d.String())
+		})
+	}
+}
+
+func TestHealthNodeQuery_filter(t *testing.T) {
+	d, err := NewHealthNodeQuery(testConsul.Config.NodeName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.SetFilter(`CheckID == "serfHealth"`)
+
+	assert.Equal(t, fmt.Sprintf(`health.node(%s@filter=CheckID == "serfHealth")`,
+		testConsul.Config.NodeName), d.String())
+
+	act, _, err := d.Fetch(testClients, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checks := act.(api.HealthChecks)
+	if len(checks) != 1 {
+		t.Fatalf("expected 1 check, got %d", len(checks))
+	}
+	assert.Equal(t, "serfHealth", checks[0].CheckID)
+}
@@ -4,6 +4,7 @@
 package dependency
 
 import (
+	"context"
 	"encoding/gob"
 	"fmt"
 	"log"
@@ -23,6 +24,7 @@ const (
 	HealthCritical = "critical"
 	HealthMaint    = "maintenance"
 
+	QueryFilter        = "filter"
 	QueryNamespace     = "ns"
 	QueryPartition     = "partition"
 	QueryPeer          = "peer"
@@ -61,6 +63,10 @@ type HealthService struct {
 	Status                 string
 	Port                   int
 	Weights                api.AgentWeights
+
+	// Datacenter is the datacenter of the service. It is only set for
+	// queries across all datacenters.
+	Datacenter string
 }
 
 // HealthServiceQuery is the representation of all a service query in Consul.
@@ -77,6 +83,7 @@ type HealthServiceQuery struct {
 	peer          string
 	namespace     string
 	samenessGroup string
+	filter        string
 }
 
 // NewHealthServiceQuery processes the strings to build a service dependency.
@@ -154,6 +161,11 @@ func (d *HealthServiceQuery) Fetch(clients *ClientSet, opts *QueryOptions) (inte
 	default:
 	}
 
+	return d.fetch(context.Background(), clients, opts)
+}
+
+// fetch queries the Consul API, canceling the query when the context is done.
+func (d *HealthServiceQuery) fetch(ctx context.Context, clients *ClientSet, opts *QueryOptions) (interface{}, *ResponseMetadata, error) {
 	opts = opts.Merge(&QueryOptions{
 		Datacenter:          d.dc,
 		Near:                d.near,
@@ -161,6 +173,7 @@ func (d *HealthServiceQuery) Fetch(clients *ClientSet, opts *QueryOptions) (inte
 		ConsulPartition:     d.partition,
 		ConsulPeer:          d.peer,
 		ConsulSamenessGroup: d.samenessGroup,
+		Filter:              d.filter,
 	})
 
 	u := &url.URL{
@@ -183,7 +196,7 @@ func (d *HealthServiceQuery) Fetch(clients *ClientSet, opts *QueryOptions) (inte
 	if d.connect {
 		nodes = clients.Consul().Health().Connect
 	}
-	entries, qm, err := nodes(d.name, d.tag, passingOnly, opts.ToConsulOpts())
+	entries, qm, err := nodes(d.name, d.tag, passingOnly, opts.ToConsulOpts().WithContext(ctx))
 	if err != nil {
 		return nil, nil, errors.Wrap(err, d.String())
 	}
@@ -201,31 +214,7 @@ func (d *HealthServiceQuery) Fetch(clients *ClientSet, opts *QueryOptions) (inte
 			continue
 		}
 
-		// Get the address of the service, falling back to the address of the
-		// node.
-		address := entry.Service.Address
-		if address == "" {
-			address = entry.Node.Address
-		}
-
-		list = append(list, &HealthService{
-			Node:                   entry.Node.Node,
-			NodeID:                 entry.Node.ID,
-			NodeAddress:            entry.Node.Address,
-			NodeTaggedAddresses:    entry.Node.TaggedAddresses,
-			NodeMeta:               entry.Node.Meta,
-			ServiceMeta:            entry.Service.Meta,
-			Address:                address,
-			ServiceTaggedAddresses: entry.Service.TaggedAddresses,
-			ID:                     entry.Service.ID,
-			Name:                   entry.Service.Service,
-			Tags: ServiceTags(
-				deepCopyAndSortTags(entry.Service.Tags)),
-			Status:  status,
-			Checks:  entry.Checks,
-			Port:    entry.Service.Port,
-			Weights: entry.Service.Weights,
-		})
+		list = append(list, newHealthService(entry, status))
 	}
 
 	log.Printf("[TRACE] %s: returned %d results after filtering", d, len(list))
@@ -243,6 +232,42 @@ func (d *HealthServiceQuery) Fetch(clients *ClientSet, opts *QueryOptions) (inte
 	return list, rm, nil
 }
 
+// newHealthService converts the Consul service entry into a HealthService
+// with the given status.
+func newHealthService(entry *api.ServiceEntry, status string) *HealthService {
+	// Get the address of the service, falling back to the address of the
+	// node.
+	address := entry.Service.Address
+	if address == "" {
+		address = entry.Node.Address
+	}
+
+	return &HealthService{
+		Node:                   entry.Node.Node,
+		NodeID:                 entry.Node.ID,
+		NodeAddress:            entry.Node.Address,
+		NodeTaggedAddresses:    entry.Node.TaggedAddresses,
+		NodeMeta:               entry.Node.Meta,
+		ServiceMeta:            entry.Service.Meta,
+		Address:                address,
+		ServiceTaggedAddresses: entry.Service.TaggedAddresses,
+		ID:                     entry.Service.ID,
+		Name:                   entry.Service.Service,
+		Tags: ServiceTags(
+			deepCopyAndSortTags(entry.Service.Tags)),
+		Status:  status,
+		Checks:  entry.Checks,
+		Port:    entry.Service.Port,
+		Weights: entry.Service.Weights,
+	}
+}
+
+// SetFilter sets a filter expression that Consul evaluates to filter the
+// results server-side.
+func (d *HealthServiceQuery) SetFilter(filter string) {
+	d.filter = filter
+}
+
 // CanShare returns a boolean if this dependency is shareable.
 func (d *HealthServiceQuery) CanShare() bool {
 	return true
@@ -274,6 +299,9 @@ func (d *HealthServiceQuery) String() string {
 	if d.samenessGroup != "" {
 		name = name + "@sameness-group=" + d.samenessGroup
 	}
+	if d.filter != "" {
+		name = name + "@filter=" + d.filter
+	}
 	if d.near != "" {
 		name = name + "~" + d.near
 	}
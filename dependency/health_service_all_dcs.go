@@ -0,0 +1,289 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const allDatacenters = "*"
+
+var (
+	// Ensure implements
+	_ Dependency = (*HealthServiceAllDCsQuery)(nil)
+
+	// HealthServiceAllDCsQueryRe is the regular expression to use. It is the
+	// same as HealthServiceQueryRe, except that the datacenter can only be
+	// "*".
+	HealthServiceAllDCsQueryRe = regexp.MustCompile(`\A` + tagRe + serviceNameRe + queryRe + `(@\*)?` + nearRe + filterRe + `\z`)
+
+	// HealthServiceAllDCsRetryTime is the amount of time to wait before
+	// querying a datacenter again after a query to it failed.
+	HealthServiceAllDCsRetryTime = 5 * time.Second
+)
+
+// HealthServiceAllDCsQuery is the representation of a service query across
+// all datacenters in Consul. It watches the service in every datacenter and
+// the list of datacenters, and returns as soon as either changes.
+type HealthServiceAllDCsQuery struct {
+	stopCh chan struct{}
+
+	// query is the query for the service, with "*" as its datacenter.
+	query *HealthServiceQuery
+
+	// dcs, results and indexes are the datacenters, and the services in and
+	// last index of each datacenter, from the last fetch. The indexes of
+	// different datacenters are not comparable, so index is the monotonic
+	// index returned to the view in their place.
+	dcs     []string
+	results map[string][]*HealthService
+	indexes map[string]uint64
+	index   uint64
+
+	// failed are the datacenters whose last query failed. Their last known
+	// services are kept until a retry succeeds.
+	failed map[string]bool
+}
+
+// NewHealthServiceAllDCsQuery parses a string of the format
+// tag.name?query@*~near|filter into a dependency. The "@*" is optional.
+func NewHealthServiceAllDCsQuery(s string) (*HealthServiceAllDCsQuery, error) {
+	if !HealthServiceAllDCsQueryRe.MatchString(s) {
+		return nil, fmt.Errorf("health.service: invalid format: %q", s)
+	}
+
+	query, err := healthServiceQuery(strings.Replace(s, "@"+allDatacenters, "", 1), false)
+	if err != nil {
+		return nil, err
+	}
+	if query.peer != "" || query.samenessGroup != "" {
+		return nil, fmt.Errorf("health.service: cannot specify %s or %s across all datacenters: %q",
+			QueryPeer, QuerySamenessGroup, s)
+	}
+	query.dc = allDatacenters
+
+	return &HealthServiceAllDCsQuery{
+		stopCh: make(chan struct{}, 1),
+		query:  query,
+	}, nil
+}
+
+// IsAllDCsServiceQuery returns true if the given service query string is for
+// all datacenters.
+func IsAllDCsServiceQuery(s string) bool {
+	return strings.Contains(s, "@"+allDatacenters)
+}
+
+// SetFilter sets a filter expression that Consul evaluates to filter the
+// results server-side.
+func (d *HealthServiceAllDCsQuery) SetFilter(filter string) {
+	d.query.SetFilter(filter)
+}
+
+// Fetch queries the Consul API defined by the given client and returns a slice
+// of HealthService objects from all datacenters, ordered by datacenter.
+func (d *HealthServiceAllDCsQuery) Fetch(clients *ClientSet, opts *QueryOptions) (interface{}, *ResponseMetadata, error) {
+	select {
+	case <-d.stopCh:
+		return nil, nil, ErrStopped
+	default:
+	}
+
+	// If the view has the data of the last fetch, block until a datacenter
+	// changes. Otherwise query all datacenters without blocking.
+	refresh := true
+	if opts != nil && opts.WaitIndex != 0 && opts.WaitIndex == d.index {
+		var err error
+		if refresh, err = d.wait(clients, opts); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if refresh {
+		if err := d.refresh(clients, opts); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var list []*HealthService
+	for _, dc := range d.dcs {
+		list = append(list, d.results[dc]...)
+	}
+	if list == nil {
+		list = []*HealthService{}
+	}
+
+	log.Printf("[TRACE] %s: returned %d results from %d datacenters", d, len(list), len(d.dcs))
+
+	d.index++
+
+	return list, &ResponseMetadata{LastIndex: d.index}, nil
+}
+
+// refresh queries the list of datacenters and the services in each of them
+// without blocking.
+func (d *HealthServiceAllDCsQuery) refresh(clients *ClientSet, opts *QueryOptions) error {
+	dcs, err := clients.Consul().Catalog().Datacenters()
+	if err != nil {
+		return errors.Wrap(err, d.String())
+	}
+	sort.Strings(dcs)
+
+	opts = opts.Merge(nil)
+	opts.WaitIndex = 0
+	opts.WaitTime = 0
+
+	results := make(map[string][]*HealthService, len(dcs))
+	indexes := make(map[string]uint64, len(dcs))
+	failed := make(map[string]bool)
+	for _, dc := range dcs {
+		list, rm, err := d.queryDC(dc).fetch(context.Background(), clients, opts)
+		if err != nil {
+			// A single unavailable datacenter should not hide the services
+			// in all others.
+			log.Printf("[WARN] %s: failed to query datacenter %s: %s", d, dc, err)
+			results[dc] = d.results[dc]
+			failed[dc] = true
+			continue
+		}
+		results[dc] = tagDatacenter(list.([]*HealthService), dc)
+		indexes[dc] = rm.LastIndex
+	}
+
+	d.dcs, d.results, d.indexes, d.failed = dcs, results, indexes, failed
+	return nil
+}
+
+// wait blocks until the services in a datacenter or the list of datacenters
+// change. It returns true if the list of datacenters changed.
+func (d *HealthServiceAllDCsQuery) wait(clients *ClientSet, opts *QueryOptions) (bool, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type result struct {
+		dc   string
+		list []*HealthService
+		rm   *ResponseMetadata
+		err  error
+	}
+	resultCh := make(chan result, len(d.dcs)+1)
+
+	for _, dc := range d.dcs {
+		dcOpts := opts.Merge(nil)
+		dcOpts.WaitIndex = d.indexes[dc]
+
+		go func(q *HealthServiceQuery, failed bool) {
+			for {
+				// Retry a failed datacenter without blocking, after a while.
+				if failed {
+					dcOpts.WaitIndex = 0
+					select {
+					case <-ctx.Done():
+						return
+					case <-time.After(HealthServiceAllDCsRetryTime):
+					}
+				}
+
+				list, rm, err := q.fetch(ctx, clients, dcOpts)
+				if err != nil {
+					if ctx.Err() != nil {
+						return
+					}
+					log.Printf("[WARN] %s: failed to query datacenter %s: %s", d, q.dc, err)
+					failed = true
+					continue
+				}
+				resultCh <- result{dc: q.dc, list: list.([]*HealthService), rm: rm}
+				return
+			}
+		}(d.queryDC(dc), d.failed[dc])
+	}
+
+	// The datacenters endpoint does not support blocking queries, so poll it
+	// for additions and removals.
+	current := d.dcs
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(CatalogDatacentersQuerySleepTime):
+			}
+
+			dcs, err := clients.Consul().Catalog().Datacenters()
+			if err != nil {
+				resultCh <- result{err: err}
+				return
+			}
+			sort.Strings(dcs)
+			if !slices.Equal(dcs, current) {
+				log.Printf("[TRACE] %s: datacenters changed", d)
+				resultCh <- result{}
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-d.stopCh:
+		return false, ErrStopped
+	case r := <-resultCh:
+		if r.err != nil {
+			return false, errors.Wrap(r.err, d.String())
+		}
+		if r.dc == "" {
+			return true, nil
+		}
+		// Blocking queries that return due to the wait time have the same
+		// index and data.
+		d.results[r.dc] = tagDatacenter(r.list, r.dc)
+		d.indexes[r.dc] = r.rm.LastIndex
+		delete(d.failed, r.dc)
+		return false, nil
+	}
+}
+
+// queryDC returns the service query for the given datacenter.
+func (d *HealthServiceAllDCsQuery) queryDC(dc string) *HealthServiceQuery {
+	q := *d.query
+	q.dc = dc
+	return &q
+}
+
+// tagDatacenter sets the datacenter of the services.
+func tagDatacenter(list []*HealthService, dc string) []*HealthService {
+	for _, s := range list {
+		s.Datacenter = dc
+	}
+	return list
+}
+
+// CanShare returns a boolean if this dependency is shareable.
+func (d *HealthServiceAllDCsQuery) CanShare() bool {
+	return true
+}
+
+// Stop halts the dependency's fetch function.
+func (d *HealthServiceAllDCsQuery) Stop() {
+	close(d.stopCh)
+}
+
+// String returns the human-friendly version of this dependency.
+func (d *HealthServiceAllDCsQuery) String() string {
+	return d.query.String()
+}
+
+// Type returns the type of this dependency.
+func (d *HealthServiceAllDCsQuery) Type() Type {
+	return TypeConsul
+}
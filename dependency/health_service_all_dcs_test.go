@@ -0,0 +1,206 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	HealthServiceAllDCsRetryTime = 50 * time.Millisecond
+}
+
+func TestNewHealthServiceAllDCsQuery(t *testing.T) {
+	cases := []struct {
+		name string
+		i    string
+		exp  *HealthServiceQuery
+		err  bool
+	}{
+		{
+			"name",
+			"web",
+			&HealthServiceQuery{
+				dc:      "*",
+				name:    "web",
+				filters: []string{"passing"},
+			},
+			false,
+		},
+		{
+			"all_dcs",
+			"web@*",
+			&HealthServiceQuery{
+				dc:      "*",
+				name:    "web",
+				filters: []string{"passing"},
+			},
+			false,
+		},
+		{
+			"tag_query_near_filter",
+			"primary.web?ns=foo@*~node1|any",
+			&HealthServiceQuery{
+				dc:        "*",
+				name:      "web",
+				tag:       "primary",
+				namespace: "foo",
+				near:      "node1",
+				filters:   []string{"any"},
+			},
+			false,
+		},
+		{
+			"dc",
+			"web@dc1",
+			nil,
+			true,
+		},
+		{
+			"peer",
+			"web?peer=foo@*",
+			nil,
+			true,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			act, err := NewHealthServiceAllDCsQuery(tc.i)
+			if (err != nil) != tc.err {
+				t.Fatal(err)
+			}
+
+			if act == nil {
+				assert.Nil(t, tc.exp)
+				return
+			}
+
+			act.query.stopCh = nil
+			assert.Equal(t, tc.exp, act.query)
+		})
+	}
+}
+
+func TestHealthServiceAllDCsQuery_Fetch(t *testing.T) {
+	d, err := NewHealthServiceAllDCsQuery("consul@*")
+	require.NoError(t, err)
+
+	act, rm, err := d.Fetch(testClients, nil)
+	require.NoError(t, err)
+
+	services := act.([]*HealthService)
+	require.Len(t, services, 1)
+	assert.Equal(t, "consul", services[0].Name)
+	assert.Equal(t, "dc1", services[0].Datacenter)
+
+	t.Run("stops", func(t *testing.T) {
+		errCh := make(chan error, 1)
+		go func() {
+			_, _, err := d.Fetch(testClients, &QueryOptions{WaitIndex: rm.LastIndex})
+			errCh <- err
+		}()
+
+		time.Sleep(100 * time.Millisecond)
+		d.Stop()
+
+		select {
+		case err := <-errCh:
+			if err != ErrStopped {
+				t.Fatal(err)
+			}
+		case <-time.After(500 * time.Millisecond):
+			t.Errorf("did not stop")
+		}
+	})
+}
+
+func TestHealthServiceAllDCsQuery_Fetch_failingDC(t *testing.T) {
+	// dc2 fails until it is fixed, and queries to dc1 block once they have an
+	// index.
+	var dc2Fixed atomic.Bool
+	consul := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/catalog/datacenters":
+			fmt.Fprint(w, `["dc1","dc2"]`)
+		case "/v1/health/service/web":
+			dc := r.URL.Query().Get("dc")
+			switch {
+			case dc == "dc2" && !dc2Fixed.Load():
+				http.Error(w, "rpc error: no path to datacenter", http.StatusInternalServerError)
+				return
+			case dc == "dc1" && r.URL.Query().Get("index") != "":
+				<-r.Context().Done()
+				return
+			}
+			w.Header().Set("X-Consul-Index", "1")
+			fmt.Fprintf(w, `[{"Node":{"Node":"node-%s"},"Service":{"Service":"web"}}]`, dc)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(consul.Close)
+
+	clients := NewClientSet()
+	require.NoError(t, clients.CreateConsulClient(&CreateConsulClientInput{
+		Address: consul.URL,
+	}))
+
+	d, err := NewHealthServiceAllDCsQuery("web@*")
+	require.NoError(t, err)
+	t.Cleanup(d.Stop)
+
+	nodes := func(act interface{}) []string {
+		var nodes []string
+		for _, s := range act.([]*HealthService) {
+			nodes = append(nodes, s.Datacenter+"/"+s.Node)
+		}
+		return nodes
+	}
+
+	act, rm, err := d.Fetch(clients, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dc1/node-dc1"}, nodes(act))
+
+	// The failing datacenter is retried until it recovers.
+	dc2Fixed.Store(true)
+	act, _, err = d.Fetch(clients, &QueryOptions{WaitIndex: rm.LastIndex})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dc1/node-dc1", "dc2/node-dc2"}, nodes(act))
+}
+
+func TestHealthServiceAllDCsQuery_String(t *testing.T) {
+	cases := []struct {
+		name string
+		i    string
+		exp  string
+	}{
+		{
+			"name",
+			"web",
+			"health.service(web@*|passing)",
+		},
+		{
+			"tag_near_filter",
+			"primary.web@*~node1|any",
+			"health.service(primary.web@*~node1|any)",
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			d, err := NewHealthServiceAllDCsQuery(tc.i)
+			require.NoError(t, err)
+			assert.Equal(t, tc.exp, d.String())
+		})
+	}
+}
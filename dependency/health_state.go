@@ -0,0 +1,152 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"encoding/gob"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/pkg/errors"
+)
+
+var (
+	// Ensure implements
+	_ Dependency = (*HealthStateQuery)(nil)
+
+	// HealthStateQueryRe is the regular expression to use.
+	HealthStateQueryRe = regexp.MustCompile(`\A(?P<state>any|passing|warning|critical)` + queryRe + dcRe + `\z`)
+)
+
+func init() {
+	gob.Register(api.HealthChecks{})
+}
+
+// HealthStateQuery is the representation of a query for all health checks
+// in a given state.
+type HealthStateQuery struct {
+	stopCh chan struct{}
+
+	dc        string
+	state     string
+	namespace string
+	partition string
+	filter    string
+}
+
+// NewHealthStateQuery parses a string of the format state?query@dc, where
+// the state is one of any, passing, warning or critical.
+func NewHealthStateQuery(s string) (*HealthStateQuery, error) {
+	if !HealthStateQueryRe.MatchString(s) {
+		return nil, fmt.Errorf("health.state: invalid format: %q", s)
+	}
+
+	m := regexpMatch(HealthStateQueryRe, s)
+	queryParams, err := GetConsulQueryOpts(m, "health.state")
+	if err != nil {
+		return nil, err
+	}
+
+	return &HealthStateQuery{
+		stopCh:    make(chan struct{}, 1),
+		dc:        m["dc"],
+		state:     m["state"],
+		namespace: queryParams.Get(QueryNamespace),
+		partition: queryParams.Get(QueryPartition),
+	}, nil
+}
+
+// Fetch queries the Consul API defined by the given client and returns the
+// health checks in the state.
+func (d *HealthStateQuery) Fetch(clients *ClientSet, opts *QueryOptions) (interface{}, *ResponseMetadata, error) {
+	select {
+	case <-d.stopCh:
+		return nil, nil, ErrStopped
+	default:
+	}
+
+	opts = opts.Merge(&QueryOptions{
+		Datacenter:      d.dc,
+		ConsulNamespace: d.namespace,
+		ConsulPartition: d.partition,
+		Filter:          d.filter,
+	})
+
+	log.Printf("[TRACE] %s: GET %s", d, &url.URL{
+		Path:     "/v1/health/state/" + d.state,
+		RawQuery: opts.String(),
+	})
+
+	checks, qm, err := clients.Consul().Health().State(d.state, opts.ToConsulOpts())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, d.String())
+	}
+
+	log.Printf("[TRACE] %s: returned %d results", d, len(checks))
+
+	sort.Stable(ByNodeThenCheckID(checks))
+
+	rm := &ResponseMetadata{
+		LastIndex:   qm.LastIndex,
+		LastContact: qm.LastContact,
+	}
+
+	return checks, rm, nil
+}
+
+// SetFilter sets a filter expression that Consul evaluates to filter the
+// results server-side.
+func (d *HealthStateQuery) SetFilter(filter string) {
+	d.filter = filter
+}
+
+// CanShare returns a boolean if this dependency is shareable.
+func (d *HealthStateQuery) CanShare() bool {
+	return true
+}
+
+// Stop halts the dependency's fetch function.
+func (d *HealthStateQuery) Stop() {
+	close(d.stopCh)
+}
+
+// String returns the human-friendly version of this dependency.
+func (d *HealthStateQuery) String() string {
+	name := d.state
+	if d.dc != "" {
+		name = name + "@" + d.dc
+	}
+	if d.partition != "" {
+		name = name + "@partition=" + d.partition
+	}
+	if d.namespace != "" {
+		name = name + "@ns=" + d.namespace
+	}
+	if d.filter != "" {
+		name = name + "@filter=" + d.filter
+	}
+	return fmt.Sprintf("health.state(%s)", name)
+}
+
+// Type returns the type of this dependency.
+func (d *HealthStateQuery) Type() Type {
+	return TypeConsul
+}
+
+// ByNodeThenCheckID is a sortable slice of health checks.
+type ByNodeThenCheckID api.HealthChecks
+
+// Len, Swap, and Less are used to implement the sort.Sort interface.
+func (s ByNodeThenCheckID) Len() int      { return len(s) }
+func (s ByNodeThenCheckID) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s ByNodeThenCheckID) Less(i, j int) bool {
+	if s[i].Node != s[j].Node {
+		return s[i].Node < s[j].Node
+	}
+	return s[i].CheckID < s[j].CheckID
+}
@@ -0,0 +1,140 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/consul/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHealthStateQuery(t *testing.T) {
+	cases := []struct {
+		name string
+		i    string
+		exp  *HealthStateQuery
+		err  bool
+	}{
+		{
+			"empty",
+			"",
+			nil,
+			true,
+		},
+		{
+			"invalid_state",
+			"maintenance",
+			nil,
+			true,
+		},
+		{
+			"state",
+			"critical",
+			&HealthStateQuery{
+				state: "critical",
+			},
+			false,
+		},
+		{
+			"state_query_dc",
+			"passing?ns=foo&partition=bar@dc1",
+			&HealthStateQuery{
+				dc:        "dc1",
+				state:     "passing",
+				namespace: "foo",
+				partition: "bar",
+			},
+			false,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			act, err := NewHealthStateQuery(tc.i)
+			if (err != nil) != tc.err {
+				t.Fatal(err)
+			}
+
+			if act != nil {
+				act.stopCh = nil
+			}
+
+			assert.Equal(t, tc.exp, act)
+		})
+	}
+}
+
+func TestHealthStateQuery_Fetch(t *testing.T) {
+	d, err := NewHealthStateQuery("passing")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	act, _, err := d.Fetch(testClients, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checks := act.(api.HealthChecks)
+	if len(checks) == 0 {
+		t.Fatal("expected passing checks")
+	}
+	for _, check := range checks {
+		assert.Equal(t, api.HealthPassing, check.Status)
+	}
+}
+
+func TestHealthStateQuery_String(t *testing.T) {
+	cases := []struct {
+		name string
+		i    string
+		exp  string
+	}{
+		{
+			"state",
+			"critical",
+			"health.state(critical)",
+		},
+		{
+			"state_query_dc",
+			"passing?ns=foo&partition=bar@dc1",
+			"health.state(passing@dc1@partition=bar@ns=foo)",
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			d, err := NewHealthStateQuery(tc.i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.exp, d.String())
+		})
+	}
+}
+
+func TestHealthStateQuery_filter(t *testing.T) {
+	d, err := NewHealthStateQuery("any")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter := fmt.Sprintf("Node == %q", testConsul.Config.NodeName)
+	d.SetFilter(filter)
+
+	assert.Equal(t, fmt.Sprintf("health.state(any@filter=%s)", filter), d.String())
+
+	act, _, err := d.Fetch(testClients, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checks := act.(api.HealthChecks)
+	if len(checks) == 0 {
+		t.Fatal("expected checks")
+	}
+	for _, check := range checks {
+		assert.Equal(t, testConsul.Config.NodeName, check.Node)
+	}
+}
@@ -4,6 +4,7 @@
 package dependency
 
 import (
+	"encoding/gob"
 	"fmt"
 	"log"
 	"net/url"
@@ -20,6 +21,10 @@ var (
 	KVGetQueryRe = regexp.MustCompile(`\A` + keyRe + queryRe + dcRe + `\z`)
 )
 
+func init() {
+	gob.Register(&KeyPair{})
+}
+
 // KVGetQuery queries the KV store for a single key.
 type KVGetQuery struct {
 	stopCh chan struct{}
@@ -27,6 +32,7 @@ type KVGetQuery struct {
 	dc         string
 	key        string
 	blockOnNil bool
+	keyPair    bool
 	namespace  string
 	partition  string
 }
@@ -91,6 +97,19 @@ func (d *KVGetQuery) Fetch(clients *ClientSet, opts *QueryOptions) (interface{},
 
 	value := string(pair.Value)
 	log.Printf("[TRACE] %s: returned %q", d, value)
+
+	if d.keyPair {
+		return &KeyPair{
+			Path:        pair.Key,
+			Key:         pair.Key,
+			Value:       value,
+			CreateIndex: pair.CreateIndex,
+			ModifyIndex: pair.ModifyIndex,
+			LockIndex:   pair.LockIndex,
+			Flags:       pair.Flags,
+			Session:     pair.Session,
+		}, rm, nil
+	}
 	return value, rm, nil
 }
 
@@ -99,6 +118,17 @@ func (d *KVGetQuery) EnableBlocking() {
 	d.blockOnNil = true
 }
 
+// EnableKeyPair makes this query return the full KeyPair of the key instead
+// of only its value.
+func (d *KVGetQuery) EnableKeyPair() {
+	d.keyPair = true
+}
+
+// IsKeyPair returns true if this query returns the full KeyPair of the key.
+func (d *KVGetQuery) IsKeyPair() bool {
+	return d.keyPair
+}
+
 // CanShare returns a boolean if this dependency is shareable.
 func (d *KVGetQuery) CanShare() bool {
 	return true
@@ -117,6 +147,9 @@ func (d *KVGetQuery) String() string {
 		key = key + "@ns=" + d.namespace
 	}
 
+	if d.keyPair {
+		return fmt.Sprintf("kv.pair(%s)", key)
+	}
 	if d.blockOnNil {
 		return fmt.Sprintf("kv.block(%s)", key)
 	}
@@ -0,0 +1,186 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	vaultapi "github.com/hashicorp/vault/api"
+)
+
+const (
+	// VaultAuthMethodAppRole, VaultAuthMethodJWT, VaultAuthMethodCert and
+	// VaultAuthMethodUserpass are the supported Vault auth methods.
+	VaultAuthMethodAppRole  = "approle"
+	VaultAuthMethodJWT      = "jwt"
+	VaultAuthMethodCert     = "cert"
+	VaultAuthMethodUserpass = "userpass"
+)
+
+// Ensure implements
+var _ vaultapi.AuthMethod = (*VaultAuth)(nil)
+
+// VaultAuth logs in to Vault with one of the supported auth methods. Files are
+// read on every login, so credentials that are rotated on disk are picked up
+// when logging in again.
+type VaultAuth struct {
+	Method    string
+	MountPath string
+
+	// RoleIDFile, SecretIDFile and SecretIDWrapped are used by the approle
+	// method. If SecretIDWrapped is true, SecretIDFile contains a
+	// response-wrapping token for the secret_id.
+	RoleIDFile      string
+	SecretIDFile    string
+	SecretIDWrapped bool
+
+	// Role is the role for the jwt method and the name of the certificate role
+	// for the cert method. JWTFile is used by the jwt method.
+	Role    string
+	JWTFile string
+
+	// Username, Password and PasswordFile are used by the userpass method.
+	Username     string
+	Password     string
+	PasswordFile string
+}
+
+// Login logs in to Vault with the auth method and returns the secret with the
+// new token. It implements api.AuthMethod.
+func (a *VaultAuth) Login(ctx context.Context, client *vaultapi.Client) (*vaultapi.Secret, error) {
+	mountPath := a.MountPath
+	if mountPath == "" {
+		mountPath = a.Method
+	}
+	path := fmt.Sprintf("auth/%s/login", mountPath)
+
+	var data map[string]interface{}
+	switch a.Method {
+	case VaultAuthMethodAppRole:
+		roleID, err := readAuthFile("role_id_file", a.RoleIDFile)
+		if err != nil {
+			return nil, fmt.Errorf("approle auth: %w", err)
+		}
+		data = map[string]interface{}{"role_id": roleID}
+
+		// The secret_id is optional if the role does not require one.
+		if a.SecretIDFile != "" {
+			secretID, err := readAuthFile("secret_id_file", a.SecretIDFile)
+			if err != nil {
+				return nil, fmt.Errorf("approle auth: %w", err)
+			}
+			if a.SecretIDWrapped {
+				if secretID, err = unwrapSecretID(ctx, client, secretID); err != nil {
+					return nil, fmt.Errorf("approle auth: %w", err)
+				}
+			}
+			data["secret_id"] = secretID
+		}
+
+	case VaultAuthMethodJWT:
+		if a.Role == "" {
+			return nil, fmt.Errorf("jwt auth: role is required")
+		}
+		jwt, err := readAuthFile("jwt_file", a.JWTFile)
+		if err != nil {
+			return nil, fmt.Errorf("jwt auth: %w", err)
+		}
+		data = map[string]interface{}{
+			"role": a.Role,
+			"jwt":  jwt,
+		}
+
+	case VaultAuthMethodCert:
+		// The certificate is the client certificate of the Vault client, so
+		// only the optional name of the certificate role is sent.
+		data = map[string]interface{}{}
+		if a.Role != "" {
+			data["name"] = a.Role
+		}
+
+	case VaultAuthMethodUserpass:
+		if a.Username == "" {
+			return nil, fmt.Errorf("userpass auth: username is required")
+		}
+		password := a.Password
+		if password == "" {
+			var err error
+			if password, err = readAuthFile("password_file", a.PasswordFile); err != nil {
+				return nil, fmt.Errorf("userpass auth: %w", err)
+			}
+		}
+		path = fmt.Sprintf("%s/%s", path, a.Username)
+		data = map[string]interface{}{"password": password}
+
+	default:
+		return nil, fmt.Errorf("unsupported auth method %q", a.Method)
+	}
+
+	secret, err := client.Logical().WriteWithContext(ctx, path, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s auth: login: %w", a.Method, err)
+	}
+	return secret, nil
+}
+
+// readAuthFile reads the credential in the file set by the given option.
+func readAuthFile(option, path string) (string, error) {
+	if path == "" {
+		return "", fmt.Errorf("%s is required", option)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", option, err)
+	}
+	s := strings.TrimSpace(string(b))
+	if s == "" {
+		return "", fmt.Errorf("%s %q is empty", option, path)
+	}
+	return s, nil
+}
+
+// unwrapSecretID returns the secret_id wrapped in the given response-wrapping
+// token.
+func unwrapSecretID(ctx context.Context, client *vaultapi.Client, wrappingToken string) (string, error) {
+	secret, err := client.Logical().UnwrapWithContext(ctx, wrappingToken)
+	// Unwrapping sets the wrapping token on a client without a token, and it
+	// must not be sent with the login.
+	client.ClearToken()
+	switch {
+	case err != nil:
+		return "", fmt.Errorf("unwrapping secret_id: %w", err)
+	case secret == nil:
+		return "", fmt.Errorf("unwrapping secret_id: no secret")
+	}
+	secretID, ok := secret.Data["secret_id"].(string)
+	if !ok || secretID == "" {
+		return "", fmt.Errorf("unwrapping secret_id: no secret_id returned")
+	}
+	return secretID, nil
+}
+
+// vaultLogin logs in to Vault with the given auth method and sets the new
+// token on the client. The login is done with a copy of the client, since other
+// requests may be using the client at the same time and must keep sending the
+// current token until the new one is set.
+func vaultLogin(ctx context.Context, client *vaultapi.Client, auth vaultapi.AuthMethod) (*vaultapi.Secret, error) {
+	login, err := client.CloneWithHeaders()
+	if err != nil {
+		return nil, err
+	}
+
+	// Login endpoints do not need a token, and an expired token must not be
+	// sent with the login.
+	login.ClearToken()
+	secret, err := login.Auth().Login(ctx, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	client.SetToken(login.Token())
+	return secret, nil
+}
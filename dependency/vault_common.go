@@ -11,6 +11,7 @@ import (
 	"sync"
 	"time"
 
+	metrics "github.com/hashicorp/go-metrics"
 	"github.com/hashicorp/vault/api"
 )
 
@@ -77,6 +78,43 @@ type renewer interface {
 	secrets() (*Secret, *api.Secret)
 }
 
+// VaultLeaser is a Vault dependency that holds a lease on its secret.
+type VaultLeaser interface {
+	Dependency
+
+	// LeaseID returns the ID of the lease on the last secret, or an empty
+	// string if the secret has no lease.
+	LeaseID() string
+}
+
+// VaultLeaseStore stores the secrets of Vault dependencies by dependency, so
+// their leases can be renewed and the secrets reused after a restart.
+type VaultLeaseStore interface {
+	Get(key string) *api.Secret
+	Put(key string, secret *api.Secret) error
+	Delete(key string) error
+}
+
+// vaultLease holds the ID of the lease on the last secret of a query. It is
+// locked since the lease is read when revoking it, while the query may still
+// be fetching.
+type vaultLease struct {
+	sync.Mutex
+	id string
+}
+
+func (l *vaultLease) set(id string) {
+	l.Lock()
+	defer l.Unlock()
+	l.id = id
+}
+
+func (l *vaultLease) get() string {
+	l.Lock()
+	defer l.Unlock()
+	return l.id
+}
+
 func renewSecret(clients *ClientSet, d renewer) error {
 	log.Printf("[TRACE] %s: starting renewer", d)
 
@@ -96,11 +134,13 @@ func renewSecret(clients *ClientSet, d renewer) error {
 		case err := <-renewer.DoneCh():
 			if err != nil {
 				log.Printf("[WARN] %s: failed to renew: %s", d, err)
+				metrics.IncrCounter([]string{"vault", "lease", "renew_error"}, 1)
 			}
 			log.Printf("[WARN] %s: renewer done (maybe the lease expired)", d)
 			return nil
 		case renewal := <-renewer.RenewCh():
 			log.Printf("[TRACE] %s: successfully renewed", d)
+			metrics.IncrCounter([]string{"vault", "lease", "renew"}, 1)
 			printVaultWarnings(d, renewal.Secret.Warnings)
 			updateSecret(secret, renewal.Secret)
 		case <-d.stopChan():
@@ -0,0 +1,461 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Ensure implements
+var _ Dependency = (*VaultPKISignQuery)(nil)
+
+const (
+	// PKIKeyTypeRSA, PKIKeyTypeEC and PKIKeyTypeEd25519 are the types of
+	// private keys that can be generated for signing.
+	PKIKeyTypeRSA     = "rsa"
+	PKIKeyTypeEC      = "ec"
+	PKIKeyTypeEd25519 = "ed25519"
+)
+
+var (
+	// defaultPKIKeyBits are the default sizes of generated keys by type.
+	defaultPKIKeyBits = map[string]int{
+		PKIKeyTypeRSA: 2048,
+		PKIKeyTypeEC:  256,
+	}
+
+	// pkiCurves are the curves of the supported EC key sizes.
+	pkiCurves = map[int]elliptic.Curve{
+		224: elliptic.P224(),
+		256: elliptic.P256(),
+		384: elliptic.P384(),
+		521: elliptic.P521(),
+	}
+)
+
+// VaultPKISignQuery is the dependency to Vault for a certificate signed by the
+// sign endpoint of a PKI secrets engine, from a CSR for a private key that is
+// generated locally and never sent to Vault.
+type VaultPKISignQuery struct {
+	stopCh  chan struct{}
+	sleepCh chan time.Duration
+
+	pkiPath string
+	data    map[string]interface{}
+
+	// keyType and keyBits set the type and size of generated keys. If
+	// rotateKey is true, a new key is generated for every certificate,
+	// otherwise the key in keyFile is reused.
+	keyType   string
+	keyBits   int
+	rotateKey bool
+
+	// keyFile, certFile and chainFile are the files the key, certificate and
+	// CA chain are written to with their permissions. If certFile is empty,
+	// the template destination is read to find the current certificate.
+	keyFile    string
+	keyPerms   os.FileMode
+	certFile   string
+	certPerms  os.FileMode
+	chainFile  string
+	chainPerms os.FileMode
+	destPath   string
+}
+
+// NewVaultPKISignQuery creates a new dependency for signing certificates at
+// the given sign path. The key_type, key_bits, rotate_key, key_file,
+// key_perms, cert_file, cert_perms, chain_file and chain_perms options in
+// data configure the key and files, and are not sent to Vault.
+func NewVaultPKISignQuery(urlpath, destPath string, data map[string]interface{}) (*VaultPKISignQuery, error) {
+	urlpath = strings.TrimSpace(urlpath)
+	urlpath = strings.Trim(urlpath, "/")
+	if urlpath == "" {
+		return nil, fmt.Errorf("vault.pki.sign: invalid format: %q", urlpath)
+	}
+
+	secretURL, err := url.Parse(urlpath)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &VaultPKISignQuery{
+		stopCh:     make(chan struct{}, 1),
+		sleepCh:    make(chan time.Duration, 1),
+		pkiPath:    secretURL.Path,
+		data:       make(map[string]interface{}, len(data)),
+		keyType:    PKIKeyTypeRSA,
+		keyPerms:   0o600,
+		certPerms:  0o644,
+		chainPerms: 0o644,
+		destPath:   destPath,
+	}
+
+	for k, v := range data {
+		s := fmt.Sprint(v)
+		switch k {
+		case "key_type":
+			d.keyType = s
+		case "key_bits":
+			if d.keyBits, err = strconv.Atoi(s); err != nil {
+				return nil, fmt.Errorf("vault.pki.sign: invalid key_bits %q", s)
+			}
+		case "rotate_key":
+			if d.rotateKey, err = strconv.ParseBool(s); err != nil {
+				return nil, fmt.Errorf("vault.pki.sign: invalid rotate_key %q", s)
+			}
+		case "key_file":
+			d.keyFile = s
+		case "cert_file":
+			d.certFile = s
+		case "chain_file":
+			d.chainFile = s
+		case "key_perms":
+			err = parsePKIFilePerms(k, s, &d.keyPerms)
+		case "cert_perms":
+			err = parsePKIFilePerms(k, s, &d.certPerms)
+		case "chain_perms":
+			err = parsePKIFilePerms(k, s, &d.chainPerms)
+		default:
+			d.data[k] = v
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if d.keyFile == "" {
+		return nil, fmt.Errorf("vault.pki.sign: key_file is required")
+	}
+
+	switch d.keyType {
+	case PKIKeyTypeRSA:
+		if d.keyBits == 0 {
+			d.keyBits = defaultPKIKeyBits[d.keyType]
+		}
+		if d.keyBits != 2048 && d.keyBits != 3072 && d.keyBits != 4096 {
+			return nil, fmt.Errorf("vault.pki.sign: invalid rsa key_bits %d", d.keyBits)
+		}
+	case PKIKeyTypeEC:
+		if d.keyBits == 0 {
+			d.keyBits = defaultPKIKeyBits[d.keyType]
+		}
+		if _, ok := pkiCurves[d.keyBits]; !ok {
+			return nil, fmt.Errorf("vault.pki.sign: invalid ec key_bits %d", d.keyBits)
+		}
+	case PKIKeyTypeEd25519:
+	default:
+		return nil, fmt.Errorf("vault.pki.sign: unsupported key_type %q", d.keyType)
+	}
+
+	return d, nil
+}
+
+// parsePKIFilePerms parses the octal file permissions of the given option.
+func parsePKIFilePerms(option, s string, perms *os.FileMode) error {
+	p, err := strconv.ParseUint(s, 8, 32)
+	if err != nil {
+		return fmt.Errorf("vault.pki.sign: invalid %s %q", option, s)
+	}
+	*perms = os.FileMode(p)
+	return nil
+}
+
+// Fetch returns the current certificate if it is still good, and otherwise
+// signs a new certificate with Vault.
+func (d *VaultPKISignQuery) Fetch(clients *ClientSet, opts *QueryOptions) (interface{}, *ResponseMetadata, error) {
+	select {
+	case <-d.stopCh:
+		return nil, nil, ErrStopped
+	default:
+	}
+	select {
+	case dur := <-d.sleepCh:
+		time.Sleep(dur)
+	default:
+	}
+
+	needsRenewal := fmt.Errorf("needs renewal")
+	getPEMs := func(renew bool) (PemEncoded, error) {
+		key, keyPEM, err := d.privateKey(renew && d.rotateKey)
+		if err != nil {
+			return PemEncoded{}, err
+		}
+
+		rawPems, err := d.readPEMs()
+		if renew || err != nil || len(rawPems) == 0 {
+			rawPems, err = d.signCSR(clients, key, keyPEM)
+		}
+		if err != nil {
+			return PemEncoded{}, err
+		}
+
+		encPems, cert, err := pemsCert(rawPems)
+		if err != nil {
+			return encPems, err
+		}
+		encPems.Key = string(keyPEM)
+
+		// A certificate for another key, such as the one before a rotation,
+		// is renewed.
+		if cert == nil || !publicKeyEqual(cert.PublicKey, key.Public()) {
+			return encPems, needsRenewal
+		}
+
+		if sleepFor, ok := goodFor(cert); ok {
+			d.sleepCh <- sleepFor
+			return encPems, nil
+		}
+		return encPems, needsRenewal
+	}
+
+	encPems, err := getPEMs(false)
+	switch err {
+	case nil:
+	case needsRenewal:
+		encPems, err = getPEMs(true)
+		if err != nil {
+			return PemEncoded{}, nil, err
+		}
+	default:
+		return PemEncoded{}, nil, err
+	}
+	return respWithMetadata(encPems)
+}
+
+// privateKey returns the key in the key file. A new key is generated if there
+// is no key yet or if generate is true, and only written to the key file once
+// a certificate is signed for it.
+func (d *VaultPKISignQuery) privateKey(generate bool) (crypto.Signer, []byte, error) {
+	if !generate {
+		keyPEM, err := os.ReadFile(d.keyFile)
+		switch {
+		case err == nil:
+			key, err := parsePrivateKey(keyPEM)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "%s: parsing %s", d, d.keyFile)
+			}
+			return key, keyPEM, nil
+		case !os.IsNotExist(err):
+			return nil, nil, errors.Wrap(err, d.String())
+		}
+	}
+
+	key, err := generatePrivateKey(d.keyType, d.keyBits)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, d.String())
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, d.String())
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// generatePrivateKey generates a private key of the given type and size.
+func generatePrivateKey(keyType string, bits int) (crypto.Signer, error) {
+	switch keyType {
+	case PKIKeyTypeRSA:
+		return rsa.GenerateKey(rand.Reader, bits)
+	case PKIKeyTypeEC:
+		return ecdsa.GenerateKey(pkiCurves[bits], rand.Reader)
+	case PKIKeyTypeEd25519:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	}
+	return nil, fmt.Errorf("unsupported key type %q", keyType)
+}
+
+// parsePrivateKey parses a PEM encoded PKCS#8, PKCS#1 or SEC 1 private key.
+func parsePrivateKey(keyPEM []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded key found")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
+
+// publicKeyEqual returns true if the public keys are the same.
+func publicKeyEqual(a, b crypto.PublicKey) bool {
+	k, ok := a.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && k.Equal(b)
+}
+
+// readPEMs reads the current certificate and CA chain.
+func (d *VaultPKISignQuery) readPEMs() ([]byte, error) {
+	certFile := d.certFile
+	if certFile == "" {
+		certFile = d.destPath
+	}
+	rawPems, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.chainFile != "" {
+		chain, err := os.ReadFile(d.chainFile)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		rawPems = append(append(rawPems, '\n'), chain...)
+	}
+	return rawPems, nil
+}
+
+// signCSR has Vault sign a CSR for the key and writes the key, certificate and
+// CA chain to their files.
+func (d *VaultPKISignQuery) signCSR(clients *ClientSet, key crypto.Signer, keyPEM []byte) ([]byte, error) {
+	commonName, _ := d.data["common_name"].(string)
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: commonName},
+	}, key)
+	if err != nil {
+		return nil, errors.Wrap(err, d.String())
+	}
+
+	data := make(map[string]interface{}, len(d.data)+1)
+	for k, v := range d.data {
+		data[k] = v
+	}
+	data["csr"] = string(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE REQUEST",
+		Bytes: csr,
+	}))
+
+	vaultSecret, err := clients.Vault().Logical().Write(d.pkiPath, data)
+	switch {
+	case err != nil:
+		return nil, errors.Wrap(err, d.String())
+	case vaultSecret == nil:
+		return nil, fmt.Errorf("no secret exists at %s", d.pkiPath)
+	}
+	printVaultWarnings(d, vaultSecret.Warnings)
+
+	pems := bytes.Buffer{}
+	if cert, ok := vaultSecret.Data["certificate"].(string); ok {
+		pems.WriteString(cert + "\n")
+	}
+	if ca, ok := vaultSecret.Data["issuing_ca"].(string); ok {
+		pems.WriteString(ca + "\n")
+	}
+	if chain, ok := vaultSecret.Data["ca_chain"].([]interface{}); ok {
+		for _, item := range chain {
+			if item, ok := item.(string); ok {
+				pems.WriteString(item + "\n")
+			}
+		}
+	}
+
+	encPems, cert, err := pemsCert(pems.Bytes())
+	switch {
+	case err != nil:
+		return nil, errors.Wrap(err, d.String())
+	case cert == nil:
+		return nil, fmt.Errorf("%s: no certificate returned", d)
+	}
+
+	// The key is written first, so a certificate on disk always has its key.
+	if err := writePKIFile(d.keyFile, keyPEM, d.keyPerms); err != nil {
+		return nil, errors.Wrap(err, d.String())
+	}
+	if d.certFile != "" {
+		if err := writePKIFile(d.certFile, []byte(encPems.Cert), d.certPerms); err != nil {
+			return nil, errors.Wrap(err, d.String())
+		}
+	}
+	if d.chainFile != "" {
+		chain := []byte(strings.Join(encPems.CAChain, ""))
+		if err := writePKIFile(d.chainFile, chain, d.chainPerms); err != nil {
+			return nil, errors.Wrap(err, d.String())
+		}
+	}
+
+	return pems.Bytes(), nil
+}
+
+// writePKIFile atomically writes the file with the given permissions.
+func writePKIFile(path string, contents []byte, perms os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(contents); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), perms); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// CanShare returns if this dependency is shareable.
+func (d *VaultPKISignQuery) CanShare() bool {
+	return false
+}
+
+// Stop halts the given dependency's fetch.
+func (d *VaultPKISignQuery) Stop() {
+	close(d.stopCh)
+}
+
+// String returns the human-friendly version of this dependency.
+func (d *VaultPKISignQuery) String() string {
+	return fmt.Sprintf("vault.pki.sign(%s->%s)", d.pkiPath, d.keyFile)
+}
+
+// Type returns the type of this dependency.
+func (d *VaultPKISignQuery) Type() Type {
+	return TypeVault
+}
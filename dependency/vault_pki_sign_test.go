@@ -0,0 +1,371 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewVaultPKISignQuery(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		data map[string]interface{}
+		exp  *VaultPKISignQuery
+		err  bool
+	}{
+		{
+			"defaults",
+			map[string]interface{}{"key_file": "key.pem", "common_name": "foo"},
+			&VaultPKISignQuery{
+				pkiPath:    "pki/sign/role",
+				data:       map[string]interface{}{"common_name": "foo"},
+				keyType:    PKIKeyTypeRSA,
+				keyBits:    2048,
+				keyFile:    "key.pem",
+				keyPerms:   0o600,
+				certPerms:  0o644,
+				chainPerms: 0o644,
+				destPath:   "dest",
+			},
+			false,
+		},
+		{
+			"options",
+			map[string]interface{}{
+				"key_type":    "ec",
+				"key_bits":    "384",
+				"rotate_key":  "true",
+				"key_file":    "key.pem",
+				"key_perms":   "0400",
+				"cert_file":   "cert.pem",
+				"cert_perms":  "0640",
+				"chain_file":  "chain.pem",
+				"chain_perms": "0444",
+				"ttl":         "1h",
+			},
+			&VaultPKISignQuery{
+				pkiPath:    "pki/sign/role",
+				data:       map[string]interface{}{"ttl": "1h"},
+				keyType:    PKIKeyTypeEC,
+				keyBits:    384,
+				rotateKey:  true,
+				keyFile:    "key.pem",
+				keyPerms:   0o400,
+				certFile:   "cert.pem",
+				certPerms:  0o640,
+				chainFile:  "chain.pem",
+				chainPerms: 0o444,
+				destPath:   "dest",
+			},
+			false,
+		},
+		{
+			"ed25519",
+			map[string]interface{}{"key_type": "ed25519", "key_file": "key.pem"},
+			&VaultPKISignQuery{
+				pkiPath:    "pki/sign/role",
+				data:       map[string]interface{}{},
+				keyType:    PKIKeyTypeEd25519,
+				keyFile:    "key.pem",
+				keyPerms:   0o600,
+				certPerms:  0o644,
+				chainPerms: 0o644,
+				destPath:   "dest",
+			},
+			false,
+		},
+		{
+			"missing_key_file",
+			map[string]interface{}{},
+			nil,
+			true,
+		},
+		{
+			"invalid_key_type",
+			map[string]interface{}{"key_type": "dsa", "key_file": "key.pem"},
+			nil,
+			true,
+		},
+		{
+			"invalid_rsa_key_bits",
+			map[string]interface{}{"key_bits": "1024", "key_file": "key.pem"},
+			nil,
+			true,
+		},
+		{
+			"invalid_ec_key_bits",
+			map[string]interface{}{"key_type": "ec", "key_bits": "512", "key_file": "key.pem"},
+			nil,
+			true,
+		},
+		{
+			"invalid_perms",
+			map[string]interface{}{"key_perms": "0900", "key_file": "key.pem"},
+			nil,
+			true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			act, err := NewVaultPKISignQuery("/pki/sign/role/", "dest", tc.data)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			act.stopCh = nil
+			act.sleepCh = nil
+			assert.Equal(t, tc.exp, act)
+		})
+	}
+}
+
+func TestVaultPKISignQuery_Fetch(t *testing.T) {
+	t.Parallel()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, caKey.Public(), caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+	caPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}))
+
+	// signCert signs a certificate for the public key, valid for ttl.
+	signCert := func(t *testing.T, pub crypto.PublicKey, ttl time.Duration) string {
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(time.Now().UnixNano()),
+			Subject:      pkix.Name{CommonName: "foo.example.com"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(ttl),
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, caCert, pub, caKey)
+		require.NoError(t, err)
+		return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	}
+
+	// newClients returns clients for a Vault that signs CSRs at pki/sign/role
+	// and counts the signed certificates.
+	newClients := func(t *testing.T, signed *int32) *ClientSet {
+		addr := newVaultMockReversedProxy(t, vaultMock{
+			HandleCond: func(r *http.Request) bool {
+				return r.URL.Path == "/v1/pki/sign/role"
+			},
+			HandleJSON: func(_ *http.Request, data map[string]interface{}) interface{} {
+				atomic.AddInt32(signed, 1)
+				assert.Equal(t, "foo.example.com", data["common_name"])
+				assert.NotContains(t, data, "key_file")
+
+				block, _ := pem.Decode([]byte(data["csr"].(string)))
+				if !assert.NotNil(t, block) {
+					return nil
+				}
+				csr, err := x509.ParseCertificateRequest(block.Bytes)
+				if !assert.NoError(t, err) {
+					return nil
+				}
+				assert.NoError(t, csr.CheckSignature())
+				assert.Equal(t, "foo.example.com", csr.Subject.CommonName)
+
+				return &api.Secret{Data: map[string]interface{}{
+					"certificate": signCert(t, csr.PublicKey, time.Hour),
+					"issuing_ca":  caPEM,
+					"ca_chain":    []interface{}{caPEM},
+				}}
+			},
+		})
+
+		clients := NewClientSet()
+		require.NoError(t, clients.CreateVaultClient(&CreateVaultClientInput{
+			Address:         addr,
+			Token:           vaultToken,
+			ClientUserAgent: userAgent,
+		}))
+		return clients
+	}
+
+	newQuery := func(t *testing.T, dir string, options map[string]interface{}) *VaultPKISignQuery {
+		data := map[string]interface{}{
+			"common_name": "foo.example.com",
+			"key_file":    filepath.Join(dir, "key.pem"),
+			"cert_file":   filepath.Join(dir, "cert.pem"),
+			"chain_file":  filepath.Join(dir, "chain.pem"),
+		}
+		for k, v := range options {
+			data[k] = v
+		}
+		d, err := NewVaultPKISignQuery("pki/sign/role", "/dev/null", data)
+		require.NoError(t, err)
+		return d
+	}
+
+	fetch := func(t *testing.T, d *VaultPKISignQuery, clients *ClientSet) PemEncoded {
+		act, _, err := d.Fetch(clients, nil)
+		require.NoError(t, err)
+		<-d.sleepCh // drain, so the next fetch does not sleep
+		return act.(PemEncoded)
+	}
+
+	// certKey returns the key and the public key of the certificate on disk.
+	certKey := func(t *testing.T, dir string) (crypto.Signer, crypto.PublicKey) {
+		keyPEM, err := os.ReadFile(filepath.Join(dir, "key.pem"))
+		require.NoError(t, err)
+		key, err := parsePrivateKey(keyPEM)
+		require.NoError(t, err)
+
+		certPEM, err := os.ReadFile(filepath.Join(dir, "cert.pem"))
+		require.NoError(t, err)
+		_, cert, err := pemsCert(certPEM)
+		require.NoError(t, err)
+		require.NotNil(t, cert)
+		return key, cert.PublicKey
+	}
+
+	// expireCert replaces the certificate on disk with an expired one for the
+	// same key.
+	expireCert := func(t *testing.T, dir string) {
+		key, _ := certKey(t, dir)
+		cert := signCert(t, key.Public(), -time.Minute)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "cert.pem"), []byte(cert), 0o644))
+	}
+
+	for _, keyType := range []string{PKIKeyTypeRSA, PKIKeyTypeEC, PKIKeyTypeEd25519} {
+		keyType := keyType
+		t.Run(keyType, func(t *testing.T) {
+			t.Parallel()
+
+			var signed int32
+			clients := newClients(t, &signed)
+			dir := t.TempDir()
+			d := newQuery(t, dir, map[string]interface{}{"key_type": keyType})
+
+			pems := fetch(t, d, clients)
+			assert.EqualValues(t, 1, signed)
+			assert.Equal(t, caPEM, pems.CA)
+
+			key, pub := certKey(t, dir)
+			assert.True(t, publicKeyEqual(pub, key.Public()))
+			switch keyType {
+			case PKIKeyTypeRSA:
+				assert.IsType(t, &rsa.PrivateKey{}, key)
+			case PKIKeyTypeEC:
+				assert.IsType(t, &ecdsa.PrivateKey{}, key)
+			case PKIKeyTypeEd25519:
+				assert.IsType(t, ed25519.PrivateKey{}, key)
+			}
+
+			for file, perms := range map[string]os.FileMode{
+				"key.pem":   0o600,
+				"cert.pem":  0o644,
+				"chain.pem": 0o644,
+			} {
+				fi, err := os.Stat(filepath.Join(dir, file))
+				require.NoError(t, err)
+				assert.Equal(t, perms, fi.Mode().Perm(), file)
+			}
+			chain, err := os.ReadFile(filepath.Join(dir, "chain.pem"))
+			require.NoError(t, err)
+			assert.Equal(t, caPEM, string(chain))
+
+			// A good certificate is read from disk.
+			assert.Equal(t, pems, fetch(t, d, clients))
+			assert.EqualValues(t, 1, signed)
+		})
+	}
+
+	t.Run("reuses_key", func(t *testing.T) {
+		t.Parallel()
+
+		var signed int32
+		clients := newClients(t, &signed)
+		dir := t.TempDir()
+		d := newQuery(t, dir, map[string]interface{}{"key_type": "ec"})
+
+		first := fetch(t, d, clients)
+		expireCert(t, dir)
+		second := fetch(t, d, clients)
+
+		assert.EqualValues(t, 2, signed)
+		assert.NotEqual(t, first.Cert, second.Cert)
+		assert.Equal(t, first.Key, second.Key)
+	})
+
+	t.Run("rotates_key", func(t *testing.T) {
+		t.Parallel()
+
+		var signed int32
+		clients := newClients(t, &signed)
+		dir := t.TempDir()
+		d := newQuery(t, dir, map[string]interface{}{
+			"key_type":   "ec",
+			"rotate_key": "true",
+			"key_perms":  "0400",
+		})
+
+		first := fetch(t, d, clients)
+		expireCert(t, dir)
+		second := fetch(t, d, clients)
+
+		assert.EqualValues(t, 2, signed)
+		assert.NotEqual(t, first.Key, second.Key)
+		key, pub := certKey(t, dir)
+		assert.True(t, publicKeyEqual(pub, key.Public()))
+
+		fi, err := os.Stat(filepath.Join(dir, "key.pem"))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o400), fi.Mode().Perm())
+	})
+
+	t.Run("renews_cert_for_other_key", func(t *testing.T) {
+		t.Parallel()
+
+		var signed int32
+		clients := newClients(t, &signed)
+		dir := t.TempDir()
+		d := newQuery(t, dir, map[string]interface{}{"key_type": "ec"})
+
+		other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		cert := signCert(t, other.Public(), time.Hour)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "cert.pem"), []byte(cert), 0o644))
+
+		pems := fetch(t, d, clients)
+		assert.EqualValues(t, 1, signed)
+		assert.NotEqual(t, cert, pems.Cert)
+		key, pub := certKey(t, dir)
+		assert.True(t, publicKeyEqual(pub, key.Public()))
+	})
+}
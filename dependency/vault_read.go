@@ -16,7 +16,7 @@ import (
 )
 
 // Ensure implements
-var _ Dependency = (*VaultReadQuery)(nil)
+var _ VaultLeaser = (*VaultReadQuery)(nil)
 
 // VaultReadQuery is the dependency to Vault for a secret
 type VaultReadQuery struct {
@@ -31,6 +31,9 @@ type VaultReadQuery struct {
 
 	// vaultSecret is the actual Vault secret which we are renewing
 	vaultSecret *api.Secret
+
+	// lease is the lease on vaultSecret.
+	lease vaultLease
 }
 
 // NewVaultReadQuery creates a new datacenter dependency.
@@ -75,6 +78,10 @@ func (d *VaultReadQuery) Fetch(clients *ClientSet, opts *QueryOptions,
 
 	firstRun := d.secret == nil
 
+	// On the first run, reuse the secret from the lease store if its lease is
+	// still valid.
+	reused := firstRun && d.reuseStoredSecret(clients)
+
 	if !firstRun && vaultSecretRenewable(d.secret) {
 		err := renewSecret(clients, d)
 		if err != nil {
@@ -82,9 +89,11 @@ func (d *VaultReadQuery) Fetch(clients *ClientSet, opts *QueryOptions,
 		}
 	}
 
-	err := d.fetchSecret(clients)
-	if err != nil {
-		return nil, nil, errors.Wrap(err, d.String())
+	if !reused {
+		err := d.fetchSecret(clients)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, d.String())
+		}
 	}
 
 	if !vaultSecretRenewable(d.secret) {
@@ -107,10 +116,71 @@ func (d *VaultReadQuery) fetchSecret(clients *ClientSet) error {
 		d.vaultSecret = vaultSecret
 		// the cloned secret which will be exposed to the template
 		d.secret = transformSecret(vaultSecret)
+		d.lease.set(vaultSecret.LeaseID)
+		d.storeSecret(clients)
 	}
 	return err
 }
 
+// reuseStoredSecret renews the lease of the secret in the lease store and, if
+// the renewal succeeds, uses the stored secret instead of reading a new one.
+// It returns true if the stored secret is used.
+func (d *VaultReadQuery) reuseStoredSecret(clients *ClientSet) bool {
+	store := clients.VaultLeaseStore()
+	if store == nil {
+		return false
+	}
+	stored := store.Get(d.String())
+	if stored == nil {
+		return false
+	}
+
+	renewal, err := clients.Vault().Sys().Renew(stored.LeaseID, 0)
+	if err != nil || renewal == nil {
+		log.Printf("[DEBUG] %s: not reusing stored lease: %v", d, err)
+		if err := store.Delete(d.String()); err != nil {
+			log.Printf("[WARN] %s: failed to delete stored lease: %s", d, err)
+		}
+		return false
+	}
+	log.Printf("[INFO] %s: reusing stored lease", d)
+	printVaultWarnings(d, renewal.Warnings)
+
+	stored.LeaseDuration = renewal.LeaseDuration
+	stored.Renewable = renewal.Renewable
+
+	d.vaultSecret = stored
+	d.secret = transformSecret(stored)
+	d.lease.set(stored.LeaseID)
+	d.storeSecret(clients)
+	return true
+}
+
+// storeSecret saves the secret in the lease store, so its lease can be reused
+// after a restart. Only renewable leases are stored, since renewing the lease
+// is how a stored secret is checked to still be valid.
+func (d *VaultReadQuery) storeSecret(clients *ClientSet) {
+	store := clients.VaultLeaseStore()
+	if store == nil {
+		return
+	}
+
+	var err error
+	if d.vaultSecret.LeaseID != "" && d.vaultSecret.Renewable {
+		err = store.Put(d.String(), d.vaultSecret)
+	} else {
+		err = store.Delete(d.String())
+	}
+	if err != nil {
+		log.Printf("[WARN] %s: failed to store lease: %s", d, err)
+	}
+}
+
+// LeaseID returns the ID of the lease on the last secret.
+func (d *VaultReadQuery) LeaseID() string {
+	return d.lease.get()
+}
+
 func (d *VaultReadQuery) stopChan() chan struct{} {
 	return d.stopCh
 }
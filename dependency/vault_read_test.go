@@ -5,6 +5,7 @@ package dependency
 
 import (
 	"fmt"
+	"net/http"
 	"net/url"
 	"reflect"
 	"strings"
@@ -558,6 +559,119 @@ func TestVaultReadQuery_Fetch_PKI_Anonymous(t *testing.T) {
 	}
 }
 
+// memLeaseStore is an in-memory VaultLeaseStore.
+type memLeaseStore map[string]*api.Secret
+
+func (s memLeaseStore) Get(key string) *api.Secret { return s[key] }
+
+func (s memLeaseStore) Put(key string, secret *api.Secret) error {
+	s[key] = secret
+	return nil
+}
+
+func (s memLeaseStore) Delete(key string) error {
+	delete(s, key)
+	return nil
+}
+
+// TestVaultReadQuery_Fetch_LeaseStore asserts that vault.read reuses a stored
+// secret if its lease can be renewed, and reads a new secret otherwise.
+func TestVaultReadQuery_Fetch_LeaseStore(t *testing.T) {
+	t.Parallel()
+
+	const path = "database/creds/app"
+
+	newSecret := func(leaseID, username string) *api.Secret {
+		return &api.Secret{
+			LeaseID:       leaseID,
+			LeaseDuration: 3600,
+			Renewable:     true,
+			Data:          map[string]interface{}{"username": username},
+		}
+	}
+
+	newClients := func(t *testing.T, store VaultLeaseStore, mocks ...vaultMock) *ClientSet {
+		clients := NewClientSet()
+		err := clients.CreateVaultClient(&CreateVaultClientInput{
+			Address:         newVaultMockReversedProxy(t, mocks...),
+			Token:           vaultToken,
+			ClientUserAgent: userAgent,
+			LeaseStore:      store,
+		})
+		require.NoError(t, err)
+		return clients
+	}
+
+	t.Run("reuses_renewed_lease", func(t *testing.T) {
+		t.Parallel()
+
+		d, err := NewVaultReadQuery(path)
+		require.NoError(t, err)
+
+		store := memLeaseStore{d.String(): newSecret("lease", "stored")}
+		clients := newClients(t, store,
+			vaultMock{
+				HandleCond: func(r *http.Request) bool {
+					return r.URL.Path == "/v1/sys/leases/renew"
+				},
+				HandleJSON: func(_ *http.Request, data map[string]interface{}) interface{} {
+					assert.Equal(t, "lease", data["lease_id"], data)
+
+					return &api.Secret{LeaseID: "lease", LeaseDuration: 60, Renewable: true}
+				},
+			},
+			vaultMock{
+				HandleCond: func(r *http.Request) bool {
+					return r.URL.Path == "/v1/"+path
+				},
+				HandleJSON: func(_ *http.Request, _ map[string]interface{}) interface{} {
+					t.Error("unexpected read of a new secret")
+
+					return newSecret("new", "new")
+				},
+			},
+		)
+
+		act, _, err := d.Fetch(clients, nil)
+		require.NoError(t, err)
+
+		secret := act.(*Secret)
+		assert.Equal(t, "stored", secret.Data["username"])
+		assert.Equal(t, 60, secret.LeaseDuration)
+		assert.Equal(t, "lease", d.LeaseID())
+		assert.Equal(t, 60, store[d.String()].LeaseDuration)
+	})
+
+	t.Run("reads_on_failed_renewal", func(t *testing.T) {
+		t.Parallel()
+
+		d, err := NewVaultReadQuery(path)
+		require.NoError(t, err)
+
+		// The renewal of the unknown lease is proxied to Vault and fails.
+		store := memLeaseStore{d.String(): newSecret("unknown", "stored")}
+		clients := newClients(t, store,
+			vaultMock{
+				HandleCond: func(r *http.Request) bool {
+					return r.URL.Path == "/v1/"+path
+				},
+				HandleJSON: func(_ *http.Request, _ map[string]interface{}) interface{} {
+					return newSecret("new", "new")
+				},
+			},
+		)
+
+		act, _, err := d.Fetch(clients, nil)
+		require.NoError(t, err)
+
+		secret := act.(*Secret)
+		assert.Equal(t, "new", secret.Data["username"])
+		assert.Equal(t, "new", d.LeaseID())
+		require.NotNil(t, store[d.String()])
+		assert.Equal(t, "new", store[d.String()].LeaseID)
+	})
+}
+
 // TestVaultReadQuery_Fetch_NonSecrets asserts that vault.read can fetch a
 // non-secret
 func TestVaultReadQuery_Fetch_NonSecrets(t *testing.T) {
@@ -4,6 +4,10 @@
 package dependency
 
 import (
+	"context"
+	"log"
+	"time"
+
 	"github.com/hashicorp/vault/api"
 	"github.com/pkg/errors"
 )
@@ -16,6 +20,10 @@ type VaultTokenQuery struct {
 	stopCh      chan struct{}
 	secret      *Secret
 	vaultSecret *api.Secret
+
+	// login is true if the query logs in to Vault again once the token can no
+	// longer be renewed.
+	login bool
 }
 
 // NewVaultTokenQuery creates a new dependency.
@@ -34,6 +42,14 @@ func NewVaultTokenQuery(token string) (*VaultTokenQuery, error) {
 	}, nil
 }
 
+// EnableLogin makes the query log in to Vault again with the auth method of
+// the Vault client once the token can no longer be renewed, instead of
+// returning ErrLeaseExpired. The new token is set on the client and its secret
+// is returned.
+func (d *VaultTokenQuery) EnableLogin() {
+	d.login = true
+}
+
 // Fetch queries the Vault API
 func (d *VaultTokenQuery) Fetch(clients *ClientSet, opts *QueryOptions,
 ) (interface{}, *ResponseMetadata, error) {
@@ -48,9 +64,38 @@ func (d *VaultTokenQuery) Fetch(clients *ClientSet, opts *QueryOptions,
 		if err != nil {
 			return nil, nil, errors.Wrap(err, d.String())
 		}
+	} else if d.login {
+		// The token from the last login cannot be renewed, so use it until it
+		// is about to expire.
+		dur, err := leaseCheckWait(d.secret)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, d.String())
+		}
+		log.Printf("[TRACE] %s: non-renewable token, logging in again in %s", d, dur)
+
+		select {
+		case <-time.After(dur):
+		case <-d.stopCh:
+			return nil, nil, ErrStopped
+		}
+	}
+
+	if !d.login {
+		return nil, nil, ErrLeaseExpired
 	}
 
-	return nil, nil, ErrLeaseExpired
+	log.Printf("[DEBUG] %s: logging in to vault again", d)
+
+	vaultSecret, err := clients.VaultLogin(context.Background())
+	if err != nil {
+		return nil, nil, errors.Wrap(err, d.String())
+	}
+	printVaultWarnings(d, vaultSecret.Warnings)
+
+	d.vaultSecret = vaultSecret
+	d.secret = transformSecret(vaultSecret)
+
+	return respWithMetadata(d.secret)
 }
 
 func (d *VaultTokenQuery) stopChan() chan struct{} {
@@ -0,0 +1,309 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Ensure implements
+var _ Dependency = (*VaultTransitQuery)(nil)
+
+const (
+	// TransitEncrypt, TransitDecrypt, TransitSign and TransitHMAC are the
+	// supported operations of the Vault transit secrets engine.
+	TransitEncrypt = "encrypt"
+	TransitDecrypt = "decrypt"
+	TransitSign    = "sign"
+	TransitHMAC    = "hmac"
+)
+
+// transitInputKey is the key of the HMAC that identifies the input of a transit
+// query, so that the input never shows up in logs. It is random, since a plain
+// hash of a low-entropy input could be reversed.
+var transitInputKey = func() []byte {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return b
+}()
+
+// TransitEncrypted is the result of the transit encrypt operation.
+type TransitEncrypted struct {
+	Ciphertext string
+	KeyVersion int
+}
+
+// String returns the ciphertext.
+func (t TransitEncrypted) String() string { return t.Ciphertext }
+
+// TransitDecrypted is the result of the transit decrypt operation.
+type TransitDecrypted struct {
+	Plaintext string
+}
+
+// String returns the plaintext.
+func (t TransitDecrypted) String() string { return t.Plaintext }
+
+// TransitSigned is the result of the transit sign operation.
+type TransitSigned struct {
+	Signature  string
+	KeyVersion int
+}
+
+// String returns the signature.
+func (t TransitSigned) String() string { return t.Signature }
+
+// TransitHMACed is the result of the transit hmac operation.
+type TransitHMACed struct {
+	HMAC       string
+	KeyVersion int
+}
+
+// String returns the HMAC.
+func (t TransitHMACed) String() string { return t.HMAC }
+
+// VaultTransitQuery is the dependency to a Vault transit key for encrypting,
+// decrypting, signing or computing the HMAC of an input. The result is kept
+// until the latest version of the key changes, since Vault returns a new
+// ciphertext for every encryption of the same input.
+type VaultTransitQuery struct {
+	stopCh chan struct{}
+
+	op      string
+	mount   string
+	keyName string
+	input   string
+	data    map[string]interface{}
+	id      string
+
+	// version is the latest version of the key when result was computed.
+	version int
+	result  interface{}
+}
+
+// NewVaultTransitQuery creates a new transit dependency for the given
+// operation with the key at the given "<mount>/<key name>" path. The data is
+// sent to Vault with the input.
+func NewVaultTransitQuery(op, keyPath, input string, data map[string]interface{}) (*VaultTransitQuery, error) {
+	switch op {
+	case TransitEncrypt, TransitDecrypt, TransitSign, TransitHMAC:
+	default:
+		return nil, fmt.Errorf("vault.transit: unsupported operation %q", op)
+	}
+
+	keyPath = strings.TrimSpace(keyPath)
+	keyPath = strings.Trim(keyPath, "/")
+	mount, keyName := path.Split(keyPath)
+	mount = strings.Trim(mount, "/")
+	if mount == "" || keyName == "" {
+		return nil, fmt.Errorf("vault.transit.%s: invalid format: %q", op, keyPath)
+	}
+
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+
+	return &VaultTransitQuery{
+		stopCh:  make(chan struct{}, 1),
+		op:      op,
+		mount:   mount,
+		keyName: keyName,
+		input:   input,
+		data:    data,
+		id:      transitInputID(input, data),
+	}, nil
+}
+
+// transitInputID identifies the input and data of a query without revealing
+// them.
+func transitInputID(input string, data map[string]interface{}) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := hmac.New(sha256.New, transitInputKey)
+	h.Write([]byte(input))
+	for _, k := range keys {
+		fmt.Fprintf(h, "\x00%s=%v", k, data[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// Fetch queries the Vault API
+func (d *VaultTransitQuery) Fetch(clients *ClientSet, opts *QueryOptions) (interface{}, *ResponseMetadata, error) {
+	select {
+	case <-d.stopCh:
+		return nil, nil, ErrStopped
+	default:
+	}
+
+	opts = opts.Merge(&QueryOptions{})
+
+	// If this is not the first query, poll to simulate blocking-queries.
+	if opts.WaitIndex != 0 {
+		dur := VaultDefaultLeaseDuration
+		log.Printf("[TRACE] %s: long polling for %s", d, dur)
+
+		select {
+		case <-d.stopCh:
+			return nil, nil, ErrStopped
+		case <-time.After(dur):
+		}
+	}
+
+	version, err := d.latestVersion(clients)
+	if err != nil {
+		// Without access to the key, a new version of the key is not noticed,
+		// but the operation itself may still be allowed.
+		log.Printf("[WARN] %s: failed to read key version: %s", d, err)
+	}
+
+	if d.result != nil && (err != nil || version == d.version) {
+		log.Printf("[TRACE] %s: key version %d unchanged", d, d.version)
+		return respWithMetadata(d.result)
+	}
+
+	result, err := d.run(clients)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, d.String())
+	}
+	d.version = version
+	d.result = result
+
+	return respWithMetadata(d.result)
+}
+
+// latestVersion returns the latest version of the key.
+func (d *VaultTransitQuery) latestVersion(clients *ClientSet) (int, error) {
+	keyPath := fmt.Sprintf("%s/keys/%s", d.mount, d.keyName)
+	secret, err := clients.Vault().Logical().Read(keyPath)
+	switch {
+	case err != nil:
+		return 0, err
+	case secret == nil:
+		return 0, fmt.Errorf("no key exists at %s", keyPath)
+	}
+	return transitInt(secret.Data["latest_version"]), nil
+}
+
+// run performs the operation with the input.
+func (d *VaultTransitQuery) run(clients *ClientSet) (interface{}, error) {
+	data := make(map[string]interface{}, len(d.data)+1)
+	for k, v := range d.data {
+		data[k] = v
+	}
+	if d.op == TransitDecrypt {
+		data["ciphertext"] = d.input
+	} else {
+		field := "input"
+		if d.op == TransitEncrypt {
+			field = "plaintext"
+		}
+		data[field] = base64.StdEncoding.EncodeToString([]byte(d.input))
+	}
+
+	opPath := fmt.Sprintf("%s/%s/%s", d.mount, d.op, d.keyName)
+	log.Printf("[TRACE] %s: PUT %s", d, opPath)
+	secret, err := clients.Vault().Logical().Write(opPath, data)
+	switch {
+	case err != nil:
+		return nil, err
+	case secret == nil:
+		return nil, fmt.Errorf("no response from %s", opPath)
+	}
+	printVaultWarnings(d, secret.Warnings)
+
+	keyVersion := transitInt(secret.Data["key_version"])
+	switch d.op {
+	case TransitEncrypt:
+		ciphertext, ok := secret.Data["ciphertext"].(string)
+		if !ok {
+			return nil, fmt.Errorf("no ciphertext returned")
+		}
+		return TransitEncrypted{Ciphertext: ciphertext, KeyVersion: keyVersion}, nil
+	case TransitDecrypt:
+		encoded, ok := secret.Data["plaintext"].(string)
+		if !ok {
+			return nil, fmt.Errorf("no plaintext returned")
+		}
+		plaintext, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, errors.Wrap(err, "decoding plaintext")
+		}
+		return TransitDecrypted{Plaintext: string(plaintext)}, nil
+	case TransitSign:
+		signature, ok := secret.Data["signature"].(string)
+		if !ok {
+			return nil, fmt.Errorf("no signature returned")
+		}
+		return TransitSigned{Signature: signature, KeyVersion: keyVersion}, nil
+	default:
+		mac, ok := secret.Data["hmac"].(string)
+		if !ok {
+			return nil, fmt.Errorf("no hmac returned")
+		}
+		return TransitHMACed{HMAC: mac, KeyVersion: keyVersion}, nil
+	}
+}
+
+// transitInt returns the integer in a Vault response.
+func transitInt(v interface{}) int {
+	switch v := v.(type) {
+	case json.Number:
+		i, _ := v.Int64()
+		return int(i)
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
+
+// Op returns the transit operation of the query.
+func (d *VaultTransitQuery) Op() string {
+	return d.op
+}
+
+// KeyPath returns the "<mount>/<key name>" path of the transit key.
+func (d *VaultTransitQuery) KeyPath() string {
+	return d.mount + "/" + d.keyName
+}
+
+// CanShare returns if this dependency is shareable.
+func (d *VaultTransitQuery) CanShare() bool {
+	return false
+}
+
+// Stop halts the given dependency's fetch.
+func (d *VaultTransitQuery) Stop() {
+	close(d.stopCh)
+}
+
+// String returns the human-friendly version of this dependency.
+func (d *VaultTransitQuery) String() string {
+	return fmt.Sprintf("vault.transit.%s(%s@%s)", d.op, d.KeyPath(), d.id)
+}
+
+// Type returns the type of this dependency.
+func (d *VaultTransitQuery) Type() Type {
+	return TypeVault
+}
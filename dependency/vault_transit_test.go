@@ -0,0 +1,227 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package dependency
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewVaultTransitQuery(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		op      string
+		keyPath string
+		mount   string
+		keyName string
+		err     bool
+	}{
+		{
+			"key",
+			TransitEncrypt,
+			"transit/my-key",
+			"transit",
+			"my-key",
+			false,
+		},
+		{
+			"nested_mount",
+			TransitHMAC,
+			"/team/transit/my-key/",
+			"team/transit",
+			"my-key",
+			false,
+		},
+		{
+			"no_mount",
+			TransitSign,
+			"my-key",
+			"",
+			"",
+			true,
+		},
+		{
+			"empty",
+			TransitDecrypt,
+			"",
+			"",
+			"",
+			true,
+		},
+		{
+			"invalid_op",
+			"rewrap",
+			"transit/my-key",
+			"",
+			"",
+			true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			act, err := NewVaultTransitQuery(tc.op, tc.keyPath, "input", nil)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.mount, act.mount)
+			assert.Equal(t, tc.keyName, act.keyName)
+		})
+	}
+}
+
+func TestVaultTransitQuery_String(t *testing.T) {
+	t.Parallel()
+
+	newQuery := func(input string, data map[string]interface{}) *VaultTransitQuery {
+		d, err := NewVaultTransitQuery(TransitEncrypt, "transit/my-key", input, data)
+		require.NoError(t, err)
+		return d
+	}
+
+	d := newQuery("hunter2", nil)
+	assert.True(t, strings.HasPrefix(d.String(), "vault.transit.encrypt(transit/my-key@"), d.String())
+	assert.NotContains(t, d.String(), "hunter2")
+
+	assert.Equal(t, d.String(), newQuery("hunter2", nil).String())
+	assert.NotEqual(t, d.String(), newQuery("hunter3", nil).String())
+	assert.NotEqual(t, d.String(), newQuery("hunter2",
+		map[string]interface{}{"context": "Zm9v"}).String())
+}
+
+func TestVaultTransitQuery_Fetch(t *testing.T) {
+	t.Parallel()
+
+	// newClients returns clients for a Vault with the transit key
+	// transit/my-key at the given version, counting the operations.
+	newClients := func(t *testing.T, version, calls *int32) *ClientSet {
+		addr := newVaultMockReversedProxy(t,
+			vaultMock{
+				HandleCond: func(r *http.Request) bool {
+					return r.URL.Path == "/v1/transit/keys/my-key"
+				},
+				HandleJSON: func(_ *http.Request, _ map[string]interface{}) interface{} {
+					return &api.Secret{Data: map[string]interface{}{
+						"latest_version": atomic.LoadInt32(version),
+					}}
+				},
+			},
+			vaultMock{
+				HandleCond: func(r *http.Request) bool {
+					return strings.HasPrefix(r.URL.Path, "/v1/transit/") &&
+						strings.HasSuffix(r.URL.Path, "/my-key")
+				},
+				HandleJSON: func(r *http.Request, data map[string]interface{}) interface{} {
+					n := atomic.AddInt32(calls, 1)
+					v := atomic.LoadInt32(version)
+					out := fmt.Sprintf("vault:v%d:%d", v, n)
+
+					switch r.URL.Path {
+					case "/v1/transit/encrypt/my-key":
+						assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("secret")), data["plaintext"])
+						return &api.Secret{Data: map[string]interface{}{"ciphertext": out, "key_version": v}}
+					case "/v1/transit/decrypt/my-key":
+						assert.Equal(t, "vault:v1:abc", data["ciphertext"])
+						return &api.Secret{Data: map[string]interface{}{
+							"plaintext": base64.StdEncoding.EncodeToString([]byte("secret")),
+						}}
+					case "/v1/transit/sign/my-key":
+						assert.Equal(t, "sha2-512", data["hash_algorithm"])
+						return &api.Secret{Data: map[string]interface{}{"signature": out, "key_version": v}}
+					case "/v1/transit/hmac/my-key":
+						return &api.Secret{Data: map[string]interface{}{"hmac": out, "key_version": v}}
+					}
+					t.Errorf("unexpected request to %s", r.URL.Path)
+					return nil
+				},
+			},
+		)
+
+		clients := NewClientSet()
+		require.NoError(t, clients.CreateVaultClient(&CreateVaultClientInput{
+			Address:         addr,
+			Token:           vaultToken,
+			ClientUserAgent: userAgent,
+		}))
+		return clients
+	}
+
+	fetch := func(t *testing.T, d *VaultTransitQuery, clients *ClientSet) interface{} {
+		act, rm, err := d.Fetch(clients, nil)
+		require.NoError(t, err)
+		require.NotNil(t, rm)
+		return act
+	}
+
+	t.Run("encrypt", func(t *testing.T) {
+		t.Parallel()
+
+		version, calls := int32(1), int32(0)
+		clients := newClients(t, &version, &calls)
+		d, err := NewVaultTransitQuery(TransitEncrypt, "transit/my-key", "secret", nil)
+		require.NoError(t, err)
+
+		exp := TransitEncrypted{Ciphertext: "vault:v1:1", KeyVersion: 1}
+		assert.Equal(t, exp, fetch(t, d, clients))
+
+		// The same key version returns the same ciphertext.
+		assert.Equal(t, exp, fetch(t, d, clients))
+		assert.EqualValues(t, 1, calls)
+
+		// A new key version encrypts again.
+		atomic.StoreInt32(&version, 2)
+		exp = TransitEncrypted{Ciphertext: "vault:v2:2", KeyVersion: 2}
+		assert.Equal(t, exp, fetch(t, d, clients))
+		assert.EqualValues(t, 2, calls)
+		assert.Equal(t, "vault:v2:2", exp.String())
+	})
+
+	t.Run("decrypt", func(t *testing.T) {
+		t.Parallel()
+
+		version, calls := int32(1), int32(0)
+		clients := newClients(t, &version, &calls)
+		d, err := NewVaultTransitQuery(TransitDecrypt, "transit/my-key", "vault:v1:abc", nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, TransitDecrypted{Plaintext: "secret"}, fetch(t, d, clients))
+	})
+
+	t.Run("sign", func(t *testing.T) {
+		t.Parallel()
+
+		version, calls := int32(3), int32(0)
+		clients := newClients(t, &version, &calls)
+		d, err := NewVaultTransitQuery(TransitSign, "transit/my-key", "secret",
+			map[string]interface{}{"hash_algorithm": "sha2-512"})
+		require.NoError(t, err)
+
+		assert.Equal(t, TransitSigned{Signature: "vault:v3:1", KeyVersion: 3}, fetch(t, d, clients))
+	})
+
+	t.Run("hmac", func(t *testing.T) {
+		t.Parallel()
+
+		version, calls := int32(1), int32(0)
+		clients := newClients(t, &version, &calls)
+		d, err := NewVaultTransitQuery(TransitHMAC, "transit/my-key", "secret", nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, TransitHMACed{HMAC: "vault:v1:1", KeyVersion: 1}, fetch(t, d, clients))
+		assert.Equal(t, TransitHMACed{HMAC: "vault:v1:1", KeyVersion: 1}, fetch(t, d, clients))
+		assert.EqualValues(t, 1, calls)
+	})
+}
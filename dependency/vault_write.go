@@ -18,7 +18,7 @@ import (
 )
 
 // Ensure implements
-var _ Dependency = (*VaultWriteQuery)(nil)
+var _ VaultLeaser = (*VaultWriteQuery)(nil)
 
 // VaultWriteQuery is the dependency to Vault for a secret
 type VaultWriteQuery struct {
@@ -32,6 +32,9 @@ type VaultWriteQuery struct {
 
 	// vaultSecret is the actual Vault secret which we are renewing
 	vaultSecret *api.Secret
+
+	// lease is the lease on vaultSecret.
+	lease vaultLease
 }
 
 // NewVaultWriteQuery creates a new datacenter dependency.
@@ -89,6 +92,7 @@ func (d *VaultWriteQuery) Fetch(clients *ClientSet, opts *QueryOptions,
 	d.vaultSecret = vaultSecret
 	// cloned secret which will be exposed to the template
 	d.secret = transformSecret(vaultSecret)
+	d.lease.set(vaultSecret.LeaseID)
 
 	if !vaultSecretRenewable(d.secret) {
 		dur, err := leaseCheckWait(d.secret)
@@ -112,6 +116,11 @@ func (d *VaultWriteQuery) secrets() (*Secret, *api.Secret) {
 	return d.secret, d.vaultSecret
 }
 
+// LeaseID returns the ID of the lease on the last secret.
+func (d *VaultWriteQuery) LeaseID() string {
+	return d.lease.get()
+}
+
 // CanShare returns if this dependency is shareable.
 func (d *VaultWriteQuery) CanShare() bool {
 	return false
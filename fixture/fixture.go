@@ -0,0 +1,429 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+// Package fixture renders templates against fixture data instead of live
+// Consul, Vault and Nomad clusters, and compares the output to golden files.
+package fixture
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/consul-template/config"
+	dep "github.com/hashicorp/consul-template/dependency"
+	"github.com/hashicorp/consul-template/renderer"
+	"github.com/hashicorp/consul-template/template"
+	"github.com/hashicorp/consul/api"
+	nomadapi "github.com/hashicorp/nomad/api"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Fixtures maps the string form of a dependency, for example
+// "health.service(web|passing)" or "vault.read(secret/foo)", to the data that
+// is returned for it.
+type Fixtures map[string]interface{}
+
+// Load reads fixtures from the JSON or YAML file at the given path. Files
+// ending in ".json" are parsed as JSON, everything else as YAML.
+func Load(path string) (Fixtures, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "fixture")
+	}
+
+	f := make(Fixtures)
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		if err := json.Unmarshal(b, &f); err != nil {
+			return nil, errors.Wrapf(err, "fixture: %s", path)
+		}
+		return f, nil
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(b, &raw); err != nil {
+		return nil, errors.Wrapf(err, "fixture: %s", path)
+	}
+	for k, v := range raw {
+		f[k] = normalizeYAML(v)
+	}
+	return f, nil
+}
+
+// normalizeYAML converts the map[interface{}]interface{} values produced by
+// the YAML decoder into map[string]interface{} so they can be encoded as JSON.
+func normalizeYAML(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, v := range t {
+			m[fmt.Sprint(k)] = normalizeYAML(v)
+		}
+		return m
+	case []interface{}:
+		for i, v := range t {
+			t[i] = normalizeYAML(v)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+// Result is the outcome of testing a single template.
+type Result struct {
+	// Source is the template source, or "(dynamic)" for inline contents.
+	Source string
+
+	// Golden is the path of the golden file, which is the template destination.
+	Golden string
+
+	// Missing is the sorted list of dependencies that had no fixture.
+	Missing []string
+
+	// Diff is a unified diff from the golden file to the rendered output. It is
+	// empty when they match.
+	Diff string
+
+	// Updated is true when the golden file was rewritten with the output.
+	Updated bool
+
+	// Err is any error that occurred while rendering the template or reading
+	// the golden file.
+	Err error
+}
+
+// Passed returns true if the template rendered without errors, every
+// dependency had a fixture and the output matched the golden file.
+func (r *Result) Passed() bool {
+	return r.Err == nil && len(r.Missing) == 0 && r.Diff == ""
+}
+
+// Run renders every template in the configuration, including the templates
+// of template groups, using the given fixtures and compares the output to the
+// template's destination, which is treated as the golden file. When update is
+// true, golden files are rewritten with the rendered output instead, unless
+// fixtures are missing. Nothing else is written and no commands are run.
+func Run(c *config.Config, f Fixtures, update bool) ([]*Result, error) {
+	var templates config.TemplateConfigs
+	if c.Templates != nil {
+		templates = append(templates, *c.Templates...)
+	}
+	if c.TemplateGroups != nil {
+		for _, g := range *c.TemplateGroups {
+			if g.Templates != nil {
+				templates = append(templates, *g.Templates...)
+			}
+		}
+	}
+
+	results := make([]*Result, 0, len(templates))
+	for _, ctmpl := range templates {
+		tmpl, err := newTemplate(c, ctmpl)
+		if err != nil {
+			return nil, err
+		}
+
+		r := &Result{
+			Source: tmpl.Source(),
+			Golden: config.StringVal(ctmpl.Destination),
+		}
+		results = append(results, r)
+
+		out, missing, err := render(c, tmpl, f)
+		r.Missing = missing
+		if err != nil {
+			r.Err = err
+			continue
+		}
+
+		// Never update a golden file with output that is missing data.
+		if update {
+			if len(r.Missing) == 0 {
+				r.Err = writeGolden(r.Golden, out)
+				r.Updated = r.Err == nil
+			}
+			continue
+		}
+
+		golden, err := os.ReadFile(r.Golden)
+		if err != nil {
+			r.Err = errors.Wrap(err, "reading golden file")
+			continue
+		}
+		r.Diff = renderer.Diff(r.Golden, "rendered", golden, out)
+	}
+
+	return results, nil
+}
+
+// newTemplate creates the template for the given configuration the same way
+// the runner does.
+func newTemplate(c *config.Config, ctmpl *config.TemplateConfig) (*template.Template, error) {
+	leftDelim := config.StringVal(ctmpl.LeftDelim)
+	if leftDelim == "" && c.DefaultDelims != nil {
+		leftDelim = config.StringVal(c.DefaultDelims.Left)
+	}
+	rightDelim := config.StringVal(ctmpl.RightDelim)
+	if rightDelim == "" && c.DefaultDelims != nil {
+		rightDelim = config.StringVal(c.DefaultDelims.Right)
+	}
+
+	return template.NewTemplate(&template.NewTemplateInput{
+		Source:           config.StringVal(ctmpl.Source),
+		Contents:         config.StringVal(ctmpl.Contents),
+		ErrMissingKey:    config.BoolVal(ctmpl.ErrMissingKey),
+		ErrFatal:         config.BoolVal(ctmpl.ErrFatal),
+		LeftDelim:        leftDelim,
+		RightDelim:       rightDelim,
+		ExtFuncMap:       ctmpl.ExtFuncMap,
+		FunctionDenylist: ctmpl.FunctionDenylist,
+		SandboxPath:      config.StringVal(ctmpl.SandboxPath),
+		Destination:      config.StringVal(ctmpl.Destination),
+		Config:           ctmpl,
+		ReaderFunc:       c.ReaderFunc,
+	})
+}
+
+// render executes the template until no more dependencies can be resolved
+// from the fixtures, since the data for one dependency may introduce others.
+// Local file dependencies without a fixture are read from disk. It returns
+// the output and the dependencies that are still missing.
+func render(c *config.Config, tmpl *template.Template, f Fixtures) ([]byte, []string, error) {
+	brain := template.NewBrain()
+	tried := make(map[string]struct{})
+
+	for {
+		result, err := tmpl.Execute(&template.ExecuteInput{
+			Brain:  brain,
+			Config: c,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		resolved := false
+		missing := make([]string, 0, result.Missing.Len())
+		for _, d := range result.Missing.List() {
+			if _, ok := tried[d.String()]; ok {
+				missing = append(missing, fixtureName(d))
+				continue
+			}
+			tried[d.String()] = struct{}{}
+
+			data, ok, err := lookup(d, f)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !ok {
+				missing = append(missing, fixtureName(d))
+				continue
+			}
+			brain.Remember(d, data)
+			resolved = true
+		}
+
+		if !resolved {
+			sort.Strings(missing)
+			return result.Output, slices.Compact(missing), nil
+		}
+	}
+}
+
+// fixtureName returns the name of the fixture for the dependency, which is
+// its string form. Transit queries are named after the operation and key
+// instead, since their string form identifies the input with a key that
+// changes on every run, so one fixture applies to all inputs.
+func fixtureName(d dep.Dependency) string {
+	if t, ok := d.(*dep.VaultTransitQuery); ok {
+		return fmt.Sprintf("vault.transit.%s(%s)", t.Op(), t.KeyPath())
+	}
+	return d.String()
+}
+
+// lookup returns the data for the dependency and whether there was any. The
+// data may be nil, for example for a key that does not exist.
+func lookup(d dep.Dependency, f Fixtures) (interface{}, bool, error) {
+	raw, ok := f[fixtureName(d)]
+	if !ok {
+		if fd, ok := d.(*dep.FileQuery); ok {
+			defer fd.Stop()
+			data, _, err := fd.Fetch(nil, nil)
+			if err != nil {
+				return nil, false, err
+			}
+			return data, true, nil
+		}
+		return nil, false, nil
+	}
+
+	v, err := decode(d, raw)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "fixture %s", d)
+	}
+	return v, true, nil
+}
+
+// decode converts the raw fixture into the type the dependency's Fetch
+// returns, which is the type the template functions expect.
+func decode(d dep.Dependency, raw interface{}) (interface{}, error) {
+	var zero interface{}
+	switch d := d.(type) {
+	case *dep.AgentMembersQuery:
+		zero = []*dep.AgentMember(nil)
+	case *dep.CatalogDatacentersQuery, *dep.KVKeysQuery, *dep.VaultListQuery:
+		zero = []string(nil)
+	case *dep.CatalogNodeQuery:
+		zero = (*dep.CatalogNode)(nil)
+	case *dep.CatalogNodesQuery:
+		zero = []*dep.Node(nil)
+	case *dep.CatalogServiceQuery:
+		zero = []*dep.CatalogService(nil)
+	case *dep.CatalogServicesQuery:
+		zero = []*dep.CatalogSnippet(nil)
+	case *dep.ConfigEntryQuery:
+		// A null fixture is a config entry that does not exist.
+		if raw == nil {
+			return nil, nil
+		}
+		return decodeConfigEntry(d.Kind(), raw)
+	case *dep.ConfigEntriesQuery:
+		var list []interface{}
+		if err := roundTrip(raw, &list); err != nil {
+			return nil, err
+		}
+		entries := make([]api.ConfigEntry, 0, len(list))
+		for _, raw := range list {
+			entry, err := decodeConfigEntry(d.Kind(), raw)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+		}
+		return entries, nil
+	case *dep.ConnectCAQuery:
+		zero = []*api.CARoot(nil)
+	case *dep.ConnectLeafQuery:
+		zero = (*api.LeafCert)(nil)
+	case *dep.DatacenterCoordinatesQuery:
+		zero = []*dep.DatacenterCoordinates(nil)
+	case *dep.EventListQuery:
+		zero = []*dep.UserEvent(nil)
+	case *dep.FileQuery:
+		zero = ""
+	case *dep.HealthNodeQuery, *dep.HealthStateQuery:
+		zero = api.HealthChecks(nil)
+	case *dep.HealthServiceQuery, *dep.HealthServiceAllDCsQuery:
+		zero = []*dep.HealthService(nil)
+	case *dep.KVGetQuery:
+		// A null fixture is a key that does not exist.
+		if raw == nil {
+			return nil, nil
+		}
+		zero = ""
+		if d.IsKeyPair() {
+			zero = (*dep.KeyPair)(nil)
+		}
+	case *dep.KVListQuery:
+		zero = []*dep.KeyPair(nil)
+	case *dep.ListExportedServicesQuery:
+		zero = []dep.ExportedService(nil)
+	case *dep.ListImportedServicesQuery:
+		zero = []dep.ImportedService(nil)
+	case *dep.ListIntentionsQuery:
+		zero = []*dep.Intention(nil)
+	case *dep.ListPartitionsQuery:
+		zero = []*dep.Partition(nil)
+	case *dep.ListPeeringQuery:
+		zero = []*dep.Peering(nil)
+	case *dep.LockHolderQuery:
+		zero = (*dep.LockHolder)(nil)
+	case *dep.NodeCoordinatesQuery:
+		zero = []*dep.NodeCoordinate(nil)
+	case *dep.NomadServiceQuery:
+		zero = []*dep.NomadService(nil)
+	case *dep.NomadServicesQuery:
+		zero = []*dep.NomadServicesSnippet(nil)
+	case *dep.NVGetQuery:
+		if raw == nil {
+			return nil, nil
+		}
+		var items map[string]string
+		if err := roundTrip(raw, &items); err != nil {
+			return nil, err
+		}
+		return &dep.NewNomadVariable(&nomadapi.Variable{Items: items}).Items, nil
+	case *dep.NVListQuery:
+		zero = []*dep.NomadVarMeta(nil)
+	case *dep.PreparedQueryExecuteQuery:
+		zero = []*dep.HealthService(nil)
+	case *dep.VaultPKIQuery, *dep.VaultPKISignQuery:
+		zero = dep.PemEncoded{}
+	case *dep.VaultReadQuery, *dep.VaultWriteQuery:
+		zero = (*dep.Secret)(nil)
+	case *dep.VaultTransitQuery:
+		switch d.Op() {
+		case dep.TransitEncrypt:
+			zero = dep.TransitEncrypted{}
+		case dep.TransitDecrypt:
+			zero = dep.TransitDecrypted{}
+		case dep.TransitSign:
+			zero = dep.TransitSigned{}
+		default:
+			zero = dep.TransitHMACed{}
+		}
+	default:
+		return nil, fmt.Errorf("fixtures are not supported for %s", d)
+	}
+
+	target := reflect.New(reflect.TypeOf(zero))
+	if err := roundTrip(raw, target.Interface()); err != nil {
+		return nil, err
+	}
+	return target.Elem().Interface(), nil
+}
+
+// decodeConfigEntry converts a config entry fixture into the config entry type
+// of its kind, which defaults to the kind of the query.
+func decodeConfigEntry(kind string, raw interface{}) (api.ConfigEntry, error) {
+	var m map[string]interface{}
+	if err := roundTrip(raw, &m); err != nil {
+		return nil, err
+	}
+	if m["Kind"] == nil && m["kind"] == nil {
+		m["Kind"] = kind
+	}
+	return api.DecodeConfigEntry(m)
+}
+
+// roundTrip converts the decoded fixture into the given target by encoding
+// it as JSON. Struct fields are matched case-insensitively, so fixtures use
+// the same field names as templates, for example "Key" and "Value".
+func roundTrip(raw, target interface{}) error {
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, target)
+}
+
+// writeGolden writes the rendered output to the golden file, creating parent
+// directories as needed.
+func writeGolden(path string, contents []byte) error {
+	if path == "" {
+		return errors.New("template has no destination to use as golden file")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return errors.Wrap(err, "writing golden file")
+	}
+	if err := os.WriteFile(path, contents, renderer.DefaultFilePerms); err != nil {
+		return errors.Wrap(err, "writing golden file")
+	}
+	return nil
+}
@@ -0,0 +1,552 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package fixture
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/consul-template/config"
+)
+
+func testFile(t *testing.T, dir, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func testConfig(dir string, templates ...string) *config.Config {
+	c := config.DefaultConfig()
+	ts := make(config.TemplateConfigs, 0, len(templates))
+	for i, contents := range templates {
+		ts = append(ts, &config.TemplateConfig{
+			Contents:    config.String(contents),
+			Destination: config.String(filepath.Join(dir, fmt.Sprintf("out%d", i))),
+		})
+	}
+	c.Templates = &ts
+	c.Finalize()
+	return c
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	exp := Fixtures{
+		"kv.block(foo)": "bar",
+		"health.service(web|passing)": []interface{}{
+			map[string]interface{}{
+				"Address": "10.0.0.1",
+				"Port":    float64(80),
+			},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		file     string
+		contents string
+	}{
+		{
+			"json",
+			"fixtures.json",
+			`{"kv.block(foo)": "bar", "health.service(web|passing)": [{"Address": "10.0.0.1", "Port": 80}]}`,
+		},
+		{
+			"yaml",
+			"fixtures.yaml",
+			"kv.block(foo): bar\nhealth.service(web|passing):\n  - Address: 10.0.0.1\n    Port: 80\n",
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			act, err := Load(testFile(t, dir, tc.file, tc.contents))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// YAML integers are decoded as int, JSON numbers as float64.
+			if err := roundTrip(act, &act); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(exp, act) {
+				t.Errorf("\nexp: %#v\nact: %#v", exp, act)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	fixtures := Fixtures{
+		"kv.block(foo)":   "bar",
+		"kv.get(missing)": nil,
+		"kv.pair(app/config)": map[string]interface{}{
+			"Key": "app/config", "Value": "on", "Flags": 42,
+		},
+		"kv.pair(missing)": nil,
+		"catalog.services": []interface{}{
+			map[string]interface{}{"Name": "web", "Tags": []interface{}{"prod"}},
+		},
+		"health.service(web|passing)": []interface{}{
+			map[string]interface{}{"Address": "10.0.0.1", "Port": 80},
+			map[string]interface{}{"Address": "10.0.0.2", "Port": 80},
+		},
+		"vault.read(secret/foo)": map[string]interface{}{
+			"Data": map[string]interface{}{"password": "hunter2"},
+		},
+		"kv.list(app)": []interface{}{
+			map[string]interface{}{"Key": "a", "Value": "1"},
+		},
+		"prepared_query(web)": []interface{}{
+			map[string]interface{}{"Address": "10.0.1.1", "Port": 80},
+		},
+		"config_entry(service-defaults/web)": map[string]interface{}{
+			"Name": "web", "Protocol": "http",
+		},
+		"config_entry(service-defaults/missing)": nil,
+		"config_entries(service-resolver)": []interface{}{
+			map[string]interface{}{"Name": "api", "DefaultSubset": "v1"},
+			map[string]interface{}{"Name": "web", "DefaultSubset": "v2"},
+		},
+		"list.intentions?destination=web": []interface{}{
+			map[string]interface{}{
+				"Source":      map[string]interface{}{"Name": "api"},
+				"Destination": map[string]interface{}{"Name": "web"},
+				"Action":      "allow",
+			},
+		},
+		"health.state(critical)": []interface{}{
+			map[string]interface{}{"Node": "node1", "CheckID": "_node_maintenance", "Status": "critical"},
+		},
+		"health.node(node1|critical,warning)": []interface{}{
+			map[string]interface{}{"CheckID": "serfHealth", "Status": "warning"},
+		},
+		"coordinate.nodes": []interface{}{
+			map[string]interface{}{
+				"Node":  "node1",
+				"Coord": map[string]interface{}{"Vec": []interface{}{0.002, 0}},
+			},
+			map[string]interface{}{
+				"Node":  "node2",
+				"Coord": map[string]interface{}{"Vec": []interface{}{0, 0}},
+			},
+		},
+		"coordinate.datacenters": []interface{}{
+			map[string]interface{}{
+				"Datacenter":  "dc2",
+				"AreaID":      "wan",
+				"Coordinates": []interface{}{map[string]interface{}{"Node": "server1"}},
+			},
+		},
+		"lock_holder(service/leader)": map[string]interface{}{
+			"Key": "service/leader", "Value": "10.0.0.1", "Node": "node1", "Status": "passing",
+		},
+		"lock_holder(service/unlocked)": nil,
+		"event.list(deploy)": []interface{}{
+			map[string]interface{}{"Name": "deploy", "Payload": "v1.2.3", "LTime": 7},
+		},
+		"agent.members(lan)": []interface{}{
+			map[string]interface{}{"Name": "node1", "Addr": "10.0.0.1", "Status": "alive"},
+			map[string]interface{}{"Name": "node2", "Addr": "10.0.0.2", "Status": "failed"},
+		},
+		"health.service(web@*|passing)": []interface{}{
+			map[string]interface{}{"Datacenter": "dc1", "Address": "10.0.0.1"},
+			map[string]interface{}{"Datacenter": "dc2", "Address": "10.1.0.1"},
+		},
+		"vault.transit.encrypt(transit/app)": map[string]interface{}{
+			"Ciphertext": "vault:v1:abc", "KeyVersion": 1,
+		},
+		"vault.transit.decrypt(transit/app)": map[string]interface{}{
+			"Plaintext": "hunter2",
+		},
+		"vault.transit.sign(transit/app)": map[string]interface{}{
+			"Signature": "vault:v1:sig",
+		},
+		"vault.transit.hmac(transit/app)": map[string]interface{}{
+			"HMAC": "vault:v1:mac",
+		},
+	}
+
+	cases := []struct {
+		name     string
+		contents string
+		golden   *string
+		missing  []string
+		diff     bool
+		err      bool
+	}{
+		{
+			"key",
+			`{{ key "foo" }}`,
+			config.String("bar"),
+			nil,
+			false,
+			false,
+		},
+		{
+			"key_does_not_exist",
+			`{{ keyExists "missing" }}`,
+			config.String("false"),
+			nil,
+			false,
+			false,
+		},
+		{
+			"key_pair",
+			`{{ with keyPair "app/config" }}{{ .Key }}={{ .Value }} {{ .Flags }}{{ end }}`,
+			config.String("app/config=on 42"),
+			nil,
+			false,
+			false,
+		},
+		{
+			"key_pair_does_not_exist",
+			`{{ with keyPair "missing" }}{{ .Key }}{{ else }}none{{ end }}`,
+			config.String("none"),
+			nil,
+			false,
+			false,
+		},
+		{
+			"nested_dependencies",
+			`{{ range services }}{{ range service .Name }}{{ .Address }}:{{ .Port }} {{ end }}{{ end }}`,
+			config.String("10.0.0.1:80 10.0.0.2:80 "),
+			nil,
+			false,
+			false,
+		},
+		{
+			"vault",
+			`{{ with secret "secret/foo" }}{{ .Data.password }}{{ end }}`,
+			config.String("hunter2"),
+			nil,
+			false,
+			false,
+		},
+		{
+			"kv_list",
+			`{{ range ls "app" }}{{ .Key }}={{ .Value }}{{ end }}`,
+			config.String("a=1"),
+			nil,
+			false,
+			false,
+		},
+		{
+			"prepared_query",
+			`{{ range preparedQuery "web" }}{{ .Address }}:{{ .Port }}{{ end }}`,
+			config.String("10.0.1.1:80"),
+			nil,
+			false,
+			false,
+		},
+		{
+			"config_entry",
+			`{{ with configEntry "service-defaults" "web" }}{{ .Kind }}/{{ .Name }}={{ .Protocol }}{{ end }}`,
+			config.String("service-defaults/web=http"),
+			nil,
+			false,
+			false,
+		},
+		{
+			"config_entry_does_not_exist",
+			`{{ with configEntry "service-defaults" "missing" }}{{ .Name }}{{ else }}none{{ end }}`,
+			config.String("none"),
+			nil,
+			false,
+			false,
+		},
+		{
+			"config_entries",
+			`{{ range configEntries "service-resolver" }}{{ .Name }}={{ .DefaultSubset }} {{ end }}`,
+			config.String("api=v1 web=v2 "),
+			nil,
+			false,
+			false,
+		},
+		{
+			"intentions",
+			`{{ range intentions "?destination=web" }}{{ .Source.Name }}->{{ .Destination.Name }}={{ .Action }}{{ end }}`,
+			config.String("api->web=allow"),
+			nil,
+			false,
+			false,
+		},
+		{
+			"checks",
+			`{{ range checks "critical" }}{{ .Node }}/{{ .CheckID }}{{ end }}`,
+			config.String("node1/_node_maintenance"),
+			nil,
+			false,
+			false,
+		},
+		{
+			"node_checks",
+			`{{ range nodeChecks "node1" "warning,critical" }}{{ .CheckID }}={{ .Status }}{{ end }}`,
+			config.String("serfHealth=warning"),
+			nil,
+			false,
+			false,
+		},
+		{
+			"node_coordinates",
+			`{{ with nodeCoordinates }}{{ rtt (index . 0) (index . 1) }}{{ end }}`,
+			config.String("2ms"),
+			nil,
+			false,
+			false,
+		},
+		{
+			"datacenter_coordinates",
+			`{{ range datacenterCoordinates }}{{ .Datacenter }}: {{ range .Coordinates }}{{ .Node }}{{ end }}{{ end }}`,
+			config.String("dc2: server1"),
+			nil,
+			false,
+			false,
+		},
+		{
+			"lock_holder",
+			`{{ with lockHolder "service/leader" }}{{ .Value }}@{{ .Node }} {{ .Status }}{{ end }}`,
+			config.String("10.0.0.1@node1 passing"),
+			nil,
+			false,
+			false,
+		},
+		{
+			"lock_holder_not_locked",
+			`{{ with lockHolder "service/unlocked" }}{{ .Node }}{{ else }}none{{ end }}`,
+			config.String("none"),
+			nil,
+			false,
+			false,
+		},
+		{
+			"events",
+			`{{ range events "deploy" }}{{ .Payload }}@{{ .LTime }}{{ end }}`,
+			config.String("v1.2.3@7"),
+			nil,
+			false,
+			false,
+		},
+		{
+			"members",
+			`{{ range members }}{{ .Name }}={{ .Status }} {{ end }}`,
+			config.String("node1=alive node2=failed "),
+			nil,
+			false,
+			false,
+		},
+		{
+			"service_all_dcs",
+			`{{ range serviceAllDCs "web" }}{{ .Datacenter }}/{{ .Address }} {{ end }}`,
+			config.String("dc1/10.0.0.1 dc2/10.1.0.1 "),
+			nil,
+			false,
+			false,
+		},
+		{
+			"transit",
+			`{{ transitEncrypt "transit/app" "a" }} {{ transitEncrypt "transit/app" "b" }} {{ transitDecrypt "transit/app" "vault:v1:abc" }} {{ transitSign "transit/app" "a" }} {{ transitHMAC "transit/app" "a" }}`,
+			config.String("vault:v1:abc vault:v1:abc hunter2 vault:v1:sig vault:v1:mac"),
+			nil,
+			false,
+			false,
+		},
+		{
+			"transit_missing_fixture",
+			`{{ transitEncrypt "transit/other" "a" }}{{ transitEncrypt "transit/other" "b" }}`,
+			config.String(""),
+			[]string{"vault.transit.encrypt(transit/other)"},
+			false,
+			false,
+		},
+		{
+			"missing_fixture",
+			`{{ key "foo" }}{{ key "nope" }}{{ range service "db" }}{{ end }}`,
+			config.String("bar"),
+			[]string{"health.service(db|passing)", "kv.block(nope)"},
+			false,
+			false,
+		},
+		{
+			"diff",
+			`{{ key "foo" }}`,
+			config.String("baz"),
+			nil,
+			true,
+			false,
+		},
+		{
+			"no_golden_file",
+			`{{ key "foo" }}`,
+			nil,
+			nil,
+			false,
+			true,
+		},
+		{
+			"execute_error",
+			`{{ fail }}`,
+			config.String(""),
+			nil,
+			false,
+			true,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			dir := t.TempDir()
+			c := testConfig(dir, tc.contents)
+			golden := config.StringVal((*c.Templates)[0].Destination)
+			if tc.golden != nil {
+				testFile(t, dir, filepath.Base(golden), *tc.golden)
+			}
+
+			results, err := Run(c, fixtures, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("expected 1 result, got %d", len(results))
+			}
+			r := results[0]
+
+			if (r.Err != nil) != tc.err {
+				t.Errorf("expected error to be %t, got %v", tc.err, r.Err)
+			}
+			if !reflect.DeepEqual(tc.missing, r.Missing) && len(tc.missing)+len(r.Missing) > 0 {
+				t.Errorf("\nexp: %#v\nact: %#v", tc.missing, r.Missing)
+			}
+			if (r.Diff != "") != tc.diff {
+				t.Errorf("expected diff to be %t, got %q", tc.diff, r.Diff)
+			}
+			if r.Golden != golden {
+				t.Errorf("expected %q to be %q", r.Golden, golden)
+			}
+			pass := !tc.err && !tc.diff && len(tc.missing) == 0
+			if r.Passed() != pass {
+				t.Errorf("expected passed to be %t", pass)
+			}
+		})
+	}
+}
+
+func TestRun_diff(t *testing.T) {
+	dir := t.TempDir()
+	c := testConfig(dir, "a\n{{ key \"foo\" }}\nc\n")
+	testFile(t, dir, "out0", "a\nb\nc\n")
+
+	results, err := Run(c, Fixtures{"kv.block(foo)": "B"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join(dir, "out0")
+	exp := "--- " + golden + "\n" +
+		"+++ rendered\n" +
+		"@@ -1,3 +1,3 @@\n" +
+		" a\n" +
+		"-b\n" +
+		"+B\n" +
+		" c\n"
+	if results[0].Diff != exp {
+		t.Errorf("\nexp: %s\nact: %s", exp, results[0].Diff)
+	}
+}
+
+func TestRun_update(t *testing.T) {
+	dir := t.TempDir()
+	c := testConfig(filepath.Join(dir, "golden"), `{{ key "foo" }}`)
+
+	results, err := Run(c, Fixtures{"kv.block(foo)": "bar"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := results[0]; r.Err != nil || !r.Updated {
+		t.Fatalf("expected golden file to be updated: %#v", r)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "golden", "out0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "bar" {
+		t.Errorf("expected %q to be %q", b, "bar")
+	}
+
+	results, err = Run(c, Fixtures{"kv.block(foo)": "bar"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[0].Passed() {
+		t.Errorf("expected updated golden file to pass: %#v", results[0])
+	}
+}
+
+func TestRun_file(t *testing.T) {
+	dir := t.TempDir()
+	path := testFile(t, dir, "input", "from disk")
+	c := testConfig(dir,
+		`{{ file "`+path+`" }}`,
+		`{{ file "/does/not/exist" }}`,
+	)
+	testFile(t, dir, "out0", "from disk")
+	testFile(t, dir, "out1", "from fixture")
+
+	results, err := Run(c, Fixtures{"file(/does/not/exist)": "from fixture"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if !r.Passed() {
+			t.Errorf("expected %s to pass: %#v", r.Golden, r)
+		}
+	}
+}
+
+func TestRun_invalidFixture(t *testing.T) {
+	dir := t.TempDir()
+	c := testConfig(dir, `{{ key "foo" }}`)
+	testFile(t, dir, "out0", "")
+
+	results, err := Run(c, Fixtures{"kv.block(foo)": map[string]interface{}{"a": "b"}}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := results[0].Err; err == nil || !strings.Contains(err.Error(), "fixture kv.block(foo)") {
+		t.Errorf("expected decode error, got %v", err)
+	}
+}
+
+func TestRun_templateGroup(t *testing.T) {
+	dir := t.TempDir()
+	c := config.DefaultConfig()
+	c.TemplateGroups = &config.TemplateGroupConfigs{
+		&config.TemplateGroupConfig{
+			Templates: &config.TemplateConfigs{
+				&config.TemplateConfig{
+					Contents:    config.String(`{{ key "foo" }}`),
+					Destination: config.String(filepath.Join(dir, "out0")),
+				},
+			},
+		},
+	}
+	c.Finalize()
+	testFile(t, dir, "out0", "bar")
+
+	results, err := Run(c, Fixtures{"kv.block(foo)": "bar"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || !results[0].Passed() {
+		t.Errorf("expected group template to pass: %#v", results)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	// FormatText is the default "[LEVEL] (subsystem) message" log format.
+	FormatText = "text"
+
+	// FormatJSON writes every log line as a JSON object.
+	FormatJSON = "json"
+)
+
+// fieldsMarker separates a log message from the structured fields appended
+// by Fields.
+const fieldsMarker = '\x1f'
+
+// jsonEnabled records whether the JSON format is in use. Fields are only
+// appended to log lines when it is, so text output and applications that
+// embed Consul Template and own the log output never see them.
+var jsonEnabled atomic.Bool
+
+// Fields returns the given key/value pairs encoded so they can be appended to
+// a log line, for example:
+//
+//	log.Printf("[INFO] (runner) rendered %s%s", dest,
+//		logging.Fields("template_id", id, "destination", dest))
+//
+// When the JSON format is enabled the pairs become fields of the log object.
+// Otherwise Fields returns an empty string and the log line is unchanged.
+func Fields(kv ...string) string {
+	if !jsonEnabled.Load() || len(kv) < 2 {
+		return ""
+	}
+	b, err := json.Marshal(kv[:len(kv)&^1])
+	if err != nil {
+		return ""
+	}
+	return string(fieldsMarker) + string(b)
+}
+
+// jsonWriter converts each "[LEVEL] (subsystem) message" line written to it
+// into a JSON object before passing it on.
+type jsonWriter struct {
+	out io.Writer
+}
+
+// Write is used to implement io.Writer.
+func (w jsonWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if _, err := w.out.Write(formatJSON(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// formatJSON converts a single log line into a newline-terminated JSON object
+// with timestamp, level, subsystem and message keys, followed by any fields
+// appended by Fields. Lines without a level are logged at the info level.
+func formatJSON(p []byte) []byte {
+	line := string(bytes.TrimRight(p, "\r\n"))
+
+	var fields []string
+	if i := strings.IndexByte(line, fieldsMarker); i >= 0 {
+		if err := json.Unmarshal([]byte(line[i+1:]), &fields); err != nil {
+			fields = nil
+		}
+		line = line[:i]
+	}
+
+	level := "info"
+	if strings.HasPrefix(line, "[") {
+		if i := strings.IndexByte(line, ']'); i > 0 {
+			level = strings.ToLower(line[1:i])
+			line = strings.TrimLeft(line[i+1:], " ")
+		}
+	}
+
+	var subsystem string
+	if strings.HasPrefix(line, "(") {
+		if i := strings.IndexByte(line, ')'); i > 0 {
+			subsystem = line[1:i]
+			line = strings.TrimLeft(line[i+1:], " ")
+		}
+	}
+	// Some call sites log "(subsystem): message".
+	line = strings.TrimLeft(strings.TrimPrefix(line, ":"), " ")
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	writeJSONField(&buf, "timestamp", now(), true)
+	writeJSONField(&buf, "level", level, false)
+	if subsystem != "" {
+		writeJSONField(&buf, "subsystem", subsystem, false)
+	}
+	writeJSONField(&buf, "message", strings.TrimRight(line, " "), false)
+	for i := 0; i+1 < len(fields); i += 2 {
+		switch fields[i] {
+		case "timestamp", "level", "subsystem", "message":
+			continue
+		}
+		writeJSONField(&buf, fields[i], fields[i+1], false)
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+// writeJSONField appends a "key":"value" pair to buf. HTML characters are
+// not escaped so that messages such as "a" => "b" stay readable.
+func writeJSONField(buf *bytes.Buffer, key, value string, first bool) {
+	if !first {
+		buf.WriteByte(',')
+	}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(key)
+	buf.Truncate(buf.Len() - 1) // Encode appends a newline
+	buf.WriteByte(':')
+	enc.Encode(value)
+	buf.Truncate(buf.Len() - 1)
+}
@@ -0,0 +1,213 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gsyslog "github.com/hashicorp/go-syslog"
+	"github.com/hashicorp/logutils"
+)
+
+func TestFormatJSON(t *testing.T) {
+	defer func(orig func() string) { now = orig }(now)
+	now = func() string { return "*NOW*" }
+
+	defer jsonEnabled.Store(jsonEnabled.Load())
+	jsonEnabled.Store(true)
+
+	cases := []struct {
+		name   string
+		input  string
+		output string
+	}{
+		{
+			"full",
+			"[INFO] (runner) rendered \"in\" => \"out\"\n",
+			`{"timestamp":"*NOW*","level":"info","subsystem":"runner","message":"rendered \"in\" => \"out\""}` + "\n",
+		},
+		{
+			"no_subsystem",
+			"[WARN] something happened",
+			`{"timestamp":"*NOW*","level":"warn","message":"something happened"}` + "\n",
+		},
+		{
+			"no_level",
+			"plain message",
+			`{"timestamp":"*NOW*","level":"info","message":"plain message"}` + "\n",
+		},
+		{
+			"subsystem_colon",
+			"[ERR] (runner): boom",
+			`{"timestamp":"*NOW*","level":"err","subsystem":"runner","message":"boom"}` + "\n",
+		},
+		{
+			"fields",
+			"[DEBUG] (view) kv.block(foo) received data" +
+				Fields("dependency", "kv.block(foo)", "template_id", "abc") + "\n",
+			`{"timestamp":"*NOW*","level":"debug","subsystem":"view","message":"kv.block(foo) received data","dependency":"kv.block(foo)","template_id":"abc"}` + "\n",
+		},
+		{
+			"fields_reserved_keys",
+			"[INFO] (runner) hello" + Fields("message", "nope", "destination", "/tmp/out"),
+			`{"timestamp":"*NOW*","level":"info","subsystem":"runner","message":"hello","destination":"/tmp/out"}` + "\n",
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			act := string(formatJSON([]byte(tc.input)))
+			if act != tc.output {
+				t.Errorf("\nexp: %s\nact: %s", tc.output, act)
+			}
+		})
+	}
+}
+
+func TestFields(t *testing.T) {
+	defer jsonEnabled.Store(jsonEnabled.Load())
+
+	jsonEnabled.Store(false)
+	if f := Fields("template_id", "abc"); f != "" {
+		t.Errorf("expected no fields in text mode, got %q", f)
+	}
+
+	jsonEnabled.Store(true)
+	if f := Fields("template_id"); f != "" {
+		t.Errorf("expected no fields without a value, got %q", f)
+	}
+	exp := "\x1f" + `["template_id","abc"]`
+	if f := Fields("template_id", "abc", "dangling"); f != exp {
+		t.Errorf("\nexp: %q\nact: %q", exp, f)
+	}
+}
+
+func TestNewWriter_json(t *testing.T) {
+	defer func(orig func() string) { now = orig }(now)
+	now = func() string { return "*NOW*" }
+	defer jsonEnabled.Store(jsonEnabled.Load())
+
+	var buf bytes.Buffer
+	writer, err := newWriter(&Config{
+		Level:  "INFO",
+		Format: "JSON",
+		Writer: &buf,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := "[INFO] (runner) rendered" + Fields("template_id", "abc")
+	n, err := writer.Write([]byte(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(input) {
+		t.Errorf("byte count (%d) doesn't match input len (%d)", n, len(input))
+	}
+	writer.Write([]byte("[DEBUG] (runner) should not write"))
+
+	exp := `{"timestamp":"*NOW*","level":"info","subsystem":"runner","message":"rendered","template_id":"abc"}` + "\n"
+	if buf.String() != exp {
+		t.Errorf("\nexp: %s\nact: %s", exp, buf.String())
+	}
+}
+
+func TestNewWriter_jsonLogFile(t *testing.T) {
+	defer func(orig func() string) { now = orig }(now)
+	now = func() string { return "*NOW*" }
+	defer jsonEnabled.Store(jsonEnabled.Load())
+
+	dir := t.TempDir()
+	var buf bytes.Buffer
+	writer, err := newWriter(&Config{
+		Level:       "INFO",
+		Format:      FormatJSON,
+		LogFilePath: dir + string(filepath.Separator),
+		Writer:      &buf,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writer.Write([]byte("[INFO] (runner) one"))
+	writer.Write([]byte("[DEBUG] (runner) filtered"))
+	writer.Write([]byte("[ERR] (runner) two"))
+
+	files := listDir(t, dir)
+	if len(files) != 1 {
+		t.Fatalf("expected 1 log file, got %d", len(files))
+	}
+	b, err := os.ReadFile(filepath.Join(dir, files[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := `{"timestamp":"*NOW*","level":"info","subsystem":"runner","message":"one"}` + "\n" +
+		`{"timestamp":"*NOW*","level":"err","subsystem":"runner","message":"two"}` + "\n"
+	if string(b) != exp {
+		t.Errorf("\nexp: %s\nact: %s", exp, b)
+	}
+	if buf.String() != exp {
+		t.Errorf("\nexp: %s\nact: %s", exp, buf.String())
+	}
+}
+
+func TestNewWriter_invalidFormat(t *testing.T) {
+	_, err := newWriter(&Config{
+		Level:  "INFO",
+		Format: "xml",
+	})
+	if err == nil || !strings.Contains(err.Error(), `invalid log format "xml"`) {
+		t.Errorf("expected invalid log format error, got %v", err)
+	}
+}
+
+type testSyslogger struct {
+	priority gsyslog.Priority
+	buf      bytes.Buffer
+}
+
+func (s *testSyslogger) WriteLevel(p gsyslog.Priority, b []byte) error {
+	s.priority = p
+	s.buf.Write(b)
+	return nil
+}
+
+func (s *testSyslogger) Write(b []byte) (int, error) {
+	return s.buf.Write(b)
+}
+
+func (s *testSyslogger) Close() error {
+	return nil
+}
+
+func TestSyslogWrapper_json(t *testing.T) {
+	defer func(orig func() string) { now = orig }(now)
+	now = func() string { return "*NOW*" }
+
+	filt, err := newLogFilter(nil, logutils.LogLevel("INFO"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := &testSyslogger{}
+	s := &SyslogWrapper{l: l, filt: filt, json: true}
+	if _, err := s.Write([]byte("[WARN] (runner) careful")); err != nil {
+		t.Fatal(err)
+	}
+
+	if l.priority != gsyslog.LOG_WARNING {
+		t.Errorf("expected %v to be %v", l.priority, gsyslog.LOG_WARNING)
+	}
+	exp := `{"timestamp":"*NOW*","level":"warn","subsystem":"runner","message":"careful"}` + "\n"
+	if l.buf.String() != exp {
+		t.Errorf("\nexp: %s\nact: %s", exp, l.buf.String())
+	}
+}
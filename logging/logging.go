@@ -48,6 +48,10 @@ type Config struct {
 	// Level is the log level to use.
 	Level string `json:"level"`
 
+	// Format is the log output format, either FormatText or FormatJSON. An
+	// empty value means FormatText.
+	Format string `json:"format"`
+
 	// LogFilePath is the path to the file the logs get written to
 	LogFilePath string `json:"log_file"`
 
@@ -86,13 +90,27 @@ func Setup(config *Config) error {
 
 // Creates a log writer w/ filtering
 func newWriter(config *Config) (io.Writer, error) {
+	var isJSON bool
+	switch strings.ToLower(config.Format) {
+	case "", FormatText:
+	case FormatJSON:
+		isJSON = true
+	default:
+		return nil, fmt.Errorf("invalid log format %q, valid log formats are %s, %s",
+			config.Format, FormatText, FormatJSON)
+	}
+
 	var logOutput io.Writer = logWriter{out: config.Writer}
+	if isJSON {
+		logOutput = jsonWriter{out: config.Writer}
+	}
 	logLevel := logutils.LogLevel(strings.ToUpper(config.Level))
 
 	logOutput, err := newLogFilter(logOutput, logLevel)
 	if err != nil {
 		return nil, err
 	}
+	jsonEnabled.Store(isJSON)
 
 	if config.LogFilePath != "" {
 		dir, fileName := filepath.Split(config.LogFilePath)
@@ -119,7 +137,15 @@ func newWriter(config *Config) (io.Writer, error) {
 		if err := logFile.openNew(); err != nil {
 			return nil, fmt.Errorf("error setting up log_file logging : %w", err)
 		}
-		logOutput = io.MultiWriter(logOutput, logFile)
+		var fileOutput io.Writer = logFile
+		if isJSON {
+			// The level has to be checked before the line is converted to
+			// JSON, so filter in front of the conversion instead of in the
+			// log file itself.
+			logFile.filt = nil
+			fileOutput, _ = newLogFilter(jsonWriter{out: logFile}, logLevel)
+		}
+		logOutput = io.MultiWriter(logOutput, fileOutput)
 	}
 
 	if config.Syslog {
@@ -129,7 +155,7 @@ func newWriter(config *Config) (io.Writer, error) {
 		if err != nil {
 			return nil, fmt.Errorf("error setting up syslog logger: %s", err)
 		}
-		syslog := &SyslogWrapper{l, logOutput.(*logutils.LevelFilter)}
+		syslog := &SyslogWrapper{l, logOutput.(*logutils.LevelFilter), isJSON}
 		logOutput = io.MultiWriter(logOutput, syslog)
 	}
 
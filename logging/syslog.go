@@ -23,6 +23,9 @@ var syslogPriorityMap = map[string]gsyslog.Priority{
 type SyslogWrapper struct {
 	l    gsyslog.Syslogger
 	filt *logutils.LevelFilter
+
+	// json writes each message as a JSON object instead of plain text.
+	json bool
 }
 
 // Write is used to implement io.Writer.
@@ -50,6 +53,10 @@ func (s *SyslogWrapper) Write(p []byte) (int, error) {
 		priority = gsyslog.LOG_NOTICE
 	}
 
+	if s.json {
+		afterLevel = formatJSON(p)
+	}
+
 	// Attempt the write
 	err := s.l.WriteLevel(priority, afterLevel)
 	return len(p), err
@@ -35,7 +35,7 @@ func TestSyslogFilter(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	s := &SyslogWrapper{l, filt}
+	s := &SyslogWrapper{l: l, filt: filt}
 	infotest := []byte("[INFO] test")
 	n, err := s.Write(infotest)
 	if err != nil {
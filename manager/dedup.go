@@ -13,6 +13,7 @@ import (
 	"sync"
 	"time"
 
+	metrics "github.com/hashicorp/go-metrics"
 	"github.com/mitchellh/hashstructure"
 
 	"github.com/hashicorp/consul-template/config"
@@ -291,6 +292,13 @@ func (d *DedupManager) setLeader(tmpl *template.Template, lockCh <-chan struct{}
 	}
 	d.leaderLock.Unlock()
 
+	var isLeader float32
+	if lockCh != nil {
+		isLeader = 1
+	}
+	metrics.SetGaugeWithLabels([]string{"dedup", "leader"}, isLeader,
+		[]metrics.Label{{Name: "template_id", Value: tmpl.ID()}})
+
 	// Clear the lastWrite hash if we've lost leadership
 	if lockCh == nil {
 		d.lastWriteLock.Lock()
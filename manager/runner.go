@@ -4,6 +4,8 @@
 package manager
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,25 +13,44 @@ import (
 	"os"
 	"reflect"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 
+	"github.com/hashicorp/consul-template/cache"
 	"github.com/hashicorp/consul-template/child"
 	"github.com/hashicorp/consul-template/config"
 	dep "github.com/hashicorp/consul-template/dependency"
+	"github.com/hashicorp/consul-template/logging"
 	"github.com/hashicorp/consul-template/renderer"
 	"github.com/hashicorp/consul-template/template"
 	"github.com/hashicorp/consul-template/watch"
 
+	metrics "github.com/hashicorp/go-metrics"
 	"github.com/hashicorp/go-multierror"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
 	// viewLimit is the number of views that we consider reasonable before we
 	// warn the user that they might be DDoSing their Consul cluster.
 	viewLimit = 128
+
+	// tracerName is the instrumentation scope for spans emitted by the runner.
+	tracerName = "github.com/hashicorp/consul-template/manager"
+
+	// validatePathPlaceholder is replaced with the path of the staged file in
+	// a template's validate command.
+	validatePathPlaceholder = "{{path}}"
+
+	// validatePathEnv is the environment variable holding the path of the
+	// staged file when running a template's validate command.
+	validatePathEnv = "CONSUL_TEMPLATE_VALIDATE_PATH"
 )
 
 // Runner responsible rendering Templates and invoking Commands.
@@ -57,9 +78,15 @@ type Runner struct {
 	outStream, errStream io.Writer
 	inStream             io.Reader
 
-	// templates is the list of calculated templates.
+	// templates is the list of calculated templates, including the templates
+	// of every template group.
 	templates []*template.Template
 
+	// groups is the list of template groups, and templateGroups maps the ID
+	// of each template in a group to the group.
+	groups         []*templateGroup
+	templateGroups map[string]*templateGroup
+
 	// renderEvents is a mapping of a template ID to the render event.
 	renderEvents map[string]*RenderEvent
 
@@ -89,6 +116,14 @@ type Runner struct {
 	// brain is the internal storage database of returned dependency data.
 	brain *template.Brain
 
+	// cache persists Consul and Nomad dependency data to disk, if enabled.
+	cache *cache.Cache
+
+	// staleDeps is the set of dependencies whose data was loaded from the cache
+	// and has not been replaced by live data yet. staleLock protects it.
+	staleDeps map[string]struct{}
+	staleLock sync.RWMutex
+
 	// child is the child process under management. This may be nil if not running
 	// in exec mode.
 	child *child.Child
@@ -127,6 +162,10 @@ type Runner struct {
 	// Consul Template in another application
 	rendererFn renderer.Renderer
 
+	// groupRendererFn is called whenever the templates of a template group
+	// need to be written, and defaults to renderer.RenderGroup.
+	groupRendererFn renderer.GroupRenderer
+
 	// readerFn is called whenever the template source is read, and will default
 	// to os.ReadFile. This is intended for use when embedding Consul Template
 	// in another application.
@@ -144,6 +183,12 @@ type Runner struct {
 	readyCh chan struct{}
 }
 
+// templateGroup is a set of templates that are rendered all-or-nothing.
+type templateGroup struct {
+	config    *config.TemplateGroupConfig
+	templates []*template.Template
+}
+
 // RenderEvent captures the time and events that occurred for a template
 // rendering.
 type RenderEvent struct {
@@ -163,6 +208,10 @@ type RenderEvent struct {
 	// template.
 	TemplateConfigs []*config.TemplateConfig
 
+	// StaleDeps is the list of used dependencies whose data was loaded from the
+	// on-disk cache and has not been replaced by live data yet.
+	StaleDeps *dep.Set
+
 	// Unwatched is the list of dependencies that are not present in the watcher.
 	// This value may change over time due to the n-pass evaluation.
 	UnwatchedDeps *dep.Set
@@ -223,6 +272,7 @@ func NewRunner(config *config.Config, dry bool) (*Runner, error) {
 		renderEventCh: make(chan struct{}, 1),
 		dependencies:  make(map[string]dep.Dependency),
 		brain:         template.NewBrain(),
+		staleDeps:     make(map[string]struct{}),
 		quiescenceMap: make(map[string]*quiescence),
 		quiescenceCh:  make(chan *template.Template),
 		rendererFn:    config.RendererFunc,
@@ -232,6 +282,9 @@ func NewRunner(config *config.Config, dry bool) (*Runner, error) {
 	if runner.rendererFn == nil {
 		runner.rendererFn = renderer.Render
 	}
+	if runner.groupRendererFn == nil {
+		runner.groupRendererFn = renderer.RenderGroup
+	}
 	if runner.readerFn == nil {
 		runner.readerFn = os.ReadFile
 	}
@@ -316,6 +369,7 @@ func (r *Runner) Start() {
 		} else {
 			log.Printf("[DEBUG] (runner) watching %d dependencies", r.watcher.Size())
 		}
+		metrics.SetGauge([]string{"runner", "dependencies"}, float32(r.watcher.Size()))
 
 		if r.allTemplatesRendered() {
 			log.Printf("[DEBUG] (runner) all templates rendered")
@@ -331,6 +385,9 @@ func (r *Runner) Start() {
 			// intervals.
 		NEXT_Q:
 			for _, t := range r.templates {
+				// Templates in a group share the timer of the group's first
+				// template, so the group renders together.
+				t = r.quiescenceTemplate(t)
 				if _, ok := r.quiescenceMap[t.ID()]; ok {
 					continue NEXT_Q
 				}
@@ -536,6 +593,12 @@ func (r *Runner) RenderEvents() map[string]*RenderEvent {
 	return times
 }
 
+// DependencyStatus returns a snapshot of the state of every dependency the
+// runner is currently watching, sorted by dependency.
+func (r *Runner) DependencyStatus() []*watch.ViewStatus {
+	return r.watcher.Status()
+}
+
 func (r *Runner) internalStop(immediately bool) {
 	r.stopLock.Lock()
 	defer r.stopLock.Unlock()
@@ -546,8 +609,9 @@ func (r *Runner) internalStop(immediately bool) {
 
 	log.Printf("[INFO] (runner) stopping")
 	r.stopDedup()
-	r.stopWatchers()
+	leasers := r.stopWatchers()
 	r.stopChild(immediately)
+	r.revokeVaultLeases(leasers)
 
 	if err := r.deletePid(); err != nil {
 		log.Printf("[WARN] (runner) could not remove pid at %q: %s",
@@ -566,15 +630,29 @@ func (r *Runner) stopDedup() {
 	}
 }
 
-func (r *Runner) stopWatchers() {
+// stopWatchers stops the watchers and returns the stopped dependencies that
+// hold Vault leases.
+func (r *Runner) stopWatchers() []dep.VaultLeaser {
+	var leasers []dep.VaultLeaser
 	if r.watcher != nil {
 		log.Printf("[DEBUG] (runner) stopping watcher")
-		r.watcher.Stop()
+		leasers = r.watcher.Stop()
 	}
 	if r.vaultTokenWatcher != nil {
 		log.Printf("[DEBUG] (runner) stopping vault token watcher")
 		r.vaultTokenWatcher.Stop()
 	}
+	return leasers
+}
+
+// revokeVaultLeases revokes the given leases if revoke_on_shutdown is set. It
+// runs after the child process is stopped, so the child can still use its
+// secrets while it shuts down.
+func (r *Runner) revokeVaultLeases(leasers []dep.VaultLeaser) {
+	if r.watcher == nil || !config.BoolVal(r.config.Vault.RevokeOnShutdown) {
+		return
+	}
+	r.watcher.RevokeLeases(leasers)
 }
 
 func (r *Runner) stopChild(immediately bool) {
@@ -614,8 +692,13 @@ func (r *Runner) Receive(d dep.Dependency, data interface{}) {
 	//
 	// and by "little" bug, I mean really big bug.
 	if _, ok := r.dependencies[d.String()]; ok {
-		log.Printf("[DEBUG] (runner) receiving dependency %s", d)
+		log.Printf("[DEBUG] (runner) receiving dependency %s%s", d,
+			logging.Fields("dependency", d.String()))
 		r.brain.Remember(d, data)
+
+		r.staleLock.Lock()
+		delete(r.staleDeps, d.String())
+		r.staleLock.Unlock()
 	}
 }
 
@@ -640,17 +723,16 @@ func (r *Runner) Signal(s os.Signal) error {
 func (r *Runner) Run() error {
 	log.Printf("[DEBUG] (runner) initiating run")
 
+	ctx, span := otel.Tracer(tracerName).Start(context.Background(), "runner.run")
+	defer span.End()
+
 	var newRenderEvent, wouldRenderAny, renderedAny bool
 	runCtx := &templateRunCtx{
-		depsMap: make(map[string]dep.Dependency),
+		depsMap:     make(map[string]dep.Dependency),
+		templateIDs: make(map[string][]string),
 	}
 
-	for _, tmpl := range r.templates {
-		event, err := r.runTemplate(tmpl, runCtx)
-		if err != nil {
-			return err
-		}
-
+	recordEvent := func(tmpl *template.Template, event *RenderEvent) {
 		// If there was a render event store it
 		if event != nil {
 			r.renderEventsLock.Lock()
@@ -672,34 +754,78 @@ func (r *Runner) Run() error {
 		}
 	}
 
+	for _, tmpl := range r.templates {
+		// Templates in a group are run with their group below.
+		if _, ok := r.templateGroups[tmpl.ID()]; ok {
+			continue
+		}
+
+		tmplCtx, tmplSpan := otel.Tracer(tracerName).Start(ctx, "runner.run_template",
+			trace.WithAttributes(attribute.String("template.id", tmpl.ID())))
+		event, err := r.runTemplate(tmplCtx, tmpl, runCtx)
+		endTemplateSpan(tmplSpan, event, err)
+		if err != nil {
+			return err
+		}
+		recordEvent(tmpl, event)
+	}
+
+	for _, g := range r.groups {
+		groupCtx, groupSpan := otel.Tracer(tracerName).Start(ctx, "runner.run_template_group",
+			trace.WithAttributes(attribute.String("template_group", config.StringVal(g.config.Name))))
+		events, err := r.runTemplateGroup(groupCtx, g, runCtx)
+		endSpan(groupSpan, err)
+		if err != nil {
+			return err
+		}
+		for i, tmpl := range g.templates {
+			recordEvent(tmpl, events[i])
+		}
+	}
+
 	// Always reset quiescenceRun in case this run was triggered by a quiescence timer
 	r.quiescenceRun = nil
 
 	// Perform the diff and update the known dependencies.
 	r.diffAndUpdateDeps(runCtx.depsMap)
+	r.watcher.SetTemplateIDs(runCtx.templateIDs)
+
+	if r.cache != nil {
+		r.saveCache()
+	}
 
 	// Execute each command in sequence, collecting any errors that occur - this
 	// ensures all commands execute at least once.
 	var errs []error
-	for _, t := range runCtx.commands {
-		log.Printf("[INFO] (runner) executing command %q from %s",
-			fmt.Sprintf("%q", t.Exec.Command), t.Display())
-		env := t.Exec.Env.Copy()
+	for _, c := range runCtx.commands {
+		log.Printf("[INFO] (runner) executing command %q from %s%s",
+			fmt.Sprintf("%q", c.exec.Command), c.display, c.logFields)
+		env := c.exec.Env.Copy()
 		env.Custom = append(r.childEnv(), env.Custom...)
-		if _, err := spawnChild(&spawnChildInput{
+		_, cmdSpan := otel.Tracer(tracerName).Start(ctx, "runner.command",
+			trace.WithAttributes(
+				attribute.String("command", strings.Join(c.exec.Command, " ")),
+				attribute.String("template.id", c.templateID),
+			),
+			trace.WithLinks(trace.Link{SpanContext: c.spanContext}))
+		start := time.Now()
+		_, err := spawnChild(&spawnChildInput{
 			Stdin:        r.inStream,
 			Stdout:       r.outStream,
 			Stderr:       r.errStream,
-			Command:      t.Exec.Command,
+			Command:      c.exec.Command,
 			Env:          env.Env(),
-			Timeout:      config.TimeDurationVal(t.Exec.Timeout),
-			ReloadSignal: config.SignalVal(t.Exec.ReloadSignal),
-			KillSignal:   config.SignalVal(t.Exec.KillSignal),
-			KillTimeout:  config.TimeDurationVal(t.Exec.KillTimeout),
-			Splay:        config.TimeDurationVal(t.Exec.Splay),
-		}); err != nil {
+			Timeout:      config.TimeDurationVal(c.exec.Timeout),
+			ReloadSignal: config.SignalVal(c.exec.ReloadSignal),
+			KillSignal:   config.SignalVal(c.exec.KillSignal),
+			KillTimeout:  config.TimeDurationVal(c.exec.KillTimeout),
+			Splay:        config.TimeDurationVal(c.exec.Splay),
+		})
+		recordCommandMetrics(c.exec.Command, start, err)
+		endSpan(cmdSpan, err)
+		if err != nil {
 			s := fmt.Sprintf("failed to execute command %q from %s",
-				fmt.Sprintf("%q", t.Exec.Command), t.Display())
+				fmt.Sprintf("%q", c.exec.Command), c.display)
 			errs = append(errs, errors.Wrap(err, s))
 		}
 	}
@@ -753,12 +879,62 @@ func (r *Runner) SetReadyChannel(ch chan struct{}) {
 
 type templateRunCtx struct {
 	// commands is the set of commands that will be executed after all templates
-	// have run. When adding to the commands, care should be taken not to
-	// duplicate any existing command from a previous template.
-	commands []*config.TemplateConfig
+	// have run. Commands must be added with queueCommand so that a command is
+	// not duplicated.
+	commands []*queuedCommand
 
 	// depsMap is the set of dependencies shared across all templates.
 	depsMap map[string]dep.Dependency
+
+	// templateIDs are the IDs of the templates that use each dependency in
+	// depsMap.
+	templateIDs map[string][]string
+}
+
+// addDependency records that the template uses the dependency.
+func (c *templateRunCtx) addDependency(tmpl *template.Template, d dep.Dependency) {
+	if _, ok := c.depsMap[d.String()]; !ok {
+		c.depsMap[d.String()] = d
+	}
+	c.templateIDs[d.String()] = append(c.templateIDs[d.String()], tmpl.ID())
+}
+
+// queuedCommand is a command queued by a template or template group that
+// rendered.
+type queuedCommand struct {
+	exec *config.ExecConfig
+
+	// display identifies the template or template group in logs, and
+	// logFields are its structured log fields.
+	display   string
+	logFields string
+
+	// templateID is the template that queued the command, if any, and
+	// spanContext is the span of its run so that the command's span can be
+	// linked back to it.
+	templateID  string
+	spanContext trace.SpanContext
+}
+
+// queueCommand appends the command to the commands to run, unless the same
+// command was already queued.
+//
+// Future-self Q&A: Why not use a map for the commands instead of an array with
+// an expensive lookup option? Well I'm glad you asked that future-self! One of
+// the API promises is that commands are executed in the order in which they
+// are provided in the TemplateConfig definitions. If we inserted commands into
+// a map, we would lose that relative ordering and people would be unhappy.
+func (c *templateRunCtx) queueCommand(q *queuedCommand) {
+	for _, existing := range c.commands {
+		if reflect.DeepEqual(q.exec.Command, existing.exec.Command) {
+			log.Printf("[DEBUG] (runner) skipping command %q from %s (already appended from %s)",
+				q.exec.Command, q.display, existing.display)
+			return
+		}
+	}
+	log.Printf("[DEBUG] (runner) appending command %q from %s",
+		q.exec.Command, q.display)
+	c.commands = append(c.commands, q)
 }
 
 // runTemplate is used to run a particular template. It takes as input the
@@ -767,8 +943,150 @@ type templateRunCtx struct {
 // error that occurred. The render event is nil in the case that the template has
 // been already rendered and is a once template or if there is an error and
 // fatal errors are enabled.
-func (r *Runner) runTemplate(tmpl *template.Template, runCtx *templateRunCtx) (*RenderEvent, error) {
-	log.Printf("[DEBUG] (runner) checking template %s", tmpl.ID())
+func (r *Runner) runTemplate(ctx context.Context, tmpl *template.Template, runCtx *templateRunCtx) (*RenderEvent, error) {
+	event, result, err := r.executeTemplate(ctx, tmpl, runCtx)
+	if err != nil || result == nil {
+		return event, err
+	}
+
+	// For each template configuration that is tied to this template, attempt to
+	// render it to disk and accumulate commands for later use.
+	templateConfig := r.templateConfigFor(tmpl)
+	if templateConfig != nil {
+		log.Printf("[DEBUG] (runner) rendering %s%s", templateConfig.Display(),
+			templateLogFields(tmpl))
+
+		// Render the template, taking dry mode into account
+		_, renderSpan := otel.Tracer(tracerName).Start(ctx, "renderer.render",
+			trace.WithAttributes(attribute.String("destination",
+				config.StringVal(templateConfig.Destination))))
+		result, err := r.rendererFn(r.renderInput(templateConfig, result.Output))
+		if err == nil {
+			renderSpan.SetAttributes(
+				attribute.Bool("would_render", result.WouldRender),
+				attribute.Bool("did_render", result.DidRender),
+			)
+		}
+		endSpan(renderSpan, err)
+		if err != nil {
+			metrics.IncrCounterWithLabels([]string{"runner", "template", "error"}, 1,
+				templateMetricLabels(tmpl))
+
+			// A failed validation leaves the previous file in place, but is
+			// otherwise handled like any other rendering error.
+			if tmpl.ErrFatal() {
+				return nil, errors.Wrap(err, "error rendering "+templateConfig.Display())
+			}
+			log.Printf("[ERR] (runner) error rendering: %s: %v%s", templateConfig.Display(), err,
+				templateLogFields(tmpl))
+			event.Error = err
+			return event, nil
+		}
+
+		r.recordRender(tmpl, event, result)
+
+		// If the template was rendered (changed) and we are not in dry-run mode,
+		// aggregate commands, ignoring previously known commands.
+		if result.DidRender && !r.dry {
+			if c := templateConfig.Exec; !c.Command.Empty() {
+				runCtx.queueCommand(&queuedCommand{
+					exec:        c,
+					display:     templateConfig.Display(),
+					logFields:   templateLogFields(tmpl),
+					templateID:  tmpl.ID(),
+					spanContext: trace.SpanContextFromContext(ctx),
+				})
+			}
+		}
+	}
+
+	return event, nil
+}
+
+// runTemplateGroup runs every template in the group and renders them together
+// once all of them are ready, so that either every changed file is replaced or
+// none are. The group's command is queued once if any file changed. It returns
+// the render events of the templates, which are nil for once templates that
+// were already rendered, and any fatal error.
+func (r *Runner) runTemplateGroup(ctx context.Context, g *templateGroup, runCtx *templateRunCtx) ([]*RenderEvent, error) {
+	display := g.config.Display()
+	log.Printf("[DEBUG] (runner) checking %s", display)
+
+	events := make([]*RenderEvent, len(g.templates))
+	inputs := make([]*renderer.RenderInput, 0, len(g.templates))
+	ready := true
+	for i, tmpl := range g.templates {
+		event, result, err := r.executeTemplate(ctx, tmpl, runCtx)
+		if err != nil {
+			return nil, err
+		}
+		events[i] = event
+
+		// Keep executing the rest of the group so that all of its
+		// dependencies are watched.
+		if result == nil {
+			ready = false
+			continue
+		}
+		inputs = append(inputs, r.renderInput(r.templateConfigFor(tmpl), result.Output))
+	}
+
+	if !ready {
+		log.Printf("[DEBUG] (runner) %s is not ready to render", display)
+		return events, nil
+	}
+
+	log.Printf("[DEBUG] (runner) rendering %s", display)
+
+	_, renderSpan := otel.Tracer(tracerName).Start(ctx, "renderer.render_group",
+		trace.WithAttributes(attribute.String("template_group",
+			config.StringVal(g.config.Name))))
+	results, err := r.groupRendererFn(inputs)
+	endSpan(renderSpan, err)
+	if err != nil {
+		fatal := false
+		for i, tmpl := range g.templates {
+			metrics.IncrCounterWithLabels([]string{"runner", "template", "error"}, 1,
+				templateMetricLabels(tmpl))
+			events[i].Error = err
+			fatal = fatal || tmpl.ErrFatal()
+		}
+
+		if fatal {
+			return nil, errors.Wrap(err, "error rendering "+display)
+		}
+		log.Printf("[ERR] (runner) error rendering: %s: %v", display, err)
+		return events, nil
+	}
+
+	renderedAny := false
+	for i, tmpl := range g.templates {
+		r.recordRender(tmpl, events[i], results[i])
+		renderedAny = renderedAny || results[i].DidRender
+	}
+
+	if renderedAny && !r.dry {
+		if c := g.config.Exec; !c.Command.Empty() {
+			runCtx.queueCommand(&queuedCommand{
+				exec:        c,
+				display:     display,
+				logFields:   logging.Fields("template_group", config.StringVal(g.config.Name)),
+				spanContext: trace.SpanContextFromContext(ctx),
+			})
+		}
+	}
+
+	return events, nil
+}
+
+// executeTemplate executes the template and watches its dependencies. It
+// returns the render event for the template and the result of the execution,
+// which is nil unless the template is ready to be rendered. The render event
+// is nil in the case that the template has been already rendered and is a
+// once template or if there is an error and fatal errors are enabled.
+func (r *Runner) executeTemplate(ctx context.Context, tmpl *template.Template, runCtx *templateRunCtx) (*RenderEvent, *template.ExecuteResult, error) {
+	log.Printf("[DEBUG] (runner) checking template %s%s", tmpl.ID(),
+		templateLogFields(tmpl))
 
 	// Grab the last event
 	r.renderEventsLock.RLock()
@@ -801,36 +1119,39 @@ func (r *Runner) runTemplate(tmpl *template.Template, runCtx *templateRunCtx) (*
 		r.renderEventsLock.RUnlock()
 		if ok && (onceEvent.WouldRender || onceEvent.DidRender) {
 			log.Printf("[DEBUG] (runner) once mode and already rendered")
-			return nil, nil
+			return nil, nil, nil
 		}
 	}
 
 	// Attempt to render the template, returning any missing dependencies and
 	// the rendered contents. If there are any missing dependencies, the
 	// contents cannot be rendered or trusted!
+	_, execSpan := otel.Tracer(tracerName).Start(ctx, "template.execute")
 	result, err := tmpl.Execute(&template.ExecuteInput{
 		Brain:  r.brain,
 		Env:    r.childEnv(),
 		Config: &r.finalConfigCopy,
 	})
+	endSpan(execSpan, err)
 	if err != nil {
+		metrics.IncrCounterWithLabels([]string{"runner", "template", "error"}, 1,
+			templateMetricLabels(tmpl))
 		if tmpl.ErrFatal() {
-			return nil, errors.Wrap(err, tmpl.Source())
+			return nil, nil, errors.Wrap(err, tmpl.Source())
 		}
-		log.Printf("[ERR] (runner) %s: %v", tmpl.Source(), err)
+		log.Printf("[ERR] (runner) %s: %v%s", tmpl.Source(), err,
+			templateLogFields(tmpl))
 		event.Error = err
 
 		if lastEvent != nil {
 			// Keep watching our dependencies so that we retry when they update.
 			for _, d := range lastEvent.UsedDeps.List() {
-				if _, ok := runCtx.depsMap[d.String()]; !ok {
-					runCtx.depsMap[d.String()] = d
-				}
+				runCtx.addDependency(tmpl, d)
 			}
 			event.UsedDeps = lastEvent.UsedDeps
 		}
 
-		return event, nil
+		return event, nil, nil
 	}
 
 	// Grab the list of used and missing dependencies.
@@ -839,7 +1160,8 @@ func (r *Runner) runTemplate(tmpl *template.Template, runCtx *templateRunCtx) (*
 	if l := missing.Len(); l > 0 {
 		log.Printf("[DEBUG] (runner) missing data for %d dependencies", l)
 		for _, missingDependency := range missing.List() {
-			log.Printf("[DEBUG] (runner) missing dependency: %s", missingDependency)
+			log.Printf("[DEBUG] (runner) missing dependency: %s%s", missingDependency,
+				templateLogFields(tmpl, "dependency", missingDependency.String()))
 		}
 	}
 
@@ -849,12 +1171,16 @@ func (r *Runner) runTemplate(tmpl *template.Template, runCtx *templateRunCtx) (*
 		// that is cached, but not have the watcher. We must treat this as
 		// missing so that we create the watcher and re-run the template.
 		if isLeader && !r.watcher.Watching(d) {
-			log.Printf("[DEBUG] (runner) add used dependency %s to missing since isLeader but do not have a watcher", d)
-			missing.Add(d)
-		}
-		if _, ok := runCtx.depsMap[d.String()]; !ok {
-			runCtx.depsMap[d.String()] = d
+			// Data loaded from the cache is used until the watcher replaces it.
+			if r.isStale(d) {
+				log.Printf("[DEBUG] (runner) watching %s to replace cached data", d)
+				r.watcher.Add(d, tmpl.ID())
+			} else {
+				log.Printf("[DEBUG] (runner) add used dependency %s to missing since isLeader but do not have a watcher", d)
+				missing.Add(d)
+			}
 		}
+		runCtx.addDependency(tmpl, d)
 	}
 
 	// Diff any missing dependencies the template reported with dependencies
@@ -866,8 +1192,16 @@ func (r *Runner) runTemplate(tmpl *template.Template, runCtx *templateRunCtx) (*
 		}
 	}
 
+	stale := new(dep.Set)
+	for _, d := range used.List() {
+		if r.isStale(d) {
+			stale.Add(d)
+		}
+	}
+
 	// Update the event with the new dependency information
 	event.MissingDeps = missing
+	event.StaleDeps = stale
 	event.UnwatchedDeps = unwatched
 	event.UsedDeps = used
 	event.UpdatedAt = time.Now().UTC()
@@ -880,17 +1214,17 @@ func (r *Runner) runTemplate(tmpl *template.Template, runCtx *templateRunCtx) (*
 			// If we are deduplicating, we must still handle non-sharable
 			// dependencies, since those will be ignored.
 			if isLeader || !d.CanShare() {
-				r.watcher.Add(d)
+				r.watcher.Add(d, runCtx.templateIDs[d.String()]...)
 			}
 		}
-		return event, nil
+		return event, nil, nil
 	}
 
 	// If the template is missing data for some dependencies then we are not
 	// ready to render and need to move on to the next one.
 	if l := missing.Len(); l > 0 {
 		log.Printf("[DEBUG] (runner) missing data for %d dependencies", l)
-		return event, nil
+		return event, nil, nil
 	}
 
 	// Trigger an update of the de-duplication manager
@@ -900,100 +1234,129 @@ func (r *Runner) runTemplate(tmpl *template.Template, runCtx *templateRunCtx) (*
 		}
 	}
 
-	if r.quiescenceRun != nil && r.quiescenceRun != tmpl {
+	// Templates in a group share the quiescence timer of the group.
+	quiescenceTmpl := r.quiescenceTemplate(tmpl)
+
+	if r.quiescenceRun != nil && r.quiescenceRun != quiescenceTmpl {
 		// During a run triggered via quiescence, mark any template not corresponding to
 		// the quiescence timer as ForQuiescence, signaling it was purposefully skipped.
 		event.ForQuiescence = true
-		return event, nil
+		return event, nil, nil
 	}
 
 	// If quiescence is activated, start/update the timers and loop back around.
 	// We do not want to render the templates yet.
-	if q, ok := r.quiescenceMap[tmpl.ID()]; ok {
+	if q, ok := r.quiescenceMap[quiescenceTmpl.ID()]; ok {
 		q.tick()
 		// This event is being returned early for quiescence
 		event.ForQuiescence = true
-		return event, nil
+		return event, nil, nil
 	}
 
-	// For each template configuration that is tied to this template, attempt to
-	// render it to disk and accumulate commands for later use.
-	templateConfig := r.templateConfigFor(tmpl)
-	if templateConfig != nil {
-		log.Printf("[DEBUG] (runner) rendering %s", templateConfig.Display())
+	if l := stale.Len(); l > 0 {
+		log.Printf("[WARN] (runner) rendering with cached data for %d dependencies%s", l,
+			templateLogFields(tmpl))
+	}
 
-		// Render the template, taking dry mode into account
-		result, err := r.rendererFn(&renderer.RenderInput{
-			Backup:         config.BoolVal(templateConfig.Backup),
-			Contents:       result.Output,
-			CreateDestDirs: config.BoolVal(templateConfig.CreateDestDirs),
-			Dry:            r.dry,
-			DryStream:      r.outStream,
-			Path:           config.StringVal(templateConfig.Destination),
-			Perms:          config.FileModeVal(templateConfig.Perms),
-			User:           config.StringVal(templateConfig.User),
-			Group:          config.StringVal(templateConfig.Group),
-		})
-		if err != nil {
-			if tmpl.ErrFatal() {
-				return nil, errors.Wrap(err, "error rendering "+templateConfig.Display())
-			}
-			log.Printf("[ERR] (runner) error rendering: %s: %v", templateConfig.Display(), err)
-			event.Error = err
-			return event, nil
-		}
+	return event, result, nil
+}
+
+// renderInput returns the renderer input for writing the contents to the
+// template's destination.
+func (r *Runner) renderInput(t *config.TemplateConfig, contents []byte) *renderer.RenderInput {
+	return &renderer.RenderInput{
+		Backup:         config.BoolVal(t.Backup),
+		Contents:       contents,
+		CreateDestDirs: config.BoolVal(t.CreateDestDirs),
+		Dry:            r.dry,
+		DryFormat:      r.config.DryFormat,
+		DryStream:      r.outStream,
+		Path:           config.StringVal(t.Destination),
+		Perms:          config.FileModeVal(t.Perms),
+		User:           config.StringVal(t.User),
+		Group:          config.StringVal(t.Group),
+		Validate:       r.validateFunc(t),
+	}
+}
+
+// recordRender updates the render event and metrics with the result of
+// rendering the template.
+func (r *Runner) recordRender(tmpl *template.Template, event *RenderEvent, result *renderer.RenderResult) {
+	renderTime := time.Now().UTC()
+
+	// If we would have rendered this template (but we did not because the
+	// contents were the same or something), we should consider this template
+	// rendered even though the contents on disk have not been updated. We
+	// will not fire commands unless the template was _actually_ rendered to
+	// disk though.
+	if result.WouldRender {
+		// This event would have rendered
+		event.WouldRender = true
+		event.LastWouldRender = renderTime
+		metrics.IncrCounterWithLabels([]string{"runner", "template", "would_render"}, 1,
+			templateMetricLabels(tmpl))
+	}
+
+	// If we _actually_ rendered the template to disk, we want to run the
+	// appropriate commands.
+	if result.DidRender {
+		log.Printf("[INFO] (runner) rendered %s%s", r.templateConfigFor(tmpl).Display(),
+			templateLogFields(tmpl))
+
+		// This event did render
+		event.DidRender = true
+		event.LastDidRender = renderTime
+		metrics.IncrCounterWithLabels([]string{"runner", "template", "did_render"}, 1,
+			templateMetricLabels(tmpl))
+
+		// Update the contents
+		event.Contents = result.Contents
+	}
+}
+
+// validateFunc returns the renderer validate function that runs the template's
+// validate command against the staged file, or nil if it has none.
+func (r *Runner) validateFunc(t *config.TemplateConfig) func(string) error {
+	if t.ValidateCommand.Empty() {
+		return nil
+	}
 
-		renderTime := time.Now().UTC()
-
-		// If we would have rendered this template (but we did not because the
-		// contents were the same or something), we should consider this template
-		// rendered even though the contents on disk have not been updated. We
-		// will not fire commands unless the template was _actually_ rendered to
-		// disk though.
-		if result.WouldRender {
-			// This event would have rendered
-			event.WouldRender = true
-			event.LastWouldRender = renderTime
+	return func(path string) error {
+		command := make([]string, len(t.ValidateCommand))
+		for i, c := range t.ValidateCommand {
+			command[i] = strings.ReplaceAll(c, validatePathPlaceholder, path)
 		}
 
-		// If we _actually_ rendered the template to disk, we want to run the
-		// appropriate commands.
-		if result.DidRender {
-			log.Printf("[INFO] (runner) rendered %s", templateConfig.Display())
-
-			// This event did render
-			event.DidRender = true
-			event.LastDidRender = renderTime
-
-			// Update the contents
-			event.Contents = result.Contents
-
-			if !r.dry {
-				// If the template was rendered (changed) and we are not in dry-run mode,
-				// aggregate commands, ignoring previously known commands
-				//
-				// Future-self Q&A: Why not use a map for the commands instead of an
-				// array with an expensive lookup option? Well I'm glad you asked that
-				// future-self! One of the API promises is that commands are executed
-				// in the order in which they are provided in the TemplateConfig
-				// definitions. If we inserted commands into a map, we would lose that
-				// relative ordering and people would be unhappy.
-				if c := templateConfig.Exec.Command; !c.Empty() {
-					existing := findCommand(templateConfig, runCtx.commands)
-					if existing != nil {
-						log.Printf("[DEBUG] (runner) skipping command %q from %s (already appended from %s)",
-							c, templateConfig.Display(), existing.Display())
-					} else {
-						log.Printf("[DEBUG] (runner) appending command %q from %s",
-							c, templateConfig.Display())
-						runCtx.commands = append(runCtx.commands, templateConfig)
-					}
-				}
+		log.Printf("[INFO] (runner) validating %s with %q%s", t.Display(), command,
+			logging.Fields("destination", config.StringVal(t.Destination)))
+
+		env := t.Exec.Env.Copy()
+		env.Custom = append(r.childEnv(), env.Custom...)
+		env.Custom = append(env.Custom, validatePathEnv+"="+path)
+
+		// Wait for the command to exit even if no timeout is set.
+		timeout := config.TimeDurationVal(t.ValidateCommandTimeout)
+		var out bytes.Buffer
+		c, err := spawnChild(&spawnChildInput{
+			Stdout:  &out,
+			Stderr:  &out,
+			Command: command,
+			Env:     env.Env(),
+			Timeout: timeout,
+		})
+		if err == nil && timeout == 0 {
+			if code := <-c.ExitCh(); code != 0 {
+				err = &child.ExitError{Command: c.Command(), ExitCode: code}
 			}
 		}
+		if err != nil {
+			if output := strings.TrimSpace(out.String()); output != "" {
+				return fmt.Errorf("validate command %q: %w\n%s", command, err, output)
+			}
+			return fmt.Errorf("validate command %q: %w", command, err)
+		}
+		return nil
 	}
-
-	return event, nil
 }
 
 // init() creates the Runner's underlying data structures and returns an error
@@ -1012,10 +1375,17 @@ func (r *Runner) init(clients *dep.ClientSet) error {
 
 	dep.SetVaultDefaultLeaseDuration(config.TimeDurationVal(r.config.Vault.DefaultLeaseDuration))
 	dep.SetVaultLeaseRenewalThreshold(*r.config.Vault.LeaseRenewalThreshold)
+	dep.SetPreparedQuerySleepTime(config.TimeDurationVal(r.config.Consul.PreparedQueryInterval))
 
 	// Create the watcher
 	r.watcher = newWatcher(r.config, clients)
 
+	if config.BoolVal(r.config.Cache.Enabled) {
+		if err := r.loadCache(); err != nil {
+			return err
+		}
+	}
+
 	numTemplates := len(*r.config.Templates)
 	templates := make([]*template.Template, 0, numTemplates)
 
@@ -1024,29 +1394,7 @@ func (r *Runner) init(clients *dep.ClientSet) error {
 	// config templates is kept so templates can lookup their commands and output
 	// destinations.
 	for _, ctmpl := range *r.config.Templates {
-		leftDelim := config.StringVal(ctmpl.LeftDelim)
-		if leftDelim == "" {
-			leftDelim = config.StringVal(r.config.DefaultDelims.Left)
-		}
-		rightDelim := config.StringVal(ctmpl.RightDelim)
-		if rightDelim == "" {
-			rightDelim = config.StringVal(r.config.DefaultDelims.Right)
-		}
-
-		tmpl, err := template.NewTemplate(&template.NewTemplateInput{
-			Source:           config.StringVal(ctmpl.Source),
-			Contents:         config.StringVal(ctmpl.Contents),
-			ErrMissingKey:    config.BoolVal(ctmpl.ErrMissingKey),
-			ErrFatal:         config.BoolVal(ctmpl.ErrFatal),
-			LeftDelim:        leftDelim,
-			RightDelim:       rightDelim,
-			ExtFuncMap:       ctmpl.ExtFuncMap,
-			FunctionDenylist: ctmpl.FunctionDenylist,
-			SandboxPath:      config.StringVal(ctmpl.SandboxPath),
-			Destination:      config.StringVal(ctmpl.Destination),
-			Config:           ctmpl,
-			ReaderFunc:       r.config.ReaderFunc,
-		})
+		tmpl, err := r.newTemplate(ctmpl)
 		if err != nil {
 			return err
 		}
@@ -1054,6 +1402,35 @@ func (r *Runner) init(clients *dep.ClientSet) error {
 		templates = append(templates, tmpl)
 	}
 
+	// The templates of each group are run with the group, but are otherwise
+	// treated like any other template.
+	r.groups = make([]*templateGroup, 0, len(*r.config.TemplateGroups))
+	r.templateGroups = make(map[string]*templateGroup)
+	for _, cgroup := range *r.config.TemplateGroups {
+		if len(*cgroup.Templates) == 0 {
+			return fmt.Errorf("%s: must contain at least one template", cgroup.Display())
+		}
+
+		g := &templateGroup{config: cgroup}
+		for _, ctmpl := range *cgroup.Templates {
+			if !ctmpl.Exec.Command.Empty() {
+				return fmt.Errorf("%s: %s cannot have its own command, use the "+
+					"command of the group instead", cgroup.Display(), ctmpl.Display())
+			}
+
+			tmpl, err := r.newTemplate(ctmpl)
+			if err != nil {
+				return err
+			}
+
+			g.templates = append(g.templates, tmpl)
+			r.templateGroups[tmpl.ID()] = g
+			templates = append(templates, tmpl)
+			numTemplates++
+		}
+		r.groups = append(r.groups, g)
+	}
+
 	// Convert the map of templates (which was only used to ensure uniqueness)
 	// back into an array of templates.
 	r.templates = templates
@@ -1074,6 +1451,101 @@ func (r *Runner) init(clients *dep.ClientSet) error {
 	return nil
 }
 
+// newTemplate parses the template for the given configuration.
+func (r *Runner) newTemplate(ctmpl *config.TemplateConfig) (*template.Template, error) {
+	leftDelim := config.StringVal(ctmpl.LeftDelim)
+	if leftDelim == "" {
+		leftDelim = config.StringVal(r.config.DefaultDelims.Left)
+	}
+	rightDelim := config.StringVal(ctmpl.RightDelim)
+	if rightDelim == "" {
+		rightDelim = config.StringVal(r.config.DefaultDelims.Right)
+	}
+
+	return template.NewTemplate(&template.NewTemplateInput{
+		Source:           config.StringVal(ctmpl.Source),
+		Contents:         config.StringVal(ctmpl.Contents),
+		ErrMissingKey:    config.BoolVal(ctmpl.ErrMissingKey),
+		ErrFatal:         config.BoolVal(ctmpl.ErrFatal),
+		LeftDelim:        leftDelim,
+		RightDelim:       rightDelim,
+		ExtFuncMap:       ctmpl.ExtFuncMap,
+		FunctionDenylist: ctmpl.FunctionDenylist,
+		SandboxPath:      config.StringVal(ctmpl.SandboxPath),
+		Destination:      config.StringVal(ctmpl.Destination),
+		Config:           ctmpl,
+		ReaderFunc:       r.config.ReaderFunc,
+	})
+}
+
+// quiescenceTemplate returns the template whose quiescence timer applies to
+// the given template. It is the first template of the group for templates in
+// a group, and the template itself otherwise.
+func (r *Runner) quiescenceTemplate(tmpl *template.Template) *template.Template {
+	if g, ok := r.templateGroups[tmpl.ID()]; ok {
+		return g.templates[0]
+	}
+	return tmpl
+}
+
+// loadCache creates the on-disk cache and remembers its data in the brain,
+// marked as stale until live data arrives. A cache that cannot be read is
+// ignored, so that it never prevents startup.
+func (r *Runner) loadCache() error {
+	c, err := cache.New(config.StringVal(r.config.Cache.Path),
+		config.StringVal(r.config.Cache.EncryptionKeyFile))
+	if err != nil {
+		return err
+	}
+	r.cache = c
+
+	data, err := c.Load()
+	if err != nil {
+		log.Printf("[WARN] (runner) not using cached data: %v", err)
+		return nil
+	}
+
+	r.staleLock.Lock()
+	defer r.staleLock.Unlock()
+	for key, value := range data {
+		r.brain.ForceSet(key, value)
+		r.staleDeps[key] = struct{}{}
+	}
+	log.Printf("[INFO] (runner) loaded cached data for %d dependencies", len(data))
+	return nil
+}
+
+// saveCache writes the current data of the Consul and Nomad dependencies in
+// use to the on-disk cache.
+func (r *Runner) saveCache() {
+	r.dependenciesLock.Lock()
+	data := make(map[string]interface{}, len(r.dependencies))
+	for key, d := range r.dependencies {
+		switch d.Type() {
+		case dep.TypeConsul, dep.TypeNomad:
+		default:
+			continue
+		}
+		if value, ok := r.brain.Recall(d); ok {
+			data[key] = value
+		}
+	}
+	r.dependenciesLock.Unlock()
+
+	if err := r.cache.Save(data); err != nil {
+		log.Printf("[WARN] (runner) failed to save cache: %v", err)
+	}
+}
+
+// isStale returns true if the data for the dependency was loaded from the
+// cache and has not been replaced by live data yet.
+func (r *Runner) isStale(d dep.Dependency) bool {
+	r.staleLock.RLock()
+	defer r.staleLock.RUnlock()
+	_, ok := r.staleDeps[d.String()]
+	return ok
+}
+
 // diffAndUpdateDeps iterates through the current map of dependencies on this
 // runner and stops the watcher for any deps that are no longer required.
 //
@@ -1371,16 +1843,77 @@ func (q *quiescence) tick() {
 	}
 }
 
-// findCommand searches the list of template configs for the given command and
-// returns it if it exists.
-func findCommand(c *config.TemplateConfig, templates []*config.TemplateConfig) *config.TemplateConfig {
-	needle := c.Exec.Command
-	for _, t := range templates {
-		if reflect.DeepEqual(needle, t.Exec.Command) {
-			return t
+// templateMetricLabels returns the labels attached to metrics emitted for the
+// given template.
+func templateMetricLabels(tmpl *template.Template) []metrics.Label {
+	var destination string
+	if c := tmpl.Config(); c != nil {
+		destination = config.StringVal(c.Destination)
+	}
+	return []metrics.Label{
+		{Name: "template_id", Value: tmpl.ID()},
+		{Name: "destination", Value: destination},
+	}
+}
+
+// templateLogFields returns the structured log fields identifying the given
+// template, followed by any extra key/value pairs.
+func templateLogFields(tmpl *template.Template, kv ...string) string {
+	var destination string
+	if c := tmpl.Config(); c != nil {
+		destination = config.StringVal(c.Destination)
+	}
+	return logging.Fields(append([]string{
+		"template_id", tmpl.ID(),
+		"destination", destination,
+	}, kv...)...)
+}
+
+// recordCommandMetrics records the duration and exit code of a template
+// command that was started at the given time and returned the given error.
+func recordCommandMetrics(command []string, start time.Time, err error) {
+	labels := []metrics.Label{
+		{Name: "command", Value: strings.Join(command, " ")},
+	}
+	metrics.MeasureSinceWithLabels([]string{"runner", "command", "duration"}, start, labels)
+
+	code := "0"
+	if err != nil {
+		code = "error"
+		var exitErr *child.ExitError
+		if errors.As(err, &exitErr) {
+			code = strconv.Itoa(exitErr.ExitCode)
+		}
+	}
+	metrics.IncrCounterWithLabels([]string{"runner", "command", "exit"}, 1,
+		append(labels, metrics.Label{Name: "exit_code", Value: code}))
+}
+
+// endTemplateSpan records the outcome of a template run on its span and ends
+// it.
+func endTemplateSpan(span trace.Span, event *RenderEvent, err error) {
+	if event != nil {
+		span.SetAttributes(
+			attribute.Bool("would_render", event.WouldRender),
+			attribute.Bool("did_render", event.DidRender),
+		)
+		if event.MissingDeps != nil {
+			span.SetAttributes(attribute.Int("missing_dependencies", event.MissingDeps.Len()))
+		}
+		if err == nil {
+			err = event.Error
 		}
 	}
-	return nil
+	endSpan(span, err)
+}
+
+// endSpan marks the span as failed if err is non-nil and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
 }
 
 // NewClientSet creates a new client set from the given config.
@@ -1414,6 +1947,11 @@ func NewClientSet(c *config.Config) (*dep.ClientSet, error) {
 		return nil, fmt.Errorf("runner: %s", err)
 	}
 
+	leaseStore, err := newVaultLeaseStore(c.Vault.LeaseStore)
+	if err != nil {
+		return nil, fmt.Errorf("runner: %s", err)
+	}
+
 	if err := clients.CreateVaultClient(&dep.CreateVaultClientInput{
 		Address:                      config.StringVal(c.Vault.Address),
 		Namespace:                    config.StringVal(c.Vault.Namespace),
@@ -1442,6 +1980,8 @@ func NewClientSet(c *config.Config) (*dep.ClientSet, error) {
 		K8SServiceAccountTokenPath:   config.StringVal(c.Vault.K8SServiceAccountTokenPath),
 		K8SServiceAccountToken:       config.StringVal(c.Vault.K8SServiceAccountToken),
 		K8SServiceMountPath:          config.StringVal(c.Vault.K8SServiceMountPath),
+		Auth:                         newVaultAuth(c.Vault.Auth),
+		LeaseStore:                   leaseStore,
 	}); err != nil {
 		return nil, fmt.Errorf("runner: %s", err)
 	}
@@ -1475,6 +2015,42 @@ func NewClientSet(c *config.Config) (*dep.ClientSet, error) {
 	return clients, nil
 }
 
+// newVaultAuth creates the Vault auth method from the given config, or returns
+// nil if no auth method is configured.
+func newVaultAuth(c *config.VaultAuthConfig) *dep.VaultAuth {
+	if c == nil || config.StringVal(c.Method) == "" {
+		return nil
+	}
+
+	return &dep.VaultAuth{
+		Method:          config.StringVal(c.Method),
+		MountPath:       config.StringVal(c.MountPath),
+		RoleIDFile:      config.StringVal(c.RoleIDFile),
+		SecretIDFile:    config.StringVal(c.SecretIDFile),
+		SecretIDWrapped: config.BoolVal(c.SecretIDWrapped),
+		Role:            config.StringVal(c.Role),
+		JWTFile:         config.StringVal(c.JWTFile),
+		Username:        config.StringVal(c.Username),
+		Password:        config.StringVal(c.Password),
+		PasswordFile:    config.StringVal(c.PasswordFile),
+	}
+}
+
+// newVaultLeaseStore creates the store for the leases of Vault secrets from the
+// given config, or returns nil if the store is not enabled.
+func newVaultLeaseStore(c *config.VaultLeaseStoreConfig) (dep.VaultLeaseStore, error) {
+	if c == nil || !config.BoolVal(c.Enabled) {
+		return nil, nil
+	}
+
+	s, err := cache.NewLeaseStore(config.StringVal(c.Path),
+		config.StringVal(c.EncryptionKeyFile))
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
 // newWatcher creates a new watcher.
 func newWatcher(c *config.Config, clients *dep.ClientSet) *watch.Watcher {
 	log.Printf("[INFO] (runner) creating watcher")
@@ -15,13 +15,18 @@ import (
 	"reflect"
 	"strings"
 	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 
+	"github.com/hashicorp/consul-template/cache"
 	"github.com/hashicorp/consul-template/child"
 	"github.com/hashicorp/consul-template/config"
 	dep "github.com/hashicorp/consul-template/dependency"
 	"github.com/hashicorp/consul-template/template"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestRunner_initTemplates(t *testing.T) {
@@ -153,7 +158,12 @@ func TestRunner_Run(t *testing.T) {
 				if _, err := os.Stat("/foo/bar"); err == nil {
 					t.Errorf("expected file to not exist")
 				}
-				exp := "> /foo/bar\nhello"
+				exp := "> /foo/bar (new)\n" +
+					"--- /dev/null\n" +
+					"+++ /foo/bar\n" +
+					"@@ -0,0 +1 @@\n" +
+					"+hello\n" +
+					"\\ No newline at end of file\n"
 				if out != exp {
 					t.Errorf("\nexp: %#v\nact: %#v", exp, out)
 				}
@@ -504,6 +514,461 @@ func TestRunner_Run(t *testing.T) {
 	}
 }
 
+func TestRunner_Run_validate(t *testing.T) {
+	cases := []struct {
+		name     string
+		validate string
+		errFatal bool
+		exp      string
+		ran      bool
+		err      string
+	}{
+		{
+			"passes",
+			`test "$(cat {{path}})" = new && test "$CONSUL_TEMPLATE_VALIDATE_PATH" = {{path}}`,
+			true,
+			"new",
+			true,
+			"",
+		},
+		{
+			"fails",
+			`echo "syntax error" && false`,
+			true,
+			"old",
+			false,
+			"syntax error",
+		},
+		{
+			"fails_not_fatal",
+			`echo "syntax error" && false`,
+			false,
+			"old",
+			false,
+			"syntax error",
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			dest := filepath.Join(t.TempDir(), "out")
+			if err := os.WriteFile(dest, []byte("old"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			c := config.TestConfig(&config.Config{
+				Templates: &config.TemplateConfigs{
+					&config.TemplateConfig{
+						Contents:        config.String("new"),
+						Command:         []string{"echo command ran"},
+						Destination:     config.String(dest),
+						ValidateCommand: []string{tc.validate},
+						ErrFatal:        config.Bool(tc.errFatal),
+					},
+				},
+			})
+			c.Once = true
+			c.Finalize()
+
+			r, err := NewRunner(c, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out bytes.Buffer
+			r.outStream, r.errStream = &out, &out
+			defer r.Stop()
+
+			fatal := tc.errFatal && tc.err != ""
+			err = r.Run()
+			switch {
+			case fatal && (err == nil || !strings.Contains(err.Error(), tc.err)):
+				t.Fatalf("expected error containing %q, got %v", tc.err, err)
+			case !fatal && err != nil:
+				t.Fatal(err)
+			}
+
+			b, err := os.ReadFile(dest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tc.exp {
+				t.Errorf("expected %q to be %q", b, tc.exp)
+			}
+			if ran := strings.Contains(out.String(), "command ran"); ran != tc.ran {
+				t.Errorf("expected command ran to be %t", tc.ran)
+			}
+
+			if fatal {
+				return
+			}
+			for _, event := range r.RenderEvents() {
+				switch {
+				case tc.err == "" && event.Error != nil:
+					t.Errorf("unexpected error: %s", event.Error)
+				case tc.err != "" && (event.Error == nil || !strings.Contains(event.Error.Error(), tc.err)):
+					t.Errorf("expected error containing %q, got %v", tc.err, event.Error)
+				}
+				if event.DidRender != tc.ran {
+					t.Errorf("expected did render to be %t", tc.ran)
+				}
+			}
+		})
+	}
+}
+
+func TestRunner_Run_cache(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input")
+	out := filepath.Join(dir, "out")
+	cachePath := filepath.Join(dir, "cache")
+
+	d, err := dep.NewFileQuery(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := cache.New(cachePath, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Save(map[string]interface{}{d.String(): "cached"}); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewRunner(config.TestConfig(&config.Config{
+		Cache: &config.CacheConfig{
+			Path: config.String(cachePath),
+		},
+		Templates: &config.TemplateConfigs{
+			&config.TemplateConfig{
+				Contents:    config.String(`{{ file "` + input + `" }}`),
+				Destination: config.String(out),
+			},
+		},
+	}), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Stop()
+
+	// The input does not exist, so the template renders from the cache.
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "cached" {
+		t.Errorf("expected %q, got %q", "cached", b)
+	}
+	for _, event := range r.RenderEvents() {
+		if event.StaleDeps == nil || event.StaleDeps.Len() != 1 {
+			t.Errorf("expected 1 stale dependency, got %#v", event.StaleDeps)
+		}
+	}
+
+	// Live data replaces the cached data.
+	r.Receive(d, "live")
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+	b, err = os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "live" {
+		t.Errorf("expected %q, got %q", "live", b)
+	}
+	for _, event := range r.RenderEvents() {
+		if event.StaleDeps.Len() != 0 {
+			t.Errorf("expected no stale dependencies, got %#v", event.StaleDeps)
+		}
+	}
+}
+
+func TestRunner_Run_templateGroup(t *testing.T) {
+	cases := []struct {
+		name     string
+		validate string
+		rendered bool
+	}{
+		{
+			"renders_together",
+			"",
+			true,
+		},
+		{
+			"validate_fails",
+			"false",
+			false,
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			dir := t.TempDir()
+			cert, key := filepath.Join(dir, "cert"), filepath.Join(dir, "key")
+			input := filepath.Join(dir, "input")
+			if err := os.WriteFile(input, []byte("new key"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			// A failed validation is not fatal so that the events can be
+			// checked.
+			var validate []string
+			if tc.validate != "" {
+				validate = []string{tc.validate}
+			}
+			c := config.TestConfig(&config.Config{
+				TemplateGroups: &config.TemplateGroupConfigs{
+					&config.TemplateGroupConfig{
+						Name: config.String("tls"),
+						Exec: &config.ExecConfig{
+							Command: []string{"echo group command ran"},
+						},
+						Templates: &config.TemplateConfigs{
+							&config.TemplateConfig{
+								Contents:    config.String("new cert"),
+								Destination: config.String(cert),
+								ErrFatal:    config.Bool(false),
+							},
+							&config.TemplateConfig{
+								Contents:        config.String(`{{ file "` + input + `" }}`),
+								Destination:     config.String(key),
+								ValidateCommand: validate,
+								ErrFatal:        config.Bool(false),
+							},
+						},
+					},
+				},
+			})
+
+			r, err := NewRunner(c, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out bytes.Buffer
+			r.outStream, r.errStream = &out, &out
+			defer r.Stop()
+
+			// The key is missing its file dependency, so neither file is
+			// written on the first run.
+			if err := r.Run(); err != nil {
+				t.Fatal(err)
+			}
+			for _, path := range []string{cert, key} {
+				if _, err := os.Stat(path); !os.IsNotExist(err) {
+					t.Fatalf("expected %s to not exist: %v", path, err)
+				}
+			}
+
+			for _, event := range r.RenderEvents() {
+				for _, d := range event.MissingDeps.List() {
+					r.Receive(d, "new key")
+				}
+			}
+			if err := r.Run(); err != nil {
+				t.Fatal(err)
+			}
+
+			for path, exp := range map[string]string{cert: "new cert", key: "new key"} {
+				b, err := os.ReadFile(path)
+				if tc.rendered && (err != nil || string(b) != exp) {
+					t.Errorf("expected %s to be %q: %q, %v", path, exp, b, err)
+				}
+				if !tc.rendered && !os.IsNotExist(err) {
+					t.Errorf("expected %s to not exist: %v", path, err)
+				}
+			}
+
+			ran := strings.Count(out.String(), "group command ran")
+			if tc.rendered && ran != 1 {
+				t.Errorf("expected group command to run once, ran %d times", ran)
+			}
+			if !tc.rendered && ran != 0 {
+				t.Errorf("expected group command to not run, ran %d times", ran)
+			}
+
+			for _, event := range r.RenderEvents() {
+				if event.DidRender != tc.rendered {
+					t.Errorf("expected did render to be %t", tc.rendered)
+				}
+				if (event.Error != nil) == tc.rendered {
+					t.Errorf("unexpected error: %v", event.Error)
+				}
+			}
+		})
+	}
+}
+
+func TestRunner_Start_onceValidateFails(t *testing.T) {
+	cases := []struct {
+		name   string
+		config *config.Config
+	}{
+		{
+			"template",
+			&config.Config{
+				Templates: &config.TemplateConfigs{
+					&config.TemplateConfig{
+						Contents:        config.String("new"),
+						ValidateCommand: []string{"false"},
+					},
+				},
+			},
+		},
+		{
+			"template_group",
+			&config.Config{
+				TemplateGroups: &config.TemplateGroupConfigs{
+					&config.TemplateGroupConfig{
+						Templates: &config.TemplateConfigs{
+							&config.TemplateConfig{
+								Contents:        config.String("new"),
+								ValidateCommand: []string{"false"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			dir := t.TempDir()
+			tmpls := tc.config.Templates
+			if tc.config.TemplateGroups != nil {
+				tmpls = (*tc.config.TemplateGroups)[0].Templates
+			}
+			for j, tmpl := range *tmpls {
+				tmpl.Destination = config.String(filepath.Join(dir, fmt.Sprint(j)))
+			}
+
+			c := config.TestConfig(tc.config)
+			c.Once = true
+			c.Finalize()
+
+			r, err := NewRunner(c, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			go r.Start()
+			defer r.Stop()
+
+			// Once mode must exit with the validation error instead of
+			// waiting for the template to render.
+			select {
+			case err := <-r.ErrCh:
+				if !strings.Contains(err.Error(), "validation failed") {
+					t.Errorf("expected validation error, got %s", err)
+				}
+			case <-r.DoneCh:
+				t.Fatal("expected an error")
+			case <-time.After(5 * time.Second):
+				t.Fatal("timeout")
+			}
+		})
+	}
+}
+
+func TestRunner_templateGroupCommand(t *testing.T) {
+	c := config.TestConfig(&config.Config{
+		TemplateGroups: &config.TemplateGroupConfigs{
+			&config.TemplateGroupConfig{
+				Name: config.String("tls"),
+				Templates: &config.TemplateConfigs{
+					&config.TemplateConfig{
+						Contents: config.String("cert"),
+						Command:  []string{"reload"},
+					},
+				},
+			},
+		},
+	})
+
+	_, err := NewRunner(c, false)
+	if err == nil || !strings.Contains(err.Error(), "cannot have its own command") {
+		t.Errorf("expected command error, got %v", err)
+	}
+}
+
+func TestRunner_Run_tracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(prev)
+
+	dest := filepath.Join(t.TempDir(), "out")
+	c := config.TestConfig(&config.Config{
+		Templates: &config.TemplateConfigs{
+			&config.TemplateConfig{
+				Contents:    config.String("hello"),
+				Command:     []string{"echo 123"},
+				Destination: config.String(dest),
+			},
+		},
+	})
+	c.Once = true
+	c.Finalize()
+
+	r, err := NewRunner(c, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	r.outStream, r.errStream = &out, &out
+	defer r.Stop()
+
+	if err := r.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, s := range recorder.Ended() {
+		spans[s.Name()] = s
+	}
+	for _, name := range []string{
+		"runner.run", "runner.run_template", "template.execute",
+		"renderer.render", "runner.command",
+	} {
+		if _, ok := spans[name]; !ok {
+			t.Fatalf("missing span %q", name)
+		}
+	}
+
+	run := spans["runner.run"]
+	tmpl := spans["runner.run_template"]
+	cmd := spans["runner.command"]
+
+	if tmpl.Parent().SpanID() != run.SpanContext().SpanID() {
+		t.Errorf("expected runner.run_template to be a child of runner.run")
+	}
+	if p := spans["renderer.render"].Parent().SpanID(); p != tmpl.SpanContext().SpanID() {
+		t.Errorf("expected renderer.render to be a child of runner.run_template")
+	}
+	if cmd.Parent().SpanID() != run.SpanContext().SpanID() {
+		t.Errorf("expected runner.command to be a child of runner.run")
+	}
+	if l := cmd.Links(); len(l) != 1 || l[0].SpanContext.SpanID() != tmpl.SpanContext().SpanID() {
+		t.Errorf("expected runner.command to link to runner.run_template, got %#v", l)
+	}
+
+	id := r.templates[0].ID()
+	for _, s := range []sdktrace.ReadOnlySpan{tmpl, cmd} {
+		var found bool
+		for _, kv := range s.Attributes() {
+			if kv.Key == "template.id" && kv.Value.AsString() == id {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected %s to have template.id %q", s.Name(), id)
+		}
+	}
+}
+
 func TestRunner_Start(t *testing.T) {
 	t.Run("store_pid", func(t *testing.T) {
 		pid, err := os.CreateTemp("", "")
@@ -1094,7 +1559,12 @@ func TestRunner_Start(t *testing.T) {
 			if exp != act {
 				t.Errorf("\nexp: %#v\nact: %#v", exp, act)
 			}
-			expOut := "> \nfoo"
+			expOut := "> (new)\n" +
+				"--- /dev/null\n" +
+				"+++ \n" +
+				"@@ -0,0 +1 @@\n" +
+				"+foo\n" +
+				"\\ No newline at end of file\n"
 			if expOut != o.String() {
 				t.Errorf("\nexp: %#v\nact: %#v", expOut, o.String())
 			}
@@ -1485,3 +1955,87 @@ func TestRunner_stoppedWatcher(t *testing.T) {
 		t.Fatal("watcher had dependencies added after stop")
 	}
 }
+
+// testLeaseDep is a dependency holding a Vault lease, which blocks until it is
+// stopped.
+type testLeaseDep struct {
+	leaseID string
+	stopCh  chan struct{}
+}
+
+func (d *testLeaseDep) Fetch(*dep.ClientSet, *dep.QueryOptions) (interface{}, *dep.ResponseMetadata, error) {
+	<-d.stopCh
+	return nil, nil, dep.ErrStopped
+}
+
+func (d *testLeaseDep) CanShare() bool  { return false }
+func (d *testLeaseDep) LeaseID() string { return d.leaseID }
+func (d *testLeaseDep) Stop()           { close(d.stopCh) }
+func (d *testLeaseDep) String() string  { return "test.lease(" + d.leaseID + ")" }
+func (d *testLeaseDep) Type() dep.Type  { return dep.TypeVault }
+
+func TestRunner_Stop_revokesLeasesAfterChild(t *testing.T) {
+	dir := t.TempDir()
+	ready := filepath.Join(dir, "ready")
+	stopped := filepath.Join(dir, "stopped")
+
+	// The child must already be stopped when its lease is revoked.
+	var revoked atomic.Bool
+	vaultServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/sys/leases/revoke" {
+			if _, err := os.Stat(stopped); err != nil {
+				t.Errorf("lease revoked before the child was stopped: %s", err)
+			}
+			revoked.Store(true)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(vaultServer.Close)
+
+	c := config.DefaultConfig().Merge(&config.Config{
+		Vault: &config.VaultConfig{
+			Address:          &vaultServer.URL,
+			Token:            config.String("token"),
+			RevokeOnShutdown: config.Bool(true),
+		},
+	})
+	c.Finalize()
+
+	r, err := NewRunner(c, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.child, err = spawnChild(&spawnChildInput{
+		Command: []string{fmt.Sprintf(
+			"trap 'touch %s; exit 0' TERM; touch %s; while true; do sleep 0.1; done",
+			stopped, ready)},
+		KillSignal:  syscall.SIGTERM,
+		KillTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Wait for the child to trap the kill signal.
+	for i := 0; ; i++ {
+		if _, err := os.Stat(ready); err == nil {
+			break
+		}
+		if i == 50 {
+			t.Fatal("child did not start")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	d := &testLeaseDep{leaseID: "database/creds/app/1", stopCh: make(chan struct{})}
+	if _, err := r.watcher.Add(d); err != nil {
+		t.Fatal(err)
+	}
+
+	r.Stop()
+
+	if !revoked.Load() {
+		t.Error("expected the lease to be revoked")
+	}
+}
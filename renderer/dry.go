@@ -0,0 +1,170 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package renderer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const (
+	// DryFormatDiff prints a unified diff between the destination on disk and
+	// the rendered contents. It is the default dry mode format.
+	DryFormatDiff = "diff"
+
+	// DryFormatJSON prints each DryChange as a JSON object on its own line.
+	DryFormatJSON = "json"
+
+	// DryFormatContents prints the full rendered contents of each changed file.
+	DryFormatContents = "contents"
+)
+
+const (
+	// DryStatusNew is a destination that does not exist yet.
+	DryStatusNew = "new"
+
+	// DryStatusModified is a destination whose contents change.
+	DryStatusModified = "modified"
+
+	// DryStatusAttributes is a destination whose contents are unchanged but
+	// whose mode or ownership would change.
+	DryStatusAttributes = "attributes"
+
+	// DryStatusUnchanged is a destination that would not change at all.
+	DryStatusUnchanged = "unchanged"
+)
+
+// noNewline marks a last line without a trailing newline in a diff, the same
+// way diff(1) and git do.
+const noNewline = "\n\\ No newline at end of file\n"
+
+// DryChange describes what rendering a template would change on disk. It is
+// what dry mode prints in the JSON format.
+type DryChange struct {
+	// Path is the destination of the template.
+	Path string `json:"path"`
+
+	// Status is one of DryStatusNew, DryStatusModified, DryStatusAttributes or
+	// DryStatusUnchanged.
+	Status string `json:"status"`
+
+	// OldMode and NewMode are the file modes before and after rendering, if
+	// they differ. OldMode is empty for new files.
+	OldMode string `json:"old_mode,omitempty"`
+	NewMode string `json:"new_mode,omitempty"`
+
+	// User and Group are the requested ownership, if it would change.
+	User  string `json:"user,omitempty"`
+	Group string `json:"group,omitempty"`
+
+	// Diff is a unified diff from the destination to the rendered contents.
+	Diff string `json:"diff,omitempty"`
+}
+
+// ValidDryFormat returns an error if the given dry mode format is unknown.
+func ValidDryFormat(format string) error {
+	switch format {
+	case "", DryFormatDiff, DryFormatJSON, DryFormatContents:
+		return nil
+	default:
+		return fmt.Errorf("invalid dry format %q, must be one of %q, %q or %q",
+			format, DryFormatDiff, DryFormatJSON, DryFormatContents)
+	}
+}
+
+// Diff returns a unified diff from a to b, labelled with the given file names,
+// or an empty string if they are equal.
+func Diff(fromFile, toFile string, a, b []byte) string {
+	if string(a) == string(b) {
+		return ""
+	}
+	// GetUnifiedDiffString only fails when writing to its buffer fails.
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(string(a)),
+		B:        splitLines(string(b)),
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  3,
+	})
+	return diff
+}
+
+// splitLines splits s into newline-terminated lines. Unlike
+// difflib.SplitLines, a trailing newline does not produce an extra empty line
+// and a missing one is marked in the diff.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if last := lines[len(lines)-1]; last == "" {
+		lines = lines[:len(lines)-1]
+	} else {
+		lines[len(lines)-1] = last + noNewline
+	}
+	return lines
+}
+
+// dryChange builds the DryChange for rendering the input over the existing
+// destination, which is nil if it does not exist.
+func dryChange(i *RenderInput, existing []byte, info os.FileInfo, chownNeeded, chmodNeeded bool) *DryChange {
+	c := &DryChange{Path: i.Path}
+
+	switch {
+	case info == nil:
+		c.Status = DryStatusNew
+		perms := i.Perms
+		if perms == 0 {
+			perms = DefaultFilePerms
+		}
+		c.NewMode = fmt.Sprintf("%#o", perms.Perm())
+		c.Diff = Diff("/dev/null", i.Path, nil, i.Contents)
+	case string(existing) != string(i.Contents):
+		c.Status = DryStatusModified
+		c.Diff = Diff(i.Path, i.Path, existing, i.Contents)
+	case chownNeeded || chmodNeeded:
+		c.Status = DryStatusAttributes
+	default:
+		c.Status = DryStatusUnchanged
+	}
+
+	if chmodNeeded {
+		c.OldMode = fmt.Sprintf("%#o", info.Mode().Perm())
+		c.NewMode = fmt.Sprintf("%#o", i.Perms.Perm())
+	}
+	if chownNeeded {
+		c.User, c.Group = i.User, i.Group
+	}
+
+	return c
+}
+
+// writeDry prints the change to w in the given format.
+func writeDry(w io.Writer, format string, c *DryChange, contents []byte) error {
+	switch format {
+	case DryFormatContents:
+		if c.Status == DryStatusUnchanged {
+			return nil
+		}
+		_, err := fmt.Fprintf(w, "> %s\n%s", c.Path, contents)
+		return err
+	case DryFormatJSON:
+		return json.NewEncoder(w).Encode(c)
+	}
+
+	details := []string{c.Status}
+	if c.OldMode != "" {
+		details = append(details, fmt.Sprintf("mode %s => %s", c.OldMode, c.NewMode))
+	}
+	if c.User != "" || c.Group != "" {
+		details = append(details, fmt.Sprintf("owner %s:%s", c.User, c.Group))
+	}
+	_, err := fmt.Fprintf(w, "> %s (%s)\n%s", c.Path, strings.Join(details, ", "), c.Diff)
+	return err
+}
@@ -0,0 +1,209 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package renderer
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	cases := []struct {
+		name string
+		a    string
+		b    string
+		exp  string
+	}{
+		{
+			"equal",
+			"a\nb\n",
+			"a\nb\n",
+			"",
+		},
+		{
+			"changed_line",
+			"a\nb\nc\n",
+			"a\nB\nc\n",
+			"--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			"added_trailing_newline",
+			"a",
+			"a\n",
+			"--- old\n+++ new\n@@ -1 +1 @@\n-a\n\\ No newline at end of file\n+a\n",
+		},
+		{
+			"from_empty",
+			"",
+			"a\n",
+			"--- old\n+++ new\n@@ -0,0 +1 @@\n+a\n",
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			act := Diff("old", "new", []byte(tc.a), []byte(tc.b))
+			if act != tc.exp {
+				t.Errorf("\nexp: %q\nact: %q", tc.exp, act)
+			}
+		})
+	}
+}
+
+func TestRender_dry(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not compared on Windows")
+	}
+
+	cases := []struct {
+		name     string
+		existing *string
+		contents string
+		perms    os.FileMode
+		format   string
+		exp      string
+	}{
+		{
+			"new",
+			nil,
+			"a\n",
+			0,
+			"",
+			"> PATH (new)\n--- /dev/null\n+++ PATH\n@@ -0,0 +1 @@\n+a\n",
+		},
+		{
+			"modified",
+			strPtr("a\nb\n"),
+			"a\nc\n",
+			0,
+			DryFormatDiff,
+			"> PATH (modified)\n--- PATH\n+++ PATH\n@@ -1,2 +1,2 @@\n a\n-b\n+c\n",
+		},
+		{
+			"modified_mode",
+			strPtr("a\n"),
+			"b\n",
+			0o600,
+			DryFormatDiff,
+			"> PATH (modified, mode 0644 => 0600)\n--- PATH\n+++ PATH\n@@ -1 +1 @@\n-a\n+b\n",
+		},
+		{
+			"mode_only",
+			strPtr("a\n"),
+			"a\n",
+			0o600,
+			DryFormatDiff,
+			"> PATH (attributes, mode 0644 => 0600)\n",
+		},
+		{
+			"unchanged",
+			strPtr("a\n"),
+			"a\n",
+			0o644,
+			DryFormatDiff,
+			"> PATH (unchanged)\n",
+		},
+		{
+			"json_new",
+			nil,
+			"a\n",
+			0,
+			DryFormatJSON,
+			`{"path":"PATH","status":"new","new_mode":"0644","diff":"--- /dev/null\n+++ PATH\n@@ -0,0 +1 @@\n+a\n"}` + "\n",
+		},
+		{
+			"json_mode_only",
+			strPtr("a\n"),
+			"a\n",
+			0o600,
+			DryFormatJSON,
+			`{"path":"PATH","status":"attributes","old_mode":"0644","new_mode":"0600"}` + "\n",
+		},
+		{
+			"json_unchanged",
+			strPtr("a\n"),
+			"a\n",
+			0,
+			DryFormatJSON,
+			`{"path":"PATH","status":"unchanged"}` + "\n",
+		},
+		{
+			"contents",
+			strPtr("a\n"),
+			"b\n",
+			0,
+			DryFormatContents,
+			"> PATH\nb\n",
+		},
+		{
+			"contents_unchanged",
+			strPtr("a\n"),
+			"a\n",
+			0,
+			DryFormatContents,
+			"",
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "out")
+			if tc.existing != nil {
+				if err := os.WriteFile(path, []byte(*tc.existing), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				// Ignore the umask.
+				if err := os.Chmod(path, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var out bytes.Buffer
+			if _, err := Render(&RenderInput{
+				Contents:  []byte(tc.contents),
+				Dry:       true,
+				DryFormat: tc.format,
+				DryStream: &out,
+				Path:      path,
+				Perms:     tc.perms,
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			exp := bytes.ReplaceAll([]byte(tc.exp), []byte("PATH"), []byte(path))
+			if !bytes.Equal(exp, out.Bytes()) {
+				t.Errorf("\nexp: %q\nact: %q", exp, out.String())
+			}
+
+			// Dry mode never touches the destination.
+			b, err := os.ReadFile(path)
+			if tc.existing == nil {
+				if !os.IsNotExist(err) {
+					t.Errorf("expected %s to not exist: %v", path, err)
+				}
+			} else if string(b) != *tc.existing {
+				t.Errorf("expected %q to be %q", b, *tc.existing)
+			}
+		})
+	}
+}
+
+func TestValidDryFormat(t *testing.T) {
+	for _, f := range []string{"", DryFormatDiff, DryFormatJSON, DryFormatContents} {
+		if err := ValidDryFormat(f); err != nil {
+			t.Errorf("expected %q to be valid: %s", f, err)
+		}
+	}
+	if err := ValidDryFormat("xml"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -24,3 +24,9 @@ func preserveFilePermissions(path string, fileInfo os.FileInfo) error {
 
 	return nil
 }
+
+// isChmodNeeded returns true if perms is set and differs from the permissions
+// of the existing file.
+func isChmodNeeded(fileInfo os.FileInfo, perms os.FileMode) bool {
+	return perms != 0 && fileInfo.Mode().Perm() != perms.Perm()
+}
@@ -13,3 +13,8 @@ import "os"
 func preserveFilePermissions(path string, fileInfo os.FileInfo) error {
 	return nil
 }
+
+// Permissions are not compared on Windows for the same reason.
+func isChmodNeeded(fileInfo os.FileInfo, perms os.FileMode) bool {
+	return false
+}
@@ -0,0 +1,141 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package renderer
+
+import (
+	"log"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// GroupRenderer renders a set of files all-or-nothing.
+type GroupRenderer func([]*RenderInput) ([]*RenderResult, error)
+
+// RenderGroup renders the contents of every input to disk together. Every
+// changed file is first staged and validated next to its destination, and
+// only when all of them are ready are the destinations replaced. If replacing
+// any destination fails, the ones already replaced are restored to their
+// previous contents, or removed if they did not exist. The results are in the
+// same order as the inputs.
+func RenderGroup(inputs []*RenderInput) ([]*RenderResult, error) {
+	plans := make([]*renderPlan, len(inputs))
+	results := make([]*RenderResult, len(inputs))
+	for n, i := range inputs {
+		p, err := planRender(i)
+		if err != nil {
+			return nil, errors.Wrap(err, i.Path)
+		}
+		plans[n] = p
+
+		if p.unchanged {
+			results[n] = &RenderResult{
+				DidRender:   false,
+				WouldRender: true,
+				Contents:    p.existing,
+			}
+			continue
+		}
+		results[n] = &RenderResult{
+			DidRender:   true,
+			WouldRender: true,
+			Contents:    i.Contents,
+		}
+	}
+
+	var writes []*groupWrite
+	defer func() {
+		for _, w := range writes {
+			w.cleanup()
+		}
+	}()
+
+	for n, i := range inputs {
+		p := plans[n]
+		if p.unchanged || i.Dry {
+			continue
+		}
+
+		w := &groupWrite{input: i}
+		writes = append(writes, w)
+
+		staged, err := stage(i.Path, i.CreateDestDirs, i.Contents, i.Perms, i.Validate)
+		if err != nil {
+			if _, ok := err.(*ValidateError); ok {
+				return nil, err
+			}
+			return nil, errors.Wrap(err, "failed writing file "+i.Path)
+		}
+		w.staged = staged
+
+		if err := setFileOwnership(staged, p.uid, p.gid); err != nil {
+			return nil, errors.Wrap(err, "failed setting file ownership "+i.Path)
+		}
+
+		// Keep a copy of the current file to restore if a later file fails,
+		// with the same mode and ownership.
+		if p.info != nil {
+			previous, err := stage(i.Path, false, p.existing, p.info.Mode().Perm(), nil)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed copying file "+i.Path)
+			}
+			w.previous = previous
+
+			if err := preserveFilePermissions(previous, p.info); err != nil {
+				return nil, errors.Wrap(err, "failed setting file ownership "+i.Path)
+			}
+		}
+	}
+
+	for n, w := range writes {
+		if err := commit(w.staged, w.input.Path, w.input.Backup); err != nil {
+			for _, done := range writes[:n] {
+				done.rollback()
+			}
+			return nil, errors.Wrap(err, "failed writing file "+w.input.Path)
+		}
+		w.staged = ""
+	}
+
+	return results, nil
+}
+
+// groupWrite tracks a file written by RenderGroup.
+type groupWrite struct {
+	input *RenderInput
+
+	// staged is the TempFile holding the new contents until it is committed.
+	staged string
+
+	// previous is a TempFile holding the contents of the destination before
+	// it was replaced, or empty if it did not exist.
+	previous string
+}
+
+// rollback restores the destination to what it was before it was replaced.
+func (w *groupWrite) rollback() {
+	path := w.input.Path
+	if w.previous == "" {
+		if err := os.Remove(path); err != nil {
+			log.Printf("[ERR] (runner) could not remove %q during rollback: %v", path, err)
+		}
+		return
+	}
+
+	if err := os.Rename(w.previous, path); err != nil {
+		log.Printf("[ERR] (runner) could not restore %q during rollback: %v", path, err)
+		return
+	}
+	w.previous = ""
+}
+
+// cleanup removes any TempFiles that are left over.
+func (w *groupWrite) cleanup() {
+	if w.staged != "" {
+		os.Remove(w.staged)
+	}
+	if w.previous != "" {
+		os.Remove(w.previous)
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package renderer
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestRenderGroup(t *testing.T) {
+	t.Run("renders-all", func(t *testing.T) {
+		dir := t.TempDir()
+		unchanged := filepath.Join(dir, "unchanged")
+		if err := os.WriteFile(unchanged, []byte("same"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		inputs := []*RenderInput{
+			{Path: filepath.Join(dir, "cert"), Contents: []byte("cert")},
+			{Path: unchanged, Contents: []byte("same")},
+			{Path: filepath.Join(dir, "key"), Contents: []byte("key"), Perms: 0o600},
+		}
+		results, err := RenderGroup(inputs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i, exp := range []bool{true, false, true} {
+			if results[i].DidRender != exp || !results[i].WouldRender {
+				t.Errorf("%s: expected did render to be %t: %#v", inputs[i].Path, exp, results[i])
+			}
+			expectContents(t, inputs[i].Path, string(inputs[i].Contents))
+		}
+
+		info, err := os.Stat(inputs[2].Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0o600 {
+			t.Errorf("expected %s to be %s", info.Mode().Perm(), os.FileMode(0o600))
+		}
+		expectFiles(t, dir, 3)
+	})
+
+	t.Run("validate-fails", func(t *testing.T) {
+		dir := t.TempDir()
+		cert := filepath.Join(dir, "cert")
+		if err := os.WriteFile(cert, []byte("old"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := RenderGroup([]*RenderInput{
+			{Path: cert, Contents: []byte("new")},
+			{
+				Path:     filepath.Join(dir, "key"),
+				Contents: []byte("key"),
+				Validate: func(string) error { return errors.New("bad key") },
+			},
+		})
+		var verr *ValidateError
+		if !errors.As(err, &verr) {
+			t.Fatalf("expected validate error, got %v", err)
+		}
+
+		expectContents(t, cert, "old")
+		expectFiles(t, dir, 1)
+	})
+
+	t.Run("rolls-back", func(t *testing.T) {
+		dir := t.TempDir()
+		cert, key := filepath.Join(dir, "cert"), filepath.Join(dir, "key")
+		if err := os.WriteFile(cert, []byte("old"), 0o640); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(cert, 0o640); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := RenderGroup([]*RenderInput{
+			{Path: cert, Contents: []byte("new")},
+			{Path: filepath.Join(dir, "new"), Contents: []byte("new")},
+			{
+				Path:     key,
+				Contents: []byte("key"),
+				// Make replacing the key fail after every file was staged.
+				Validate: func(string) error {
+					return os.MkdirAll(filepath.Join(key, "dir"), 0o755)
+				},
+			},
+		})
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		expectContents(t, cert, "old")
+		info, err := os.Stat(cert)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0o640 {
+			t.Errorf("expected %s to be %s", info.Mode().Perm(), os.FileMode(0o640))
+		}
+		if _, err := os.Stat(filepath.Join(dir, "new")); !os.IsNotExist(err) {
+			t.Errorf("expected new file to be removed: %v", err)
+		}
+		// Only the cert and the directory in the way of the key are left.
+		expectFiles(t, dir, 2)
+	})
+
+	t.Run("rolls-back-ownership", func(t *testing.T) {
+		// The cert is owned by a group other than the one it is rendered
+		// with, which root can use without being a member.
+		gid := -1
+		groups, err := os.Getgroups()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, g := range groups {
+			if g != os.Getgid() {
+				gid = g
+				break
+			}
+		}
+		if gid == -1 && os.Getuid() == 0 {
+			gid = os.Getgid() + 1
+		}
+		if gid == -1 {
+			t.Skip("This test requires the caller be in at least 2 groups, skipping...")
+		}
+
+		dir := t.TempDir()
+		cert, key := filepath.Join(dir, "cert"), filepath.Join(dir, "key")
+		if err := os.WriteFile(cert, []byte("old"), 0o640); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chmod(cert, 0o640); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chown(cert, -1, gid); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = RenderGroup([]*RenderInput{
+			{
+				Path:     cert,
+				Contents: []byte("new"),
+				Group:    strconv.Itoa(os.Getgid()),
+				Perms:    0o600,
+			},
+			{
+				Path:     key,
+				Contents: []byte("key"),
+				Validate: func(string) error {
+					return os.MkdirAll(filepath.Join(key, "dir"), 0o755)
+				},
+			},
+		})
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		expectContents(t, cert, "old")
+		info, err := os.Stat(cert)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0o640 {
+			t.Errorf("expected %s to be %s", info.Mode().Perm(), os.FileMode(0o640))
+		}
+		_, gotGid, err := getFileOwnership(cert)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gotGid != gid {
+			t.Errorf("expected gid %d to be %d", gotGid, gid)
+		}
+		expectFiles(t, dir, 2)
+	})
+
+	t.Run("dry", func(t *testing.T) {
+		dir := t.TempDir()
+		results, err := RenderGroup([]*RenderInput{
+			{Path: filepath.Join(dir, "cert"), Contents: []byte("cert"), Dry: true, DryStream: io.Discard},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !results[0].DidRender {
+			t.Errorf("expected did render in dry mode")
+		}
+		expectFiles(t, dir, 0)
+	})
+}
+
+// expectFiles fails the test if the directory does not contain exactly n
+// entries, which catches leftover temporary files.
+func expectFiles(t *testing.T, dir string, n int) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != n {
+		names := make([]string, len(entries))
+		for i, e := range entries {
+			names[i] = e.Name()
+		}
+		t.Errorf("expected %d files in %s, got %q", n, dir, names)
+	}
+}
@@ -5,7 +5,6 @@ package renderer
 
 import (
 	"bytes"
-	"fmt"
 	"io"
 	"log"
 	"os"
@@ -35,10 +34,31 @@ type RenderInput struct {
 	Contents       []byte
 	CreateDestDirs bool
 	Dry            bool
+	DryFormat      string
 	DryStream      io.Writer
 	Path           string
 	Perms          os.FileMode
 	User, Group    string
+
+	// Validate, if set, is called with the path of a temporary file holding
+	// the new contents before it replaces the destination. If it returns an
+	// error, the destination is left untouched and Render returns a
+	// *ValidateError.
+	Validate func(path string) error
+}
+
+// ValidateError is returned by Render when the Validate function rejects the
+// new contents. The destination is left untouched.
+type ValidateError struct {
+	Err error
+}
+
+func (e *ValidateError) Error() string {
+	return "validation failed: " + e.Err.Error()
+}
+
+func (e *ValidateError) Unwrap() error {
+	return e.Err
 }
 
 // RenderResult is returned and stored. It contains the status of the render
@@ -64,6 +84,56 @@ type Renderer func(*RenderInput) (*RenderResult, error)
 // Render atomically renders a file contents to disk, returning a result of
 // whether it would have rendered and actually did render.
 func Render(i *RenderInput) (*RenderResult, error) {
+	p, err := planRender(i)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.unchanged {
+		return &RenderResult{
+			DidRender:   false,
+			WouldRender: true,
+			Contents:    p.existing,
+		}, nil
+	}
+
+	if !i.Dry {
+		if err := atomicWrite(i.Path, i.CreateDestDirs, i.Contents, i.Perms, i.Backup, i.Validate); err != nil {
+			if _, ok := err.(*ValidateError); ok {
+				return nil, err
+			}
+			return nil, errors.Wrap(err, "failed writing file")
+		}
+
+		if err = setFileOwnership(i.Path, p.uid, p.gid); err != nil {
+			return nil, errors.Wrap(err, "failed setting file ownership")
+		}
+	}
+
+	return &RenderResult{
+		DidRender:   true,
+		WouldRender: true,
+		Contents:    i.Contents,
+	}, nil
+}
+
+// renderPlan is what rendering an input would change on disk.
+type renderPlan struct {
+	// existing and info are the contents and file info of the destination, or
+	// nil if it does not exist.
+	existing []byte
+	info     os.FileInfo
+
+	// uid and gid are the requested ownership.
+	uid, gid int
+
+	// unchanged is true if the destination already matches the input.
+	unchanged bool
+}
+
+// planRender compares the input to the destination on disk. In dry mode, it
+// also prints the change to the dry stream.
+func planRender(i *RenderInput) (*renderPlan, error) {
 	existing, err := os.ReadFile(i.Path)
 	fileExists := !os.IsNotExist(err)
 	if err != nil && fileExists {
@@ -80,6 +150,7 @@ func Render(i *RenderInput) (*RenderResult, error) {
 	}
 
 	var chownNeeded bool
+	var info os.FileInfo
 
 	if fileExists {
 		chownNeeded, err = isChownNeeded(i.Path, uid, gid)
@@ -87,32 +158,31 @@ func Render(i *RenderInput) (*RenderResult, error) {
 			log.Printf("[WARN] (runner) could not determine existing output file's permissions")
 			chownNeeded = true
 		}
-	}
 
-	if bytes.Equal(existing, i.Contents) && fileExists && !chownNeeded {
-		return &RenderResult{
-			DidRender:   false,
-			WouldRender: true,
-			Contents:    existing,
-		}, nil
+		info, err = os.Stat(i.Path)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed reading file")
+		}
 	}
 
-	if i.Dry {
-		fmt.Fprintf(i.DryStream, "> %s\n%s", i.Path, i.Contents)
-	} else {
-		if err := AtomicWrite(i.Path, i.CreateDestDirs, i.Contents, i.Perms, i.Backup); err != nil {
-			return nil, errors.Wrap(err, "failed writing file")
-		}
+	unchanged := bytes.Equal(existing, i.Contents) && fileExists && !chownNeeded
 
-		if err = setFileOwnership(i.Path, uid, gid); err != nil {
-			return nil, errors.Wrap(err, "failed setting file ownership")
+	if i.Dry {
+		// A different mode is only reported, since rendering does not change
+		// the mode of a file whose contents are unchanged.
+		chmodNeeded := fileExists && isChmodNeeded(info, i.Perms)
+		change := dryChange(i, existing, info, chownNeeded, chmodNeeded)
+		if err := writeDry(i.DryStream, i.DryFormat, change, i.Contents); err != nil {
+			return nil, errors.Wrap(err, "failed writing dry output")
 		}
 	}
 
-	return &RenderResult{
-		DidRender:   true,
-		WouldRender: true,
-		Contents:    i.Contents,
+	return &renderPlan{
+		existing:  existing,
+		info:      info,
+		uid:       uid,
+		gid:       gid,
+		unchanged: unchanged,
 	}, nil
 }
 
@@ -136,37 +206,65 @@ func Render(i *RenderInput) (*RenderResult, error) {
 // Windows and it is impossible to rename atomically on Windows. For more on
 // this see: https://github.com/golang/go/issues/22397#issuecomment-498856679
 func AtomicWrite(path string, createDestDirs bool, contents []byte, perms os.FileMode, backup bool) error {
+	return atomicWrite(path, createDestDirs, contents, perms, backup, nil)
+}
+
+// atomicWrite is AtomicWrite with an optional validate function that is
+// called with the path of the TempFile just before it is renamed. If it
+// returns an error, the destination is left untouched and a *ValidateError
+// is returned.
+func atomicWrite(path string, createDestDirs bool, contents []byte, perms os.FileMode, backup bool, validate func(string) error) error {
+	staged, err := stage(path, createDestDirs, contents, perms, validate)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(staged)
+
+	return commit(staged, path, backup)
+}
+
+// stage writes the contents to a TempFile next to the destination with the
+// permissions the destination should have, validates it and returns its
+// path. The caller is responsible for removing it.
+func stage(path string, createDestDirs bool, contents []byte, perms os.FileMode, validate func(string) error) (string, error) {
 	if path == "" {
-		return ErrMissingDest
+		return "", ErrMissingDest
 	}
 
 	parent := filepath.Dir(path)
 	if _, err := os.Stat(parent); os.IsNotExist(err) {
 		if createDestDirs {
 			if err := os.MkdirAll(parent, 0o755); err != nil {
-				return err
+				return "", err
 			}
 		} else {
-			return ErrNoParentDir
+			return "", ErrNoParentDir
 		}
 	}
 
 	f, err := os.CreateTemp(parent, "")
 	if err != nil {
-		return err
+		return "", err
 	}
-	defer os.Remove(f.Name())
+
+	done := false
+	defer func() {
+		if !done {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
 
 	if _, err := f.Write(contents); err != nil {
-		return err
+		return "", err
 	}
 
 	if err := f.Sync(); err != nil {
-		return err
+		return "", err
 	}
 
 	if err := f.Close(); err != nil {
-		return err
+		return "", err
 	}
 
 	// If the user did not explicitly set permissions, attempt to lookup the
@@ -176,7 +274,7 @@ func AtomicWrite(path string, createDestDirs bool, contents []byte, perms os.Fil
 	currentInfo, err := os.Stat(path)
 	if err != nil {
 		if !os.IsNotExist(err) {
-			return err
+			return "", err
 		}
 	} else {
 		existingPerms = currentInfo.Mode()
@@ -193,9 +291,22 @@ func AtomicWrite(path string, createDestDirs bool, contents []byte, perms os.Fil
 	}
 
 	if err := os.Chmod(f.Name(), perms); err != nil {
-		return err
+		return "", err
 	}
 
+	if validate != nil {
+		if err := validate(f.Name()); err != nil {
+			return "", &ValidateError{Err: err}
+		}
+	}
+
+	done = true
+	return f.Name(), nil
+}
+
+// commit backs up the destination, if requested, and moves the staged file
+// over it.
+func commit(staged, path string, backup bool) error {
 	// If we got this far, it means we are about to save the file. Copy the
 	// current file so we have a backup. Note that os.Link preserves the Mode.
 	if backup {
@@ -208,11 +319,7 @@ func AtomicWrite(path string, createDestDirs bool, contents []byte, perms os.Fil
 		}
 	}
 
-	if err := os.Rename(f.Name(), path); err != nil {
-		return err
-	}
-
-	return nil
+	return os.Rename(staged, path)
 }
 
 // intPtr returns a pointer to the given int.
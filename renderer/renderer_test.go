@@ -9,6 +9,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"testing"
 )
@@ -272,6 +273,100 @@ func TestRender(t *testing.T) {
 				rr.WouldRender, rr.DidRender)
 		}
 	})
+	t.Run("file-exists-diff-perms", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("file modes are not compared on Windows")
+		}
+		path := filepath.Join(t.TempDir(), "out")
+		contents := []byte("first")
+		if err := os.WriteFile(path, contents, 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		rr, err := Render(&RenderInput{
+			Path:     path,
+			Contents: contents,
+			Perms:    0o600,
+		})
+		if err != nil {
+			t.Error(err)
+		}
+		switch {
+		case rr.WouldRender && !rr.DidRender:
+		default:
+			t.Errorf("Bad render results; would: %v, did: %v",
+				rr.WouldRender, rr.DidRender)
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perms := info.Mode().Perm(); perms != 0o644 {
+			t.Errorf("expected %v to be %v", perms, os.FileMode(0o644))
+		}
+	})
+	t.Run("validate", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "out")
+		if err := os.WriteFile(path, []byte("first"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		var staged string
+		rr, err := Render(&RenderInput{
+			Path:     path,
+			Contents: []byte("second"),
+			Validate: func(p string) error {
+				staged = p
+				b, err := os.ReadFile(p)
+				if err != nil {
+					return err
+				}
+				if string(b) != "second" {
+					t.Errorf("expected staged contents %q to be %q", b, "second")
+				}
+				return nil
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !rr.DidRender {
+			t.Errorf("expected template to render")
+		}
+		if filepath.Dir(staged) != filepath.Dir(path) || staged == path {
+			t.Errorf("expected %q to be a temporary file next to %q", staged, path)
+		}
+		expectContents(t, path, "second")
+	})
+	t.Run("validate-fails", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "out")
+		if err := os.WriteFile(path, []byte("first"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		rr, err := Render(&RenderInput{
+			Path:     path,
+			Contents: []byte("second"),
+			Backup:   true,
+			Validate: func(string) error {
+				return fmt.Errorf("bad config")
+			},
+		})
+		if _, ok := err.(*ValidateError); !ok {
+			t.Fatalf("expected validate error, got %v", err)
+		}
+		if rr != nil {
+			t.Errorf("expected no render result, got %#v", rr)
+		}
+		expectContents(t, path, "first")
+		if _, err := os.Stat(path + ".bak"); !os.IsNotExist(err) {
+			t.Errorf("expected no backup to be made: %v", err)
+		}
+		if files, _ := os.ReadDir(filepath.Dir(path)); len(files) != 1 {
+			t.Errorf("expected staged file to be removed, got %d files", len(files))
+		}
+	})
 	t.Run("file-no-exists", func(t *testing.T) {
 		outDir, err := os.MkdirTemp("", "")
 		if err != nil {
@@ -762,3 +857,14 @@ func TestRender_Chown(t *testing.T) {
 		}
 	})
 }
+
+func expectContents(t *testing.T, path, exp string) {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != exp {
+		t.Errorf("expected %q to be %q", b, exp)
+	}
+}
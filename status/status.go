@@ -0,0 +1,280 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/consul-template/config"
+	dep "github.com/hashicorp/consul-template/dependency"
+	"github.com/hashicorp/consul-template/manager"
+	"github.com/hashicorp/consul-template/watch"
+)
+
+const (
+	// StatusPath serves the full status document.
+	StatusPath = "/v1/status"
+
+	// HealthPath always returns 200 while the process is serving requests and
+	// is intended for liveness probes.
+	HealthPath = "/v1/health"
+
+	// ReadyPath returns 200 once every template has rendered at least once and
+	// 503 otherwise. It is intended for readiness probes.
+	ReadyPath = "/v1/ready"
+)
+
+// shutdownTimeout is the maximum amount of time to wait for in-flight requests
+// to complete when stopping the listener.
+const shutdownTimeout = 5 * time.Second
+
+// Source is the interface the status API reads from. It is implemented by
+// *manager.Runner.
+type Source interface {
+	RenderEvents() map[string]*manager.RenderEvent
+	TemplateConfigMapping() map[string][]*config.TemplateConfig
+	DependencyStatus() []*watch.ViewStatus
+}
+
+// Config is the configuration for the status listener.
+type Config struct {
+	// Address is the host:port the listener binds to.
+	Address string
+
+	// Source is where template and dependency state is read from.
+	Source Source
+}
+
+// Server is a running status listener. The zero value is not usable; use
+// Setup to create one.
+type Server struct {
+	source   Source
+	listener net.Listener
+	server   *http.Server
+}
+
+// Status is the document served at StatusPath.
+type Status struct {
+	// Ready is true once every template has rendered at least once.
+	Ready bool `json:"ready"`
+
+	// Templates is the state of each template, sorted by ID.
+	Templates []*TemplateStatus `json:"templates"`
+
+	// Dependencies is the state of each watched dependency, sorted by name.
+	Dependencies []*DependencyStatus `json:"dependencies"`
+}
+
+// TemplateStatus is the state of a single template.
+type TemplateStatus struct {
+	ID                    string     `json:"id"`
+	Sources               []string   `json:"sources"`
+	Destinations          []string   `json:"destinations"`
+	Rendered              bool       `json:"rendered"`
+	WouldRender           bool       `json:"would_render"`
+	DidRender             bool       `json:"did_render"`
+	LastWouldRender       *time.Time `json:"last_would_render,omitempty"`
+	LastDidRender         *time.Time `json:"last_did_render,omitempty"`
+	UpdatedAt             *time.Time `json:"updated_at,omitempty"`
+	UsedDependencies      []string   `json:"used_dependencies"`
+	MissingDependencies   []string   `json:"missing_dependencies"`
+	StaleDependencies     []string   `json:"stale_dependencies"`
+	UnwatchedDependencies []string   `json:"unwatched_dependencies"`
+	Error                 string     `json:"error,omitempty"`
+}
+
+// DependencyStatus is the state of a single watched dependency.
+type DependencyStatus struct {
+	Name         string     `json:"name"`
+	ReceivedData bool       `json:"received_data"`
+	LastIndex    uint64     `json:"last_index"`
+	LastFetch    *time.Time `json:"last_fetch,omitempty"`
+	Error        string     `json:"error,omitempty"`
+}
+
+// Setup starts an HTTP listener serving the status API for the given source.
+func Setup(c *Config) (*Server, error) {
+	ln, err := net.Listen("tcp", c.Address)
+	if err != nil {
+		return nil, fmt.Errorf("status: %w", err)
+	}
+
+	s := &Server{
+		source:   c.Source,
+		listener: ln,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(StatusPath, s.handleStatus)
+	mux.HandleFunc(HealthPath, s.handleHealth)
+	mux.HandleFunc(ReadyPath, s.handleReady)
+
+	s.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	log.Printf("[INFO] (status) serving status API on http://%s", ln.Addr())
+	go func() {
+		if err := s.server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("[ERR] (status) status listener stopped: %s", err)
+		}
+	}()
+
+	return s, nil
+}
+
+// Addr returns the address the listener is bound to.
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Stop shuts down the listener.
+func (s *Server) Stop() {
+	if s == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		log.Printf("[WARN] (status) error stopping status listener: %s", err)
+	}
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, http.StatusOK, Build(s.source))
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]bool{"alive": true})
+}
+
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	ready := Build(s.source).Ready
+	code := http.StatusOK
+	if !ready {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, map[string]bool{"ready": ready})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		log.Printf("[WARN] (status) error writing response: %s", err)
+	}
+}
+
+// Build assembles a status document from the given source. A template counts
+// as rendered once it has rendered at least one time, even if the most recent
+// render failed, since the destination still holds the last good contents.
+func Build(src Source) *Status {
+	events := src.RenderEvents()
+	mapping := src.TemplateConfigMapping()
+
+	ids := make([]string, 0, len(mapping))
+	for id := range mapping {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	st := &Status{
+		Ready:        true,
+		Templates:    make([]*TemplateStatus, 0, len(ids)),
+		Dependencies: []*DependencyStatus{},
+	}
+
+	for _, id := range ids {
+		ts := &TemplateStatus{
+			ID:                    id,
+			Sources:               []string{},
+			Destinations:          []string{},
+			UsedDependencies:      []string{},
+			MissingDependencies:   []string{},
+			StaleDependencies:     []string{},
+			UnwatchedDependencies: []string{},
+		}
+		for _, tc := range mapping[id] {
+			if src := config.StringVal(tc.Source); src != "" {
+				ts.Sources = append(ts.Sources, src)
+			}
+			ts.Destinations = append(ts.Destinations, config.StringVal(tc.Destination))
+		}
+
+		if event, ok := events[id]; ok {
+			ts.Rendered = !event.LastWouldRender.IsZero() || !event.LastDidRender.IsZero()
+			ts.WouldRender = event.WouldRender
+			ts.DidRender = event.DidRender
+			ts.LastWouldRender = timePtr(event.LastWouldRender)
+			ts.LastDidRender = timePtr(event.LastDidRender)
+			ts.UpdatedAt = timePtr(event.UpdatedAt)
+			ts.UsedDependencies = setStrings(event.UsedDeps)
+			ts.MissingDependencies = setStrings(event.MissingDeps)
+			ts.StaleDependencies = setStrings(event.StaleDeps)
+			ts.UnwatchedDependencies = setStrings(event.UnwatchedDeps)
+			if event.Error != nil {
+				ts.Error = event.Error.Error()
+			}
+		}
+
+		if !ts.Rendered {
+			st.Ready = false
+		}
+		st.Templates = append(st.Templates, ts)
+	}
+
+	for _, vs := range src.DependencyStatus() {
+		ds := &DependencyStatus{
+			Name:         vs.Dependency.String(),
+			ReceivedData: vs.ReceivedData,
+			LastIndex:    vs.LastIndex,
+			LastFetch:    timePtr(vs.LastFetch),
+		}
+		if vs.LastError != nil {
+			ds.Error = vs.LastError.Error()
+		}
+		st.Dependencies = append(st.Dependencies, ds)
+	}
+
+	return st
+}
+
+// setStrings returns the sorted string form of each dependency in the set.
+func setStrings(s *dep.Set) []string {
+	r := []string{}
+	if s == nil {
+		return r
+	}
+	for _, d := range s.List() {
+		r = append(r, d.String())
+	}
+	sort.Strings(r)
+	return r
+}
+
+// timePtr returns nil for the zero time so that it is omitted from the JSON
+// output.
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,314 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package status
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul-template/config"
+	dep "github.com/hashicorp/consul-template/dependency"
+	"github.com/hashicorp/consul-template/manager"
+	"github.com/hashicorp/consul-template/watch"
+)
+
+type testSource struct {
+	events  map[string]*manager.RenderEvent
+	mapping map[string][]*config.TemplateConfig
+	deps    []*watch.ViewStatus
+}
+
+func (s *testSource) RenderEvents() map[string]*manager.RenderEvent {
+	return s.events
+}
+
+func (s *testSource) TemplateConfigMapping() map[string][]*config.TemplateConfig {
+	return s.mapping
+}
+
+func (s *testSource) DependencyStatus() []*watch.ViewStatus {
+	return s.deps
+}
+
+func testDep(t *testing.T, s string) dep.Dependency {
+	d, err := dep.NewKVGetQuery(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func testSet(deps ...dep.Dependency) *dep.Set {
+	var s dep.Set
+	for _, d := range deps {
+		s.Add(d)
+	}
+	return &s
+}
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	foo := testDep(t, "foo")
+	bar := testDep(t, "bar")
+
+	mapping := map[string][]*config.TemplateConfig{
+		"a": {{
+			Source:      config.String("/tmp/a.tpl"),
+			Destination: config.String("/tmp/a"),
+		}},
+		"b": {{
+			Contents:    config.String("{{ key \"bar\" }}"),
+			Destination: config.String("/tmp/b"),
+		}},
+	}
+
+	cases := []struct {
+		name string
+		src  *testSource
+		exp  *Status
+	}{
+		{
+			"no_templates",
+			&testSource{},
+			&Status{
+				Ready:        true,
+				Templates:    []*TemplateStatus{},
+				Dependencies: []*DependencyStatus{},
+			},
+		},
+		{
+			"no_events",
+			&testSource{
+				mapping: mapping,
+			},
+			&Status{
+				Ready: false,
+				Templates: []*TemplateStatus{
+					{
+						ID:                    "a",
+						Sources:               []string{"/tmp/a.tpl"},
+						Destinations:          []string{"/tmp/a"},
+						UsedDependencies:      []string{},
+						MissingDependencies:   []string{},
+						StaleDependencies:     []string{},
+						UnwatchedDependencies: []string{},
+					},
+					{
+						ID:                    "b",
+						Sources:               []string{},
+						Destinations:          []string{"/tmp/b"},
+						UsedDependencies:      []string{},
+						MissingDependencies:   []string{},
+						StaleDependencies:     []string{},
+						UnwatchedDependencies: []string{},
+					},
+				},
+				Dependencies: []*DependencyStatus{},
+			},
+		},
+		{
+			"partially_rendered",
+			&testSource{
+				mapping: mapping,
+				events: map[string]*manager.RenderEvent{
+					"a": {
+						UsedDeps:        testSet(foo),
+						MissingDeps:     testSet(),
+						StaleDeps:       testSet(foo),
+						UnwatchedDeps:   testSet(),
+						WouldRender:     true,
+						DidRender:       true,
+						LastWouldRender: now,
+						LastDidRender:   now,
+						UpdatedAt:       now,
+					},
+					"b": {
+						UsedDeps:      testSet(bar),
+						MissingDeps:   testSet(bar),
+						UnwatchedDeps: testSet(),
+						UpdatedAt:     now,
+						Error:         errors.New("boom"),
+					},
+				},
+				deps: []*watch.ViewStatus{
+					{
+						Dependency: bar,
+						LastError:  errors.New("connection refused"),
+					},
+					{
+						Dependency:   foo,
+						ReceivedData: true,
+						LastIndex:    10,
+						LastFetch:    now,
+					},
+				},
+			},
+			&Status{
+				Ready: false,
+				Templates: []*TemplateStatus{
+					{
+						ID:                    "a",
+						Sources:               []string{"/tmp/a.tpl"},
+						Destinations:          []string{"/tmp/a"},
+						Rendered:              true,
+						WouldRender:           true,
+						DidRender:             true,
+						LastWouldRender:       &now,
+						LastDidRender:         &now,
+						UpdatedAt:             &now,
+						UsedDependencies:      []string{"kv.get(foo)"},
+						MissingDependencies:   []string{},
+						StaleDependencies:     []string{"kv.get(foo)"},
+						UnwatchedDependencies: []string{},
+					},
+					{
+						ID:                    "b",
+						Sources:               []string{},
+						Destinations:          []string{"/tmp/b"},
+						UpdatedAt:             &now,
+						UsedDependencies:      []string{"kv.get(bar)"},
+						MissingDependencies:   []string{"kv.get(bar)"},
+						StaleDependencies:     []string{},
+						UnwatchedDependencies: []string{},
+						Error:                 "boom",
+					},
+				},
+				Dependencies: []*DependencyStatus{
+					{
+						Name:  "kv.get(bar)",
+						Error: "connection refused",
+					},
+					{
+						Name:         "kv.get(foo)",
+						ReceivedData: true,
+						LastIndex:    10,
+						LastFetch:    &now,
+					},
+				},
+			},
+		},
+		{
+			"rendered_with_later_error",
+			&testSource{
+				mapping: map[string][]*config.TemplateConfig{"a": mapping["a"]},
+				events: map[string]*manager.RenderEvent{
+					"a": {
+						LastWouldRender: now,
+						Error:           errors.New("boom"),
+					},
+				},
+			},
+			&Status{
+				Ready: true,
+				Templates: []*TemplateStatus{
+					{
+						ID:                    "a",
+						Sources:               []string{"/tmp/a.tpl"},
+						Destinations:          []string{"/tmp/a"},
+						Rendered:              true,
+						LastWouldRender:       &now,
+						UsedDependencies:      []string{},
+						MissingDependencies:   []string{},
+						StaleDependencies:     []string{},
+						UnwatchedDependencies: []string{},
+						Error:                 "boom",
+					},
+				},
+				Dependencies: []*DependencyStatus{},
+			},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("%d_%s", i, tc.name), func(t *testing.T) {
+			act := Build(tc.src)
+			if !reflect.DeepEqual(tc.exp, act) {
+				expJSON, _ := json.MarshalIndent(tc.exp, "", "  ")
+				actJSON, _ := json.MarshalIndent(act, "", "  ")
+				t.Errorf("\nexp: %s\nact: %s", expJSON, actJSON)
+			}
+		})
+	}
+}
+
+func TestSetup(t *testing.T) {
+	src := &testSource{
+		mapping: map[string][]*config.TemplateConfig{
+			"a": {{Destination: config.String("/tmp/a")}},
+		},
+		events: map[string]*manager.RenderEvent{},
+	}
+
+	s, err := Setup(&Config{
+		Address: "127.0.0.1:0",
+		Source:  src,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	get := func(path string) int {
+		resp, err := http.Get("http://" + s.Addr().String() + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := get(HealthPath); code != http.StatusOK {
+		t.Errorf("health: expected %d to be %d", code, http.StatusOK)
+	}
+	if code := get(ReadyPath); code != http.StatusServiceUnavailable {
+		t.Errorf("ready: expected %d to be %d", code, http.StatusServiceUnavailable)
+	}
+
+	src.events["a"] = &manager.RenderEvent{LastWouldRender: time.Now()}
+	if code := get(ReadyPath); code != http.StatusOK {
+		t.Errorf("ready: expected %d to be %d", code, http.StatusOK)
+	}
+
+	resp, err := http.Get("http://" + s.Addr().String() + StatusPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected %q to be %q", ct, "application/json")
+	}
+
+	var st Status
+	if err := json.NewDecoder(resp.Body).Decode(&st); err != nil {
+		t.Fatal(err)
+	}
+	if !st.Ready || len(st.Templates) != 1 || !st.Templates[0].Rendered {
+		t.Errorf("unexpected status: %#v", st)
+	}
+}
+
+func TestStop(t *testing.T) {
+	s, err := Setup(&Config{
+		Address: "127.0.0.1:0",
+		Source:  &testSource{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := s.Addr().String()
+	s.Stop()
+
+	if _, err := http.Get("http://" + addr + HealthPath); err == nil {
+		t.Errorf("expected listener to be closed")
+	}
+
+	var nilServer *Server
+	nilServer.Stop()
+}
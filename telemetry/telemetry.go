@@ -0,0 +1,115 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	metrics "github.com/hashicorp/go-metrics"
+	prometheussink "github.com/hashicorp/go-metrics/prometheus"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// MetricsPath is the HTTP path metrics are served on.
+const MetricsPath = "/metrics"
+
+// shutdownTimeout is the maximum amount of time to wait for in-flight scrapes
+// to complete when stopping the listener.
+const shutdownTimeout = 5 * time.Second
+
+// Config is the configuration for the metrics listener.
+type Config struct {
+	// Address is the host:port the listener binds to.
+	Address string
+
+	// Prefix is prepended to every metric name.
+	Prefix string
+}
+
+// Telemetry is a running metrics listener. The zero value is not usable; use
+// Setup to create one.
+type Telemetry struct {
+	listener net.Listener
+	server   *http.Server
+}
+
+// Setup configures the global metrics sink to a Prometheus registry and starts
+// an HTTP listener serving the registry. Until Setup is called, all metrics
+// emitted by Consul Template are discarded, so embedding applications that do
+// not call it are unaffected.
+func Setup(c *Config) (*Telemetry, error) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	sink, err := prometheussink.NewPrometheusSinkFrom(prometheussink.PrometheusOpts{
+		Registerer: registry,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("telemetry: %w", err)
+	}
+
+	conf := metrics.DefaultConfig(c.Prefix)
+	conf.EnableHostname = false
+	conf.EnableRuntimeMetrics = false
+	if _, err := metrics.NewGlobal(conf, sink); err != nil {
+		return nil, fmt.Errorf("telemetry: %w", err)
+	}
+
+	ln, err := net.Listen("tcp", c.Address)
+	if err != nil {
+		metrics.Shutdown()
+		return nil, fmt.Errorf("telemetry: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(MetricsPath, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	t := &Telemetry{
+		listener: ln,
+		server: &http.Server{
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+	}
+
+	log.Printf("[INFO] (telemetry) serving metrics on http://%s%s", ln.Addr(), MetricsPath)
+	go func() {
+		if err := t.server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("[ERR] (telemetry) metrics listener stopped: %s", err)
+		}
+	}()
+
+	return t, nil
+}
+
+// Addr returns the address the listener is bound to.
+func (t *Telemetry) Addr() net.Addr {
+	return t.listener.Addr()
+}
+
+// Stop shuts down the listener and resets the global metrics sink so that
+// further metrics are discarded.
+func (t *Telemetry) Stop() {
+	if t == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := t.server.Shutdown(ctx); err != nil {
+		log.Printf("[WARN] (telemetry) error stopping metrics listener: %s", err)
+	}
+	metrics.Shutdown()
+}
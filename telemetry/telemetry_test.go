@@ -0,0 +1,67 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package telemetry
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	metrics "github.com/hashicorp/go-metrics"
+)
+
+func TestSetup(t *testing.T) {
+	tel, err := Setup(&Config{
+		Address: "127.0.0.1:0",
+		Prefix:  "test",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tel.Stop()
+
+	metrics.IncrCounterWithLabels([]string{"runner", "template", "did_render"}, 1,
+		[]metrics.Label{{Name: "template_id", Value: "abcd"}})
+
+	resp, err := http.Get("http://" + tel.Addr().String() + MetricsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, exp := range []string{
+		`test_runner_template_did_render{template_id="abcd"} 1`,
+		"go_goroutines",
+	} {
+		if !strings.Contains(string(body), exp) {
+			t.Errorf("expected metrics output to contain %q, got:\n%s", exp, body)
+		}
+	}
+}
+
+func TestStop(t *testing.T) {
+	tel, err := Setup(&Config{
+		Address: "127.0.0.1:0",
+		Prefix:  "test",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := tel.Addr().String()
+	tel.Stop()
+
+	if _, err := http.Get("http://" + addr + MetricsPath); err == nil {
+		t.Fatal("expected listener to be closed")
+	}
+
+	// Stopping a nil listener is a no-op.
+	var nilTel *Telemetry
+	nilTel.Stop()
+}
@@ -30,6 +30,7 @@ import (
 	spewLib "github.com/davecgh/go-spew/spew"
 	"github.com/hashicorp/consul/api"
 	socktmpl "github.com/hashicorp/go-sockaddr/template"
+	"github.com/hashicorp/serf/coordinate"
 	"github.com/pkg/errors"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -97,6 +98,107 @@ func partitionsFunc(b *Brain, used, missing *dep.Set) func() ([]*dep.Partition,
 	}
 }
 
+// configEntryFunc returns or accumulates config entry dependencies.
+func configEntryFunc(b *Brain, used, missing *dep.Set) func(string, string) (api.ConfigEntry, error) {
+	return func(kind, s string) (api.ConfigEntry, error) {
+		if len(s) == 0 {
+			return nil, nil
+		}
+
+		d, err := dep.NewConfigEntryQuery(kind, s)
+		if err != nil {
+			return nil, err
+		}
+
+		used.Add(d)
+
+		if value, ok := b.Recall(d); ok {
+			if value == nil {
+				return nil, nil
+			}
+			return value.(api.ConfigEntry), nil
+		}
+
+		missing.Add(d)
+
+		return nil, nil
+	}
+}
+
+// configEntriesFunc returns or accumulates config entries dependencies.
+func configEntriesFunc(b *Brain, used, missing *dep.Set) func(string, ...string) ([]api.ConfigEntry, error) {
+	return func(kind string, s ...string) ([]api.ConfigEntry, error) {
+		result := []api.ConfigEntry{}
+
+		if len(s) > 1 {
+			return result, errors.New("configEntries: wrong number of arguments, expected 1 or 2")
+		}
+
+		d, err := dep.NewConfigEntriesQuery(kind, strings.Join(s, ""))
+		if err != nil {
+			return nil, err
+		}
+
+		used.Add(d)
+
+		if value, ok := b.Recall(d); ok {
+			return value.([]api.ConfigEntry), nil
+		}
+
+		missing.Add(d)
+
+		return result, nil
+	}
+}
+
+// intentionsFunc returns or accumulates intentions dependencies.
+func intentionsFunc(b *Brain, used, missing *dep.Set) func(...string) ([]*dep.Intention, error) {
+	return func(s ...string) ([]*dep.Intention, error) {
+		result := []*dep.Intention{}
+
+		if len(s) > 1 {
+			return result, errors.New("intentions: wrong number of arguments, expected 0 or 1")
+		}
+
+		d, err := dep.NewListIntentionsQuery(strings.Join(s, ""))
+		if err != nil {
+			return result, err
+		}
+
+		used.Add(d)
+
+		if value, ok := b.Recall(d); ok {
+			return value.([]*dep.Intention), nil
+		}
+
+		missing.Add(d)
+
+		return result, nil
+	}
+}
+
+// eventsFunc returns or accumulates user event dependencies.
+func eventsFunc(b *Brain, used, missing *dep.Set) func(...string) ([]*dep.UserEvent, error) {
+	return func(s ...string) ([]*dep.UserEvent, error) {
+		result := []*dep.UserEvent{}
+
+		d, err := dep.NewEventListQuery(strings.Join(s, ""))
+		if err != nil {
+			return nil, err
+		}
+
+		used.Add(d)
+
+		if value, ok := b.Recall(d); ok {
+			return value.([]*dep.UserEvent), nil
+		}
+
+		missing.Add(d)
+
+		return result, nil
+	}
+}
+
 // exportedServicesFunc returns or accumulates partition dependencies.
 func exportedServicesFunc(b *Brain, used, missing *dep.Set) func(...string) ([]dep.ExportedService, error) {
 	return func(s ...string) ([]dep.ExportedService, error) {
@@ -294,6 +396,71 @@ func keyFunc(b *Brain, used, missing *dep.Set) func(string) (string, error) {
 	}
 }
 
+// keyPairFunc returns or accumulates key dependencies, returning the full
+// KeyPair of the key or nil if it does not exist.
+func keyPairFunc(b *Brain, used, missing *dep.Set) func(string) (*dep.KeyPair, error) {
+	return func(s string) (*dep.KeyPair, error) {
+		if len(s) == 0 {
+			return nil, nil
+		}
+
+		d, err := dep.NewKVGetQuery(s)
+		if err != nil {
+			return nil, err
+		}
+		d.EnableKeyPair()
+
+		used.Add(d)
+
+		if value, ok := b.Recall(d); ok {
+			if value == nil {
+				return nil, nil
+			}
+			return value.(*dep.KeyPair), nil
+		}
+
+		missing.Add(d)
+
+		return nil, nil
+	}
+}
+
+// keyJSONFunc returns or accumulates key dependencies and decodes the value of
+// the key as JSON.
+func keyJSONFunc(b *Brain, used, missing *dep.Set) func(string) (interface{}, error) {
+	key := keyFunc(b, used, missing)
+	return func(s string) (interface{}, error) {
+		value, err := key(s)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := parseJSON(value)
+		if err != nil {
+			return nil, fmt.Errorf("keyJSON: decoding %q: %w", s, err)
+		}
+		return data, nil
+	}
+}
+
+// keyYAMLFunc returns or accumulates key dependencies and decodes the value of
+// the key as YAML.
+func keyYAMLFunc(b *Brain, used, missing *dep.Set) func(string) (interface{}, error) {
+	key := keyFunc(b, used, missing)
+	return func(s string) (interface{}, error) {
+		value, err := key(s)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := parseYAML(value)
+		if err != nil {
+			return nil, fmt.Errorf("keyYAML: decoding %q: %w", s, err)
+		}
+		return data, nil
+	}
+}
+
 // keyExistsFunc returns true if a key exists, false otherwise.
 func keyExistsFunc(b *Brain, used, missing *dep.Set) func(string) (bool, error) {
 	return func(s string) (bool, error) {
@@ -351,6 +518,30 @@ func safeLsFunc(b *Brain, used, missing *dep.Set) func(string) ([]*dep.KeyPair,
 	return lsFunc(b, used, missing, false)
 }
 
+// lockHolderFunc returns or accumulates lock holder dependencies, returning
+// nil if the key is not locked.
+func lockHolderFunc(b *Brain, used, missing *dep.Set) func(string) (*dep.LockHolder, error) {
+	return func(s string) (*dep.LockHolder, error) {
+		d, err := dep.NewLockHolderQuery(s)
+		if err != nil {
+			return nil, err
+		}
+
+		used.Add(d)
+
+		if value, ok := b.Recall(d); ok {
+			if value == nil {
+				return nil, nil
+			}
+			return value.(*dep.LockHolder), nil
+		}
+
+		missing.Add(d)
+
+		return nil, nil
+	}
+}
+
 // lsFunc returns or accumulates keyPrefix dependencies.
 func lsFunc(b *Brain, used, missing *dep.Set, emptyIsSafe bool) func(string) ([]*dep.KeyPair, error) {
 	return func(s string) ([]*dep.KeyPair, error) {
@@ -397,13 +588,38 @@ func lsFunc(b *Brain, used, missing *dep.Set, emptyIsSafe bool) func(string) ([]
 	}
 }
 
+// membersFunc returns or accumulates agent member dependencies.
+func membersFunc(b *Brain, used, missing *dep.Set) func(...string) ([]*dep.AgentMember, error) {
+	return func(s ...string) ([]*dep.AgentMember, error) {
+		result := []*dep.AgentMember{}
+
+		d, err := dep.NewAgentMembersQuery(strings.Join(s, ""))
+		if err != nil {
+			return nil, err
+		}
+
+		used.Add(d)
+
+		if value, ok := b.Recall(d); ok {
+			return value.([]*dep.AgentMember), nil
+		}
+
+		missing.Add(d)
+
+		return result, nil
+	}
+}
+
 // nodeFunc returns or accumulates catalog node dependency.
 func nodeFunc(b *Brain, used, missing *dep.Set) func(...string) (interface{}, error) {
 	return func(s ...string) (interface{}, error) {
+		s, filter := filterArg(s)
+
 		d, err := dep.NewCatalogNodeQuery(strings.Join(s, ""))
 		if err != nil {
 			return nil, err
 		}
+		d.SetFilter(filter)
 
 		used.Add(d)
 
@@ -417,15 +633,33 @@ func nodeFunc(b *Brain, used, missing *dep.Set) func(...string) (interface{}, er
 	}
 }
 
+// filterArg removes a "filter=<expression>" argument from the arguments of a
+// template function, returning the other arguments and the Consul filter
+// expression.
+func filterArg(s []string) ([]string, string) {
+	var filter string
+	args := make([]string, 0, len(s))
+	for _, arg := range s {
+		if expr, ok := strings.CutPrefix(arg, dep.QueryFilter+"="); ok {
+			filter = expr
+			continue
+		}
+		args = append(args, arg)
+	}
+	return args, filter
+}
+
 // nodesFunc returns or accumulates catalog node dependencies.
 func nodesFunc(b *Brain, used, missing *dep.Set) func(...string) ([]*dep.Node, error) {
 	return func(s ...string) ([]*dep.Node, error) {
 		result := []*dep.Node{}
+		s, filter := filterArg(s)
 
 		d, err := dep.NewCatalogNodesQuery(strings.Join(s, ""))
 		if err != nil {
 			return nil, err
 		}
+		d.SetFilter(filter)
 
 		used.Add(d)
 
@@ -439,6 +673,51 @@ func nodesFunc(b *Brain, used, missing *dep.Set) func(...string) ([]*dep.Node, e
 	}
 }
 
+// nodeCoordinatesFunc returns or accumulates node coordinate dependencies.
+func nodeCoordinatesFunc(b *Brain, used, missing *dep.Set) func(...string) ([]*dep.NodeCoordinate, error) {
+	return func(s ...string) ([]*dep.NodeCoordinate, error) {
+		result := []*dep.NodeCoordinate{}
+
+		d, err := dep.NewNodeCoordinatesQuery(strings.Join(s, ""))
+		if err != nil {
+			return nil, err
+		}
+
+		used.Add(d)
+
+		if value, ok := b.Recall(d); ok {
+			return value.([]*dep.NodeCoordinate), nil
+		}
+
+		missing.Add(d)
+
+		return result, nil
+	}
+}
+
+// datacenterCoordinatesFunc returns or accumulates datacenter coordinate
+// dependencies.
+func datacenterCoordinatesFunc(b *Brain, used, missing *dep.Set) func() ([]*dep.DatacenterCoordinates, error) {
+	return func() ([]*dep.DatacenterCoordinates, error) {
+		result := []*dep.DatacenterCoordinates{}
+
+		d, err := dep.NewDatacenterCoordinatesQuery()
+		if err != nil {
+			return nil, err
+		}
+
+		used.Add(d)
+
+		if value, ok := b.Recall(d); ok {
+			return value.([]*dep.DatacenterCoordinates), nil
+		}
+
+		missing.Add(d)
+
+		return result, nil
+	}
+}
+
 // peeringsFunc returns or accumulates peerings.
 func peeringsFunc(b *Brain, used, missing *dep.Set) func(...string) ([]*dep.Peering, error) {
 	return func(s ...string) ([]*dep.Peering, error) {
@@ -498,6 +777,111 @@ func pkiCertFunc(b *Brain, used, missing *dep.Set, destPath string) func(...stri
 	}
 }
 
+// pkiSignFunc returns a PKI cert signed by Vault for a locally generated key
+func pkiSignFunc(b *Brain, used, missing *dep.Set, destPath string) func(...string) (interface{}, error) {
+	return func(s ...string) (interface{}, error) {
+		if len(s) == 0 {
+			return nil, nil
+		}
+
+		path, rest := s[0], s[1:]
+		data := make(map[string]interface{})
+		for _, str := range rest {
+			if len(str) == 0 {
+				continue
+			}
+			parts := strings.SplitN(str, "=", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("not k=v pair %q", str)
+			}
+
+			k, v := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+			data[k] = v
+		}
+
+		d, err := dep.NewVaultPKISignQuery(path, destPath, data)
+		if err != nil {
+			return nil, err
+		}
+
+		used.Add(d)
+		if value, ok := b.Recall(d); ok {
+			return value, nil
+		}
+		missing.Add(d)
+
+		return nil, nil
+	}
+}
+
+// transitQuery returns or accumulates the Vault transit dependency for the
+// operation with the key at keyPath on the input, returning its result or nil
+// if it is not known yet.
+func transitQuery(b *Brain, used, missing *dep.Set, op, keyPath, input string, rest []string) (interface{}, error) {
+	data := make(map[string]interface{})
+	for _, str := range rest {
+		if len(str) == 0 {
+			continue
+		}
+		parts := strings.SplitN(str, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("not k=v pair %q", str)
+		}
+
+		k, v := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		data[k] = v
+	}
+
+	d, err := dep.NewVaultTransitQuery(op, keyPath, input, data)
+	if err != nil {
+		return nil, err
+	}
+
+	used.Add(d)
+	if value, ok := b.Recall(d); ok {
+		return value, nil
+	}
+	missing.Add(d)
+
+	return nil, nil
+}
+
+// transitEncryptFunc returns the input encrypted with a Vault transit key.
+func transitEncryptFunc(b *Brain, used, missing *dep.Set) func(string, string, ...string) (dep.TransitEncrypted, error) {
+	return func(keyPath, input string, rest ...string) (dep.TransitEncrypted, error) {
+		value, err := transitQuery(b, used, missing, dep.TransitEncrypt, keyPath, input, rest)
+		result, _ := value.(dep.TransitEncrypted)
+		return result, err
+	}
+}
+
+// transitDecryptFunc returns the ciphertext decrypted with a Vault transit key.
+func transitDecryptFunc(b *Brain, used, missing *dep.Set) func(string, string, ...string) (dep.TransitDecrypted, error) {
+	return func(keyPath, input string, rest ...string) (dep.TransitDecrypted, error) {
+		value, err := transitQuery(b, used, missing, dep.TransitDecrypt, keyPath, input, rest)
+		result, _ := value.(dep.TransitDecrypted)
+		return result, err
+	}
+}
+
+// transitSignFunc returns the signature of the input by a Vault transit key.
+func transitSignFunc(b *Brain, used, missing *dep.Set) func(string, string, ...string) (dep.TransitSigned, error) {
+	return func(keyPath, input string, rest ...string) (dep.TransitSigned, error) {
+		value, err := transitQuery(b, used, missing, dep.TransitSign, keyPath, input, rest)
+		result, _ := value.(dep.TransitSigned)
+		return result, err
+	}
+}
+
+// transitHMACFunc returns the HMAC of the input with a Vault transit key.
+func transitHMACFunc(b *Brain, used, missing *dep.Set) func(string, string, ...string) (dep.TransitHMACed, error) {
+	return func(keyPath, input string, rest ...string) (dep.TransitHMACed, error) {
+		value, err := transitQuery(b, used, missing, dep.TransitHMAC, keyPath, input, rest)
+		result, _ := value.(dep.TransitHMACed)
+		return result, err
+	}
+}
+
 // secretFunc returns or accumulates secret dependencies from Vault.
 func secretFunc(b *Brain, used, missing *dep.Set) func(...string) (interface{}, error) {
 	return func(s ...string) (interface{}, error) {
@@ -573,8 +957,33 @@ func secretsFunc(b *Brain, used, missing *dep.Set) func(string) ([]string, error
 	}
 }
 
-// byMeta returns Services grouped by one or many ServiceMeta fields.
-func byMeta(meta string, services []*dep.HealthService) (groups map[string][]*dep.HealthService, err error) {
+// byMeta returns a map of the given health services or agent members grouped
+// by the values of the given comma separated list of service meta or member
+// tag keys. A key with the "|int" suffix is grouped by its zero padded number.
+func byMeta(meta string, in interface{}) (interface{}, error) {
+	switch typed := in.(type) {
+	case []*dep.HealthService:
+		groups, err := groupByMeta(meta, typed, func(s *dep.HealthService) map[string]string {
+			return s.ServiceMeta
+		})
+		if err != nil {
+			return nil, err
+		}
+		return groups, nil
+	case []*dep.AgentMember:
+		groups, err := groupByMeta(meta, typed, func(m *dep.AgentMember) map[string]string {
+			return m.Tags
+		})
+		if err != nil {
+			return nil, err
+		}
+		return groups, nil
+	default:
+		return nil, fmt.Errorf("byMeta: wrong argument type %T", in)
+	}
+}
+
+func groupByMeta[T any](meta string, items []T, metaOf func(T) map[string]string) (groups map[string][]T, err error) {
 	re := regexp.MustCompile("[^a-zA-Z0-9_-]")
 	normalize := func(x string) string {
 		return re.ReplaceAllString(x, "_")
@@ -592,10 +1001,10 @@ func byMeta(meta string, services []*dep.HealthService) (groups map[string][]*de
 
 	metas := strings.Split(meta, ",")
 
-	groups = make(map[string][]*dep.HealthService)
+	groups = make(map[string][]T)
 
-	for _, s := range services {
-		sm := s.ServiceMeta
+	for _, s := range items {
+		sm := metaOf(s)
 		keyParts := []string{}
 		for _, meta := range metas {
 			value := getOrDefault(sm, meta)
@@ -618,8 +1027,14 @@ func byMeta(meta string, services []*dep.HealthService) (groups map[string][]*de
 
 // serviceFunc returns or accumulates health service dependencies.
 func serviceFunc(b *Brain, used, missing *dep.Set) func(...string) ([]*dep.HealthService, error) {
+	allDCs := serviceAllDCsFunc(b, used, missing)
 	return func(s ...string) ([]*dep.HealthService, error) {
+		if len(s) > 0 && dep.IsAllDCsServiceQuery(s[0]) {
+			return allDCs(s...)
+		}
+
 		result := []*dep.HealthService{}
+		s, filter := filterArg(s)
 
 		if len(s) == 0 || s[0] == "" {
 			return result, nil
@@ -629,6 +1044,118 @@ func serviceFunc(b *Brain, used, missing *dep.Set) func(...string) ([]*dep.Healt
 		if err != nil {
 			return nil, err
 		}
+		d.SetFilter(filter)
+
+		used.Add(d)
+
+		if value, ok := b.Recall(d); ok {
+			return value.([]*dep.HealthService), nil
+		}
+
+		missing.Add(d)
+
+		return result, nil
+	}
+}
+
+// serviceAllDCsFunc returns or accumulates health service dependencies across
+// all datacenters.
+func serviceAllDCsFunc(b *Brain, used, missing *dep.Set) func(...string) ([]*dep.HealthService, error) {
+	return func(s ...string) ([]*dep.HealthService, error) {
+		result := []*dep.HealthService{}
+		s, filter := filterArg(s)
+
+		if len(s) == 0 || s[0] == "" {
+			return result, nil
+		}
+
+		d, err := dep.NewHealthServiceAllDCsQuery(strings.Join(s, "|"))
+		if err != nil {
+			return nil, err
+		}
+		d.SetFilter(filter)
+
+		used.Add(d)
+
+		if value, ok := b.Recall(d); ok {
+			return value.([]*dep.HealthService), nil
+		}
+
+		missing.Add(d)
+
+		return result, nil
+	}
+}
+
+// checksFunc returns or accumulates health state dependencies.
+func checksFunc(b *Brain, used, missing *dep.Set) func(...string) (api.HealthChecks, error) {
+	return func(s ...string) (api.HealthChecks, error) {
+		result := api.HealthChecks{}
+		s, filter := filterArg(s)
+
+		if len(s) == 0 || s[0] == "" {
+			return result, nil
+		}
+
+		d, err := dep.NewHealthStateQuery(strings.Join(s, ""))
+		if err != nil {
+			return nil, err
+		}
+		d.SetFilter(filter)
+
+		used.Add(d)
+
+		if value, ok := b.Recall(d); ok {
+			return value.(api.HealthChecks), nil
+		}
+
+		missing.Add(d)
+
+		return result, nil
+	}
+}
+
+// nodeChecksFunc returns or accumulates health node dependencies.
+func nodeChecksFunc(b *Brain, used, missing *dep.Set) func(...string) (api.HealthChecks, error) {
+	return func(s ...string) (api.HealthChecks, error) {
+		result := api.HealthChecks{}
+		s, filter := filterArg(s)
+
+		if len(s) == 0 || s[0] == "" {
+			return result, nil
+		}
+
+		d, err := dep.NewHealthNodeQuery(strings.Join(s, "|"))
+		if err != nil {
+			return nil, err
+		}
+		d.SetFilter(filter)
+
+		used.Add(d)
+
+		if value, ok := b.Recall(d); ok {
+			return value.(api.HealthChecks), nil
+		}
+
+		missing.Add(d)
+
+		return result, nil
+	}
+}
+
+// preparedQueryFunc returns or accumulates prepared query dependencies.
+func preparedQueryFunc(b *Brain, used, missing *dep.Set) func(string) ([]*dep.HealthService, error) {
+	return func(s string) ([]*dep.HealthService, error) {
+		result := []*dep.HealthService{}
+
+		if len(s) == 0 {
+			return result, nil
+		}
+
+		d, err := dep.NewPreparedQueryExecuteQuery(s)
+		if err != nil {
+			return nil, err
+		}
 
 		used.Add(d)
 
@@ -646,11 +1173,13 @@ func serviceFunc(b *Brain, used, missing *dep.Set) func(...string) ([]*dep.Healt
 func servicesFunc(b *Brain, used, missing *dep.Set) func(...string) ([]*dep.CatalogSnippet, error) {
 	return func(s ...string) ([]*dep.CatalogSnippet, error) {
 		result := []*dep.CatalogSnippet{}
+		s, filter := filterArg(s)
 
 		d, err := dep.NewCatalogServicesQuery(strings.Join(s, ""))
 		if err != nil {
 			return nil, err
 		}
+		d.SetFilter(filter)
 
 		used.Add(d)
 
@@ -668,6 +1197,7 @@ func servicesFunc(b *Brain, used, missing *dep.Set) func(...string) ([]*dep.Cata
 func connectFunc(b *Brain, used, missing *dep.Set) func(...string) ([]*dep.HealthService, error) {
 	return func(s ...string) ([]*dep.HealthService, error) {
 		result := []*dep.HealthService{}
+		s, filter := filterArg(s)
 
 		if len(s) == 0 || s[0] == "" {
 			return result, nil
@@ -677,6 +1207,7 @@ func connectFunc(b *Brain, used, missing *dep.Set) func(...string) ([]*dep.Healt
 		if err != nil {
 			return nil, err
 		}
+		d.SetFilter(filter)
 
 		used.Add(d)
 
@@ -1850,6 +2381,45 @@ func sockaddr(args ...string) (string, error) {
 	return k, nil
 }
 
+// rtt returns the estimated round trip time between two network coordinates,
+// which are either a *dependency.NodeCoordinate or a *coordinate.Coordinate.
+func rtt(a, b interface{}) (time.Duration, error) {
+	toCoord := func(v interface{}) (*coordinate.Coordinate, error) {
+		switch c := v.(type) {
+		case *dep.NodeCoordinate:
+			if c != nil {
+				return c.Coord, nil
+			}
+		case *coordinate.Coordinate:
+			return c, nil
+		default:
+			return nil, fmt.Errorf("rtt: unsupported coordinate type %T", v)
+		}
+		return nil, nil
+	}
+
+	ca, err := toCoord(a)
+	if err != nil {
+		return 0, err
+	}
+	cb, err := toCoord(b)
+	if err != nil {
+		return 0, err
+	}
+
+	if ca == nil || cb == nil {
+		return 0, fmt.Errorf("rtt: missing coordinate")
+	}
+	if !ca.IsValid() || !cb.IsValid() {
+		return 0, fmt.Errorf("rtt: invalid coordinate")
+	}
+	if !ca.IsCompatibleWith(cb) {
+		return 0, fmt.Errorf("rtt: coordinates have different dimensions")
+	}
+
+	return ca.DistanceTo(cb), nil
+}
+
 // sha256Hex return the sha256 hex of a string
 func sha256Hex(item string) (string, error) {
 	h := sha256.New()
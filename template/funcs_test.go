@@ -10,7 +10,9 @@ import (
 	"reflect"
 	"runtime"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/serf/coordinate"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -198,11 +200,12 @@ func Test_byMeta(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotGroups, err := byMeta(tt.args.meta, tt.args.services)
+			got, err := byMeta(tt.args.meta, tt.args.services)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("byMeta() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			gotGroups, _ := got.(map[string][]*dep.HealthService)
 
 			onlyIDs := func(groups map[string][]*dep.HealthService) (ids map[string]map[string]int) {
 				ids = make(map[string]map[string]int)
@@ -224,6 +227,23 @@ func Test_byMeta(t *testing.T) {
 	}
 }
 
+func Test_byMeta_members(t *testing.T) {
+	server1 := &dep.AgentMember{Name: "server1", Tags: map[string]string{"role": "consul", "dc": "dc1"}}
+	server2 := &dep.AgentMember{Name: "server2", Tags: map[string]string{"role": "consul", "dc": "dc2"}}
+	client := &dep.AgentMember{Name: "client", Tags: map[string]string{"role": "node", "dc": "dc1"}}
+
+	got, err := byMeta("role,dc", []*dep.AgentMember{server1, server2, client})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]*dep.AgentMember{
+		"consul_dc1": {server1},
+		"consul_dc2": {server2},
+		"node_dc1":   {client},
+	}, got)
+
+	_, err = byMeta("role", []string{"server1"})
+	assert.Error(t, err)
+}
+
 func Test_byPort(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -315,6 +335,64 @@ func Test_byPort(t *testing.T) {
 	}
 }
 
+func Test_rtt(t *testing.T) {
+	newCoord := func(x, height float64) *coordinate.Coordinate {
+		c := coordinate.NewCoordinate(coordinate.DefaultConfig())
+		c.Vec[0] = x
+		c.Height = height
+		return c
+	}
+
+	tests := []struct {
+		name    string
+		a       interface{}
+		b       interface{}
+		want    time.Duration
+		wantErr bool
+	}{
+		{
+			name: "coordinates",
+			a:    newCoord(0.010, 0.001),
+			b:    newCoord(0, 0.001),
+			want: 12 * time.Millisecond,
+		},
+		{
+			name: "node_coordinates",
+			a:    &dep.NodeCoordinate{Node: "a", Coord: newCoord(0.020, 0.001)},
+			b:    &dep.NodeCoordinate{Node: "b", Coord: newCoord(0.005, 0.002)},
+			want: 18 * time.Millisecond,
+		},
+		{
+			name:    "missing",
+			a:       &dep.NodeCoordinate{Node: "a"},
+			b:       newCoord(0, 0.001),
+			wantErr: true,
+		},
+		{
+			name:    "incompatible",
+			a:       newCoord(0, 0.001),
+			b:       &coordinate.Coordinate{Vec: []float64{0, 0}, Height: 0.001},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported",
+			a:       "node1",
+			b:       newCoord(0, 0.001),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := rtt(tt.a, tt.b)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("rtt() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.InDelta(t, tt.want, got, float64(time.Microsecond))
+		})
+	}
+}
+
 func Test_sha256Hex(t *testing.T) {
 	type args struct {
 		item string
@@ -343,29 +343,49 @@ func funcMap(i *funcMapInput) template.FuncMap {
 
 	r := template.FuncMap{
 		// API functions
-		"datacenters":      datacentersFunc(i.brain, i.used, i.missing),
-		"exportedServices": exportedServicesFunc(i.brain, i.used, i.missing),
-		"importedServices": importedServicesFunc(i.brain, i.used, i.missing),
-		"file":             fileFunc(i.brain, i.used, i.missing, i.sandboxPath),
-		"key":              keyFunc(i.brain, i.used, i.missing),
-		"keyExists":        keyExistsFunc(i.brain, i.used, i.missing),
-		"keyOrDefault":     keyWithDefaultFunc(i.brain, i.used, i.missing),
-		"ls":               lsFunc(i.brain, i.used, i.missing, true),
-		"safeLs":           safeLsFunc(i.brain, i.used, i.missing),
-		"node":             nodeFunc(i.brain, i.used, i.missing),
-		"nodes":            nodesFunc(i.brain, i.used, i.missing),
-		"partitions":       partitionsFunc(i.brain, i.used, i.missing),
-		"peerings":         peeringsFunc(i.brain, i.used, i.missing),
-		"secret":           secretFunc(i.brain, i.used, i.missing),
-		"secrets":          secretsFunc(i.brain, i.used, i.missing),
-		"service":          serviceFunc(i.brain, i.used, i.missing),
-		"connect":          connectFunc(i.brain, i.used, i.missing),
-		"services":         servicesFunc(i.brain, i.used, i.missing),
-		"tree":             treeFunc(i.brain, i.used, i.missing, true),
-		"safeTree":         safeTreeFunc(i.brain, i.used, i.missing),
-		"caRoots":          connectCARootsFunc(i.brain, i.used, i.missing),
-		"caLeaf":           connectLeafFunc(i.brain, i.used, i.missing),
-		"pkiCert":          pkiCertFunc(i.brain, i.used, i.missing, i.destination),
+		"configEntry":           configEntryFunc(i.brain, i.used, i.missing),
+		"configEntries":         configEntriesFunc(i.brain, i.used, i.missing),
+		"checks":                checksFunc(i.brain, i.used, i.missing),
+		"datacenters":           datacentersFunc(i.brain, i.used, i.missing),
+		"datacenterCoordinates": datacenterCoordinatesFunc(i.brain, i.used, i.missing),
+		"events":                eventsFunc(i.brain, i.used, i.missing),
+		"exportedServices":      exportedServicesFunc(i.brain, i.used, i.missing),
+		"importedServices":      importedServicesFunc(i.brain, i.used, i.missing),
+		"file":                  fileFunc(i.brain, i.used, i.missing, i.sandboxPath),
+		"intentions":            intentionsFunc(i.brain, i.used, i.missing),
+		"key":                   keyFunc(i.brain, i.used, i.missing),
+		"keyExists":             keyExistsFunc(i.brain, i.used, i.missing),
+		"keyOrDefault":          keyWithDefaultFunc(i.brain, i.used, i.missing),
+		"keyPair":               keyPairFunc(i.brain, i.used, i.missing),
+		"keyJSON":               keyJSONFunc(i.brain, i.used, i.missing),
+		"keyYAML":               keyYAMLFunc(i.brain, i.used, i.missing),
+		"lockHolder":            lockHolderFunc(i.brain, i.used, i.missing),
+		"ls":                    lsFunc(i.brain, i.used, i.missing, true),
+		"safeLs":                safeLsFunc(i.brain, i.used, i.missing),
+		"members":               membersFunc(i.brain, i.used, i.missing),
+		"node":                  nodeFunc(i.brain, i.used, i.missing),
+		"nodes":                 nodesFunc(i.brain, i.used, i.missing),
+		"nodeChecks":            nodeChecksFunc(i.brain, i.used, i.missing),
+		"nodeCoordinates":       nodeCoordinatesFunc(i.brain, i.used, i.missing),
+		"partitions":            partitionsFunc(i.brain, i.used, i.missing),
+		"peerings":              peeringsFunc(i.brain, i.used, i.missing),
+		"preparedQuery":         preparedQueryFunc(i.brain, i.used, i.missing),
+		"secret":                secretFunc(i.brain, i.used, i.missing),
+		"secrets":               secretsFunc(i.brain, i.used, i.missing),
+		"service":               serviceFunc(i.brain, i.used, i.missing),
+		"connect":               connectFunc(i.brain, i.used, i.missing),
+		"serviceAllDCs":         serviceAllDCsFunc(i.brain, i.used, i.missing),
+		"services":              servicesFunc(i.brain, i.used, i.missing),
+		"tree":                  treeFunc(i.brain, i.used, i.missing, true),
+		"safeTree":              safeTreeFunc(i.brain, i.used, i.missing),
+		"caRoots":               connectCARootsFunc(i.brain, i.used, i.missing),
+		"caLeaf":                connectLeafFunc(i.brain, i.used, i.missing),
+		"pkiCert":               pkiCertFunc(i.brain, i.used, i.missing, i.destination),
+		"pkiSign":               pkiSignFunc(i.brain, i.used, i.missing, i.destination),
+		"transitEncrypt":        transitEncryptFunc(i.brain, i.used, i.missing),
+		"transitDecrypt":        transitDecryptFunc(i.brain, i.used, i.missing),
+		"transitSign":           transitSignFunc(i.brain, i.used, i.missing),
+		"transitHMAC":           transitHMACFunc(i.brain, i.used, i.missing),
 
 		// Nomad Functions.
 		"nomadServices":    nomadServicesFunc(i.brain, i.used, i.missing),
@@ -417,6 +437,7 @@ func funcMap(i *funcMapInput) template.FuncMap {
 		"regexReplaceAll":       regexReplaceAll,
 		"regexMatch":            regexMatch,
 		"replaceAll":            replaceAll,
+		"rtt":                   rtt,
 		"sha256Hex":             sha256Hex,
 		"md5sum":                md5sum,
 		"hmacSHA256Hex":         hmacSHA256Hex,
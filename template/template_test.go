@@ -21,6 +21,7 @@ import (
 
 	dep "github.com/hashicorp/consul-template/dependency"
 	"github.com/hashicorp/consul/api"
+	"github.com/hashicorp/serf/coordinate"
 	"github.com/stretchr/testify/require"
 )
 
@@ -272,6 +273,319 @@ func TestTemplate_Execute(t *testing.T) {
 			"6116e95f2827172aa6ef8b22b883f6a77e966aefc129c6b8228ebd0aac74e98d",
 			false,
 		},
+		{
+			"func_config_entry",
+			&NewTemplateInput{
+				Contents: `{{ with configEntry "service-resolver" "web" }}{{ range $name, $subset := .Subsets }}{{ $name }}={{ $subset.Filter }}{{ end }}{{ end }}`,
+			},
+			&ExecuteInput{
+				Brain: func() *Brain {
+					b := NewBrain()
+					d, err := dep.NewConfigEntryQuery("service-resolver", "web")
+					if err != nil {
+						t.Fatal(err)
+					}
+					b.Remember(d, &api.ServiceResolverConfigEntry{
+						Kind: api.ServiceResolver,
+						Name: "web",
+						Subsets: map[string]api.ServiceResolverSubset{
+							"v1": {Filter: "Service.Meta.version == v1"},
+						},
+					})
+					return b
+				}(),
+			},
+			"v1=Service.Meta.version == v1",
+			false,
+		},
+		{
+			"func_config_entry_missing",
+			&NewTemplateInput{
+				Contents: `{{ with configEntry "service-defaults" "web" }}{{ .Protocol }}{{ else }}none{{ end }}`,
+			},
+			&ExecuteInput{
+				Brain: func() *Brain {
+					b := NewBrain()
+					d, err := dep.NewConfigEntryQuery("service-defaults", "web")
+					if err != nil {
+						t.Fatal(err)
+					}
+					b.Remember(d, nil)
+					return b
+				}(),
+			},
+			"none",
+			false,
+		},
+		{
+			"func_config_entries",
+			&NewTemplateInput{
+				Contents: `{{ range configEntries "service-defaults" "?ns=foo" }}{{ .Name }}={{ .Protocol }},{{ end }}`,
+			},
+			&ExecuteInput{
+				Brain: func() *Brain {
+					b := NewBrain()
+					d, err := dep.NewConfigEntriesQuery("service-defaults", "?ns=foo")
+					if err != nil {
+						t.Fatal(err)
+					}
+					b.Remember(d, []api.ConfigEntry{
+						&api.ServiceConfigEntry{Kind: api.ServiceDefaults, Name: "api", Protocol: "grpc"},
+						&api.ServiceConfigEntry{Kind: api.ServiceDefaults, Name: "web", Protocol: "http"},
+					})
+					return b
+				}(),
+			},
+			"api=grpc,web=http,",
+			false,
+		},
+		{
+			"func_intentions",
+			&NewTemplateInput{
+				Contents: `{{ range intentions "?destination=db" }}{{ .Source.Name }}:{{ .Action }}{{ range .Permissions }}{{ .Action }} {{ .HTTP.PathPrefix }}{{ end }},{{ end }}`,
+			},
+			&ExecuteInput{
+				Brain: func() *Brain {
+					b := NewBrain()
+					d, err := dep.NewListIntentionsQuery("?destination=db")
+					if err != nil {
+						t.Fatal(err)
+					}
+					b.Remember(d, []*dep.Intention{
+						{
+							Source:      dep.IntentionTarget{Name: "web"},
+							Destination: dep.IntentionTarget{Name: "db"},
+							Permissions: []*dep.IntentionPermission{
+								{
+									Action: "allow",
+									HTTP:   &api.IntentionHTTPPermission{PathPrefix: "/v1"},
+								},
+							},
+						},
+						{
+							Source:      dep.IntentionTarget{Name: "*"},
+							Destination: dep.IntentionTarget{Name: "db"},
+							Action:      "deny",
+						},
+					})
+					return b
+				}(),
+			},
+			"web:allow /v1,*:deny,",
+			false,
+		},
+		{
+			"func_checks",
+			&NewTemplateInput{
+				Contents: `{{ range checks "critical@dc1" }}{{ .Node }}/{{ .CheckID }},{{ end }}`,
+			},
+			&ExecuteInput{
+				Brain: func() *Brain {
+					b := NewBrain()
+					d, err := dep.NewHealthStateQuery("critical@dc1")
+					if err != nil {
+						t.Fatal(err)
+					}
+					b.Remember(d, api.HealthChecks{
+						{Node: "node1", CheckID: "_node_maintenance", Status: "critical"},
+						{Node: "node2", CheckID: "service:web", Status: "critical"},
+					})
+					return b
+				}(),
+			},
+			"node1/_node_maintenance,node2/service:web,",
+			false,
+		},
+		{
+			"func_checks_consul_filter",
+			&NewTemplateInput{
+				Contents: `{{ range checks "critical" "filter=ServiceName == web" }}{{ .Node }}/{{ .CheckID }},{{ end }}`,
+			},
+			&ExecuteInput{
+				Brain: func() *Brain {
+					b := NewBrain()
+					d, err := dep.NewHealthStateQuery("critical")
+					if err != nil {
+						t.Fatal(err)
+					}
+					d.SetFilter("ServiceName == web")
+					b.Remember(d, api.HealthChecks{
+						{Node: "node2", CheckID: "service:web", Status: "critical"},
+					})
+					return b
+				}(),
+			},
+			"node2/service:web,",
+			false,
+		},
+		{
+			"func_node_checks",
+			&NewTemplateInput{
+				Contents: `{{ range nodeChecks "node1" "critical,warning" }}{{ .CheckID }}={{ .Status }},{{ end }}`,
+			},
+			&ExecuteInput{
+				Brain: func() *Brain {
+					b := NewBrain()
+					d, err := dep.NewHealthNodeQuery("node1|critical,warning")
+					if err != nil {
+						t.Fatal(err)
+					}
+					b.Remember(d, api.HealthChecks{
+						{Node: "node1", CheckID: "serfHealth", Status: "warning"},
+					})
+					return b
+				}(),
+			},
+			"serfHealth=warning,",
+			false,
+		},
+		{
+			"func_node_checks_consul_filter",
+			&NewTemplateInput{
+				Contents: `{{ range nodeChecks "node1" "critical" "filter=ServiceName == web" }}{{ .CheckID }}={{ .Status }},{{ end }}`,
+			},
+			&ExecuteInput{
+				Brain: func() *Brain {
+					b := NewBrain()
+					d, err := dep.NewHealthNodeQuery("node1|critical")
+					if err != nil {
+						t.Fatal(err)
+					}
+					d.SetFilter("ServiceName == web")
+					b.Remember(d, api.HealthChecks{
+						{Node: "node1", CheckID: "service:web", Status: "critical"},
+					})
+					return b
+				}(),
+			},
+			"service:web=critical,",
+			false,
+		},
+		{
+			"func_nodeCoordinates",
+			&NewTemplateInput{
+				Contents: `{{ $coords := nodeCoordinates "@dc1" }}{{ $local := index $coords 0 }}{{ range $coords }}{{ .Node }}:{{ (rtt $local .).Milliseconds }} {{ end }}`,
+			},
+			&ExecuteInput{
+				Brain: func() *Brain {
+					b := NewBrain()
+					d, err := dep.NewNodeCoordinatesQuery("@dc1")
+					if err != nil {
+						t.Fatal(err)
+					}
+					b.Remember(d, []*dep.NodeCoordinate{
+						{
+							Node:  "node1",
+							Coord: &coordinate.Coordinate{Vec: []float64{0, 0}, Height: 0.001},
+						},
+						{
+							Node:  "node2",
+							Coord: &coordinate.Coordinate{Vec: []float64{0.003, 0.004}, Height: 0.001},
+						},
+					})
+					return b
+				}(),
+			},
+			"node1:2 node2:7 ",
+			false,
+		},
+		{
+			"func_datacenterCoordinates",
+			&NewTemplateInput{
+				Contents: `{{ range datacenterCoordinates }}{{ .Datacenter }}:{{ len .Coordinates }} {{ end }}`,
+			},
+			&ExecuteInput{
+				Brain: func() *Brain {
+					b := NewBrain()
+					d, err := dep.NewDatacenterCoordinatesQuery()
+					if err != nil {
+						t.Fatal(err)
+					}
+					b.Remember(d, []*dep.DatacenterCoordinates{
+						{
+							Datacenter: "dc1",
+							AreaID:     "wan",
+							Coordinates: []*dep.NodeCoordinate{
+								{Node: "server1.dc1"},
+								{Node: "server2.dc1"},
+							},
+						},
+						{
+							Datacenter: "dc2",
+							AreaID:     "wan",
+							Coordinates: []*dep.NodeCoordinate{
+								{Node: "server1.dc2"},
+							},
+						},
+					})
+					return b
+				}(),
+			},
+			"dc1:2 dc2:1 ",
+			false,
+		},
+		{
+			"func_events",
+			&NewTemplateInput{
+				Contents: `{{ range events "deploy" }}{{ .LTime }}:{{ .Payload }} {{ end }}{{ with events "deploy" | sprig_last }}{{ .ID }}{{ end }}`,
+			},
+			&ExecuteInput{
+				Brain: func() *Brain {
+					b := NewBrain()
+					d, err := dep.NewEventListQuery("deploy")
+					if err != nil {
+						t.Fatal(err)
+					}
+					b.Remember(d, []*dep.UserEvent{
+						{
+							ID:      "b54fe110-7af5-cafc-d1fb-afc8ba432b1c",
+							Name:    "deploy",
+							Payload: "v1",
+							LTime:   2,
+						},
+						{
+							ID:      "8e3c0ed2-d6d7-7c3a-6e1b-2b4c1c43b1a3",
+							Name:    "deploy",
+							Payload: "v2",
+							LTime:   5,
+						},
+					})
+					return b
+				}(),
+			},
+			"2:v1 5:v2 8e3c0ed2-d6d7-7c3a-6e1b-2b4c1c43b1a3",
+			false,
+		},
+		{
+			"func_members",
+			&NewTemplateInput{
+				Contents: `{{ range members }}{{ .Name }}:{{ .Status }} {{ end }}{{ range members "wan" }}{{ .Name }} {{ end }}{{ range $role, $members := members | byMeta "role" }}{{ $role }}={{ len $members }} {{ end }}`,
+			},
+			&ExecuteInput{
+				Brain: func() *Brain {
+					b := NewBrain()
+					d, err := dep.NewAgentMembersQuery("")
+					if err != nil {
+						t.Fatal(err)
+					}
+					b.Remember(d, []*dep.AgentMember{
+						{Name: "client1", Status: "alive", Tags: map[string]string{"role": "node"}},
+						{Name: "server1", Status: "alive", Tags: map[string]string{"role": "consul"}},
+						{Name: "server2", Status: "failed", Tags: map[string]string{"role": "consul"}},
+					})
+					d, err = dep.NewAgentMembersQuery("wan")
+					if err != nil {
+						t.Fatal(err)
+					}
+					b.Remember(d, []*dep.AgentMember{
+						{Name: "server1.dc1", Status: "alive"},
+					})
+					return b
+				}(),
+			},
+			"client1:alive server1:alive server2:failed server1.dc1 consul=2 node=1 ",
+			false,
+		},
 		{
 			"func_datacenters",
 			&NewTemplateInput{
@@ -404,6 +718,129 @@ func TestTemplate_Execute(t *testing.T) {
 			"150 200",
 			false,
 		},
+		{
+			"func_keyPair",
+			&NewTemplateInput{
+				Contents: `{{ with keyPair "key" }}{{ .Key }}={{ .Value }} {{ .ModifyIndex }} {{ .Session }}{{ end }}{{ with keyPair "no_key" }}nope{{ end }}`,
+			},
+			&ExecuteInput{
+				Brain: func() *Brain {
+					b := NewBrain()
+					d, err := dep.NewKVGetQuery("key")
+					if err != nil {
+						t.Fatal(err)
+					}
+					d.EnableKeyPair()
+					b.Remember(d, &dep.KeyPair{
+						Path:        "key",
+						Key:         "key",
+						Value:       "5",
+						ModifyIndex: 12,
+						Session:     "abcd",
+					})
+					d, err = dep.NewKVGetQuery("no_key")
+					if err != nil {
+						t.Fatal(err)
+					}
+					d.EnableKeyPair()
+					b.Remember(d, nil)
+					return b
+				}(),
+			},
+			"key=5 12 abcd",
+			false,
+		},
+		{
+			"func_keyJSON",
+			&NewTemplateInput{
+				Contents: `{{ with keyJSON "key" }}{{ .port }} {{ index .tags 0 }}{{ end }}`,
+			},
+			&ExecuteInput{
+				Brain: func() *Brain {
+					b := NewBrain()
+					d, err := dep.NewKVGetQuery("key")
+					if err != nil {
+						t.Fatal(err)
+					}
+					d.EnableBlocking()
+					b.Remember(d, `{"port": 8080, "tags": ["a", "b"]}`)
+					return b
+				}(),
+			},
+			"8080 a",
+			false,
+		},
+		{
+			"func_keyJSON_malformed",
+			&NewTemplateInput{
+				Contents: `{{ keyJSON "key" }}`,
+			},
+			&ExecuteInput{
+				Brain: func() *Brain {
+					b := NewBrain()
+					d, err := dep.NewKVGetQuery("key")
+					if err != nil {
+						t.Fatal(err)
+					}
+					d.EnableBlocking()
+					b.Remember(d, `{"port": `)
+					return b
+				}(),
+			},
+			"",
+			true,
+		},
+		{
+			"func_keyYAML",
+			&NewTemplateInput{
+				Contents: `{{ with keyYAML "key" }}{{ .port }} {{ index .tags 1 }}{{ end }}`,
+			},
+			&ExecuteInput{
+				Brain: func() *Brain {
+					b := NewBrain()
+					d, err := dep.NewKVGetQuery("key")
+					if err != nil {
+						t.Fatal(err)
+					}
+					d.EnableBlocking()
+					b.Remember(d, "port: 8080\ntags:\n  - a\n  - b\n")
+					return b
+				}(),
+			},
+			"8080 b",
+			false,
+		},
+		{
+			"func_lockHolder",
+			&NewTemplateInput{
+				Contents: `{{ with lockHolder "service/leader" }}{{ .Value }} {{ .Node }} {{ .Name }} {{ .Status }}{{ end }}{{ with lockHolder "service/unlocked" }}nope{{ end }}`,
+			},
+			&ExecuteInput{
+				Brain: func() *Brain {
+					b := NewBrain()
+					d, err := dep.NewLockHolderQuery("service/leader")
+					if err != nil {
+						t.Fatal(err)
+					}
+					b.Remember(d, &dep.LockHolder{
+						Key:     "service/leader",
+						Value:   "10.0.0.1:8080",
+						Session: "adf4238a-882b-9ddc-4a9d-5b6758e4159e",
+						Name:    "leader-election",
+						Node:    "node1",
+						Status:  "passing",
+					})
+					d, err = dep.NewLockHolderQuery("service/unlocked")
+					if err != nil {
+						t.Fatal(err)
+					}
+					b.Remember(d, nil)
+					return b
+				}(),
+			},
+			"10.0.0.1:8080 node1 leader-election passing",
+			false,
+		},
 		{
 			"func_ls",
 			&NewTemplateInput{
@@ -797,6 +1234,111 @@ func TestTemplate_Execute(t *testing.T) {
 			"1.2.3.45.6.7.8",
 			false,
 		},
+		{
+			"func_prepared_query",
+			&NewTemplateInput{
+				Contents: `{{ range $tag, $services := preparedQuery "web-failover@dc1" | byTag }}{{ $tag }}:{{ range $services }}{{ .Address }}{{ end }}{{ end }}`,
+			},
+			&ExecuteInput{
+				Brain: func() *Brain {
+					b := NewBrain()
+					d, err := dep.NewPreparedQueryExecuteQuery("web-failover@dc1")
+					if err != nil {
+						t.Fatal(err)
+					}
+					b.Remember(d, []*dep.HealthService{
+						{
+							Node:    "node1",
+							Address: "1.2.3.4",
+							Tags:    []string{"prod"},
+						},
+						{
+							Node:    "node2",
+							Address: "5.6.7.8",
+							Tags:    []string{"prod"},
+						},
+					})
+					return b
+				}(),
+			},
+			"prod:1.2.3.45.6.7.8",
+			false,
+		},
+		{
+			"func_service_consul_filter",
+			&NewTemplateInput{
+				Contents: `{{ range service "webapp" "filter=Service.Meta.version == \"2\"" }}{{ .Address }}{{ end }}`,
+			},
+			&ExecuteInput{
+				Brain: func() *Brain {
+					b := NewBrain()
+					d, err := dep.NewHealthServiceQuery("webapp")
+					if err != nil {
+						t.Fatal(err)
+					}
+					d.SetFilter(`Service.Meta.version == "2"`)
+					b.Remember(d, []*dep.HealthService{
+						{
+							Node:    "node2",
+							Address: "5.6.7.8",
+						},
+					})
+					return b
+				}(),
+			},
+			"5.6.7.8",
+			false,
+		},
+		{
+			"func_nodes_consul_filter",
+			&NewTemplateInput{
+				Contents: `{{ range nodes "@dc1" "filter=Meta.rack == r1" }}{{ .Node }}{{ end }}`,
+			},
+			&ExecuteInput{
+				Brain: func() *Brain {
+					b := NewBrain()
+					d, err := dep.NewCatalogNodesQuery("@dc1")
+					if err != nil {
+						t.Fatal(err)
+					}
+					d.SetFilter("Meta.rack == r1")
+					b.Remember(d, []*dep.Node{
+						{Node: "node1"},
+					})
+					return b
+				}(),
+			},
+			"node1",
+			false,
+		},
+		{
+			"func_serviceAllDCs",
+			&NewTemplateInput{
+				Contents: `{{ range serviceAllDCs "webapp" }}{{ .Datacenter }}/{{ .Address }} {{ end }}{{ range service "webapp@*" }}{{ .Datacenter }} {{ end }}`,
+			},
+			&ExecuteInput{
+				Brain: func() *Brain {
+					b := NewBrain()
+					d, err := dep.NewHealthServiceAllDCsQuery("webapp")
+					if err != nil {
+						t.Fatal(err)
+					}
+					b.Remember(d, []*dep.HealthService{
+						{
+							Address:    "1.2.3.4",
+							Datacenter: "dc1",
+						},
+						{
+							Address:    "5.6.7.8",
+							Datacenter: "dc2",
+						},
+					})
+					return b
+				}(),
+			},
+			"dc1/1.2.3.4 dc2/5.6.7.8 dc1 dc2 ",
+			false,
+		},
 		{
 			"func_services",
 			&NewTemplateInput{
@@ -2108,6 +2650,66 @@ func TestTemplate_Execute(t *testing.T) {
 			testCert,
 			false,
 		},
+		{
+			"func_pkiSign",
+			&NewTemplateInput{
+				Contents:    `{{ with pkiSign "pki/sign/egs-dot-com" "key_type=ec" "key_file=/tmp/egs.key" }}{{.Cert}}{{end}}`,
+				Destination: "/dev/null",
+			},
+			&ExecuteInput{
+				Brain: func() *Brain {
+					b := NewBrain()
+					d, err := dep.NewVaultPKISignQuery("pki/sign/egs-dot-com", "/dev/null",
+						map[string]interface{}{"key_type": "ec", "key_file": "/tmp/egs.key"})
+					if err != nil {
+						t.Fatal(err)
+					}
+					b.Remember(d, dep.PemEncoded{Cert: testCert})
+					return b
+				}(),
+			},
+			testCert,
+			false,
+		},
+		{
+			"func_transitEncrypt",
+			&NewTemplateInput{
+				Contents: `{{ transitEncrypt "transit/my-key" "secret" }} {{ (transitEncrypt "transit/my-key" "secret").KeyVersion }}`,
+			},
+			&ExecuteInput{
+				Brain: func() *Brain {
+					b := NewBrain()
+					d, err := dep.NewVaultTransitQuery(dep.TransitEncrypt, "transit/my-key", "secret", nil)
+					if err != nil {
+						t.Fatal(err)
+					}
+					b.Remember(d, dep.TransitEncrypted{Ciphertext: "vault:v2:abc", KeyVersion: 2})
+					return b
+				}(),
+			},
+			"vault:v2:abc 2",
+			false,
+		},
+		{
+			"func_transitDecrypt",
+			&NewTemplateInput{
+				Contents: `{{ with transitDecrypt "transit/my-key" "vault:v2:abc" "context=Zm9v" }}{{ .Plaintext }}{{ end }}`,
+			},
+			&ExecuteInput{
+				Brain: func() *Brain {
+					b := NewBrain()
+					d, err := dep.NewVaultTransitQuery(dep.TransitDecrypt, "transit/my-key", "vault:v2:abc",
+						map[string]interface{}{"context": "Zm9v"})
+					if err != nil {
+						t.Fatal(err)
+					}
+					b.Remember(d, dep.TransitDecrypted{Plaintext: "secret"})
+					return b
+				}(),
+			},
+			"secret",
+			false,
+		},
 		{
 			"spew_sdump_simple_output",
 			&NewTemplateInput{
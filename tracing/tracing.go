@@ -0,0 +1,137 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/hashicorp/consul-template/version"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	// ExporterOTLP sends spans to an OTLP/HTTP collector.
+	ExporterOTLP = "otlp"
+
+	// ExporterFile writes spans as JSON to a local file, one span per line.
+	ExporterFile = "file"
+)
+
+// shutdownTimeout is the maximum amount of time to wait for queued spans to
+// be exported when stopping.
+const shutdownTimeout = 5 * time.Second
+
+// Config is the configuration for tracing.
+type Config struct {
+	// Exporter is either ExporterOTLP or ExporterFile.
+	Exporter string
+
+	// Endpoint is the host:port of the OTLP/HTTP collector. The standard
+	// OTEL_EXPORTER_OTLP_* environment variables are also honored.
+	Endpoint string
+
+	// Insecure disables TLS when talking to the OTLP collector.
+	Insecure bool
+
+	// FilePath is the path spans are appended to by the file exporter.
+	FilePath string
+
+	// ServiceName is the service.name resource attribute.
+	ServiceName string
+}
+
+// Tracing is a running trace pipeline. The zero value is not usable; use Setup
+// to create one.
+type Tracing struct {
+	provider *sdktrace.TracerProvider
+	file     *os.File
+}
+
+// Setup installs a global OpenTelemetry tracer provider that exports spans as
+// configured. Until Setup is called, the global provider is a no-op, so
+// embedding applications that do not call it are unaffected.
+func Setup(c *Config) (*Tracing, error) {
+	t := &Tracing{}
+
+	var exporter sdktrace.SpanExporter
+	switch c.Exporter {
+	case ExporterOTLP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(c.Endpoint)}
+		if c.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exp, err := otlptracehttp.New(context.Background(), opts...)
+		if err != nil {
+			return nil, fmt.Errorf("tracing: %w", err)
+		}
+		exporter = exp
+		log.Printf("[INFO] (tracing) exporting spans to %s", c.Endpoint)
+	case ExporterFile:
+		if c.FilePath == "" {
+			return nil, fmt.Errorf("tracing: file_path is required for the %q exporter", ExporterFile)
+		}
+		f, err := os.OpenFile(c.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("tracing: %w", err)
+		}
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("tracing: %w", err)
+		}
+		t.file = f
+		exporter = exp
+		log.Printf("[INFO] (tracing) writing spans to %s", c.FilePath)
+	default:
+		return nil, fmt.Errorf("tracing: unknown exporter %q", c.Exporter)
+	}
+
+	t.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName(c.ServiceName),
+			semconv.ServiceVersion(version.Version+version.VersionPrerelease),
+		)),
+	)
+
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		log.Printf("[WARN] (tracing) %s", err)
+	}))
+	otel.SetTracerProvider(t.provider)
+
+	return t, nil
+}
+
+// Stop flushes any queued spans and replaces the global tracer provider with a
+// no-op so that further spans are discarded.
+func (t *Tracing) Stop() {
+	if t == nil {
+		return
+	}
+
+	otel.SetTracerProvider(noop.NewTracerProvider())
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := t.provider.Shutdown(ctx); err != nil {
+		log.Printf("[WARN] (tracing) error stopping tracer provider: %s", err)
+	}
+
+	if t.file != nil {
+		if err := t.file.Close(); err != nil {
+			log.Printf("[WARN] (tracing) error closing %s: %s", t.file.Name(), err)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright IBM Corp. 2014, 2025
+// SPDX-License-Identifier: MPL-2.0
+
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+)
+
+func TestSetup_file(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.json")
+
+	tr, err := Setup(&Config{
+		Exporter:    ExporterFile,
+		FilePath:    path,
+		ServiceName: "test-service",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, span := otel.Tracer("test").Start(context.Background(), "test.span")
+	span.End()
+	tr.Stop()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, exp := range []string{`"Name":"test.span"`, "test-service"} {
+		if !strings.Contains(string(b), exp) {
+			t.Errorf("expected trace output to contain %q, got:\n%s", exp, b)
+		}
+	}
+
+	// Spans started after Stop are discarded.
+	_, span = otel.Tracer("test").Start(context.Background(), "after.stop")
+	if span.SpanContext().IsValid() {
+		t.Errorf("expected a no-op span after Stop")
+	}
+	span.End()
+}
+
+func TestSetup_errors(t *testing.T) {
+	cases := []struct {
+		name string
+		c    *Config
+		err  string
+	}{
+		{
+			"unknown_exporter",
+			&Config{Exporter: "nope"},
+			`unknown exporter "nope"`,
+		},
+		{
+			"file_without_path",
+			&Config{Exporter: ExporterFile},
+			"file_path is required",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Setup(tc.c)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestStop_nil(t *testing.T) {
+	var tr *Tracing
+	tr.Stop()
+}
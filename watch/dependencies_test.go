@@ -37,6 +37,21 @@ func (d *TestDep) Type() dep.Type {
 	return dep.TypeLocal
 }
 
+// TestDepLease is a special dependency that holds a Vault lease without
+// speaking to a server.
+type TestDepLease struct {
+	TestDep
+	leaseID string
+}
+
+func (d *TestDepLease) LeaseID() string {
+	return d.leaseID
+}
+
+func (d *TestDepLease) String() string {
+	return fmt.Sprintf("test_dep_lease(%s)", d.name)
+}
+
 // TestDepStale is a special dependency that can be used to test what happens when
 // stale data is permitted.
 type TestDepStale struct {
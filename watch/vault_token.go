@@ -24,6 +24,10 @@ func VaultTokenWatcher(
 	// tokens are not being used.
 	raw_token := strings.TrimSpace(config.StringVal(c.Token))
 	if raw_token == "" {
+		// Without a token, the client may have logged in with an auth method.
+		if c.Auth != nil && config.StringVal(c.Auth.Method) != "" {
+			return vaultAuthWatcher(clients, c, doneCh)
+		}
 		return nil, nil
 	}
 
@@ -77,6 +81,46 @@ func VaultTokenWatcher(
 	return watcher, nil
 }
 
+// vaultAuthWatcher renews the token the client logged in with, and logs in
+// again once it can no longer be renewed.
+func vaultAuthWatcher(
+	clients *dep.ClientSet, c *config.VaultConfig, doneCh chan struct{},
+) (*Watcher, error) {
+	vault := clients.Vault()
+	if vault.Token() == "" || !config.BoolVal(c.RenewToken) {
+		return nil, nil
+	}
+
+	w := NewWatcher(&NewWatcherInput{
+		Clients:        clients,
+		RetryFuncVault: RetryFunc(c.Retry.RetryFunc()),
+	})
+	vt, err := dep.NewVaultTokenQuery(vault.Token())
+	if err != nil {
+		w.Stop()
+		return nil, fmt.Errorf("vaultwatcher: %w", err)
+	}
+	vt.EnableLogin()
+	if _, err := w.Add(vt); err != nil {
+		w.Stop()
+		return nil, fmt.Errorf("vaultwatcher: %w", err)
+	}
+
+	go func() {
+		for {
+			select {
+			case <-w.DataCh():
+				log.Printf("[INFO] (vaultwatcher) logged in to vault with %s auth",
+					config.StringVal(c.Auth.Method))
+			case <-doneCh:
+				return
+			}
+		}
+	}()
+
+	return w, nil
+}
+
 func watchTokenFile(
 	w *Watcher, tokenFile, raw_token string, unwrap bool, doneCh chan struct{},
 ) (func(), error) {
@@ -4,17 +4,28 @@
 package watch
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
 	"math/rand"
 	"reflect"
+	"strings"
 	"sync"
 	"time"
 
 	dep "github.com/hashicorp/consul-template/dependency"
+	"github.com/hashicorp/consul-template/logging"
+	metrics "github.com/hashicorp/go-metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// tracerName is the instrumentation scope for spans emitted by this package.
+const tracerName = "github.com/hashicorp/consul-template/watch"
+
 var errLookup = fmt.Errorf("lookup error")
 
 // View is a representation of a Dependency and the most recent data it has
@@ -34,6 +45,17 @@ type View struct {
 	receivedData bool
 	lastIndex    uint64
 
+	// lastFetch is the last time the upstream returned a successful response
+	// and lastErr is the most recent fetch error, cleared on success. Both are
+	// protected by dataLock and are only used for reporting.
+	lastFetch time.Time
+	lastErr   error
+
+	// templateIDs are the IDs of the templates that use the dependency, which
+	// tie the fetch spans to the render spans of those templates. They are
+	// protected by dataLock.
+	templateIDs []string
+
 	// blockQueryWaitTime is amount of time in seconds to do a blocking query for
 	blockQueryWaitTime time.Duration
 
@@ -80,6 +102,9 @@ type NewViewInput struct {
 	// RetryFunc is a function which dictates how this view should retry on
 	// upstream errors.
 	RetryFunc RetryFunc
+
+	// TemplateIDs are the IDs of the templates that use the dependency.
+	TemplateIDs []string
 }
 
 // NewView constructs a new view with the given inputs.
@@ -92,6 +117,7 @@ func NewView(i *NewViewInput) (*View, error) {
 		once:               i.Once,
 		failLookupErrors:   i.FailLookupErrors,
 		retryFunc:          i.RetryFunc,
+		templateIDs:        i.TemplateIDs,
 		stopCh:             make(chan struct{}, 1),
 	}, nil
 }
@@ -101,6 +127,20 @@ func (v *View) Dependency() dep.Dependency {
 	return v.dependency
 }
 
+// TemplateIDs returns the IDs of the templates that use the dependency.
+func (v *View) TemplateIDs() []string {
+	v.dataLock.RLock()
+	defer v.dataLock.RUnlock()
+	return v.templateIDs
+}
+
+// setTemplateIDs sets the IDs of the templates that use the dependency.
+func (v *View) setTemplateIDs(ids []string) {
+	v.dataLock.Lock()
+	defer v.dataLock.Unlock()
+	v.templateIDs = ids
+}
+
 // Data returns the most-recently-received data from Consul for this View.
 func (v *View) Data() interface{} {
 	v.dataLock.RLock()
@@ -117,6 +157,40 @@ func (v *View) DataAndLastIndex() (interface{}, uint64) {
 	return v.data, v.lastIndex
 }
 
+// ViewStatus is a point-in-time snapshot of the state of a View, used for
+// reporting.
+type ViewStatus struct {
+	// Dependency is the dependency associated with the View.
+	Dependency dep.Dependency
+
+	// ReceivedData is true once the View has received data at least once.
+	ReceivedData bool
+
+	// LastIndex is the index of the most recently received data.
+	LastIndex uint64
+
+	// LastFetch is the last time the upstream returned a successful response.
+	// It is the zero value if no response has been received.
+	LastFetch time.Time
+
+	// LastError is the most recent error returned by the upstream. It is reset
+	// once a fetch succeeds.
+	LastError error
+}
+
+// Status returns a snapshot of the current state of this View.
+func (v *View) Status() *ViewStatus {
+	v.dataLock.RLock()
+	defer v.dataLock.RUnlock()
+	return &ViewStatus{
+		Dependency:   v.dependency,
+		ReceivedData: v.receivedData,
+		LastIndex:    v.lastIndex,
+		LastFetch:    v.lastFetch,
+		LastError:    v.lastErr,
+	}
+}
+
 // poll queries the Consul instance for data using the fetch function, but also
 // accounts for interrupts on the interrupt channel. This allows the poll
 // function to be fired in a goroutine, but then halted even if the fetch
@@ -137,7 +211,7 @@ func (v *View) poll(viewCh chan<- *View, errCh chan<- error, serverErrCh chan<-
 			// have some successful requests
 			retries = 0
 
-			log.Printf("[TRACE] (view) %s received data", v.dependency)
+			log.Printf("[TRACE] (view) %s received data%s", v.dependency, v.logFields())
 			select {
 			case <-v.stopCh:
 				return
@@ -156,7 +230,7 @@ func (v *View) poll(viewCh chan<- *View, errCh chan<- error, serverErrCh chan<-
 			// example, Consul make have an outage, but when it returns, the view
 			// is unchanged. We have to reset the counter retries, but not update the
 			// actual template.
-			log.Printf("[TRACE] (view) %s successful contact, resetting retries", v.dependency)
+			log.Printf("[TRACE] (view) %s successful contact, resetting retries%s", v.dependency, v.logFields())
 			retries = 0
 			goto WAIT
 		case err := <-fetchErrCh:
@@ -164,8 +238,10 @@ func (v *View) poll(viewCh chan<- *View, errCh chan<- error, serverErrCh chan<-
 				retry, sleep := v.retryFunc(retries)
 				serverErrCh <- err
 				if retry {
-					log.Printf("[WARN] (view) %s (retry attempt %d after %q)",
-						err, retries+1, sleep)
+					log.Printf("[WARN] (view) %s (retry attempt %d after %q)%s",
+						err, retries+1, sleep, v.logFields())
+					metrics.IncrCounterWithLabels([]string{"watch", "view", "retry"}, 1,
+						v.metricLabels())
 					select {
 					case <-time.After(sleep):
 						retries++
@@ -174,7 +250,7 @@ func (v *View) poll(viewCh chan<- *View, errCh chan<- error, serverErrCh chan<-
 						return
 					}
 				}
-				log.Printf("[ERR] (view) %s (exceeded maximum retries)", err)
+				log.Printf("[ERR] (view) %s (exceeded maximum retries)%s", err, v.logFields())
 			}
 
 			// Push the error back up to the watcher
@@ -185,7 +261,7 @@ func (v *View) poll(viewCh chan<- *View, errCh chan<- error, serverErrCh chan<-
 				return
 			}
 		case <-v.stopCh:
-			log.Printf("[TRACE] (view) %s stopping poll (received on view stopCh)", v.dependency)
+			log.Printf("[TRACE] (view) %s stopping poll (received on view stopCh)%s", v.dependency, v.logFields())
 			return
 		}
 	}
@@ -197,7 +273,7 @@ func (v *View) poll(viewCh chan<- *View, errCh chan<- error, serverErrCh chan<-
 // result of doneCh and errCh. It is assumed that only one instance of fetch
 // is running per View and therefore no locking or mutexes are used.
 func (v *View) fetch(doneCh, successCh chan<- struct{}, errCh chan<- error) {
-	log.Printf("[TRACE] (view) %s starting fetch", v.dependency)
+	log.Printf("[TRACE] (view) %s starting fetch%s", v.dependency, v.logFields())
 
 	var allowStale bool
 	if v.maxStale != 0 {
@@ -216,19 +292,46 @@ func (v *View) fetch(doneCh, successCh chan<- struct{}, errCh chan<- error) {
 
 		start := time.Now() // for rateLimiter below
 
+		_, span := otel.Tracer(tracerName).Start(context.Background(), "dependency.fetch",
+			trace.WithAttributes(
+				attribute.String("dependency", v.dependency.String()),
+				attribute.String("dependency.type", v.kind()),
+				attribute.Int64("wait_index", int64(v.lastIndex)),
+				attribute.Bool("allow_stale", allowStale),
+			))
 		data, rm, err := v.dependency.Fetch(v.clients, &dep.QueryOptions{
 			AllowStale: allowStale,
 			WaitTime:   v.blockQueryWaitTime,
 			WaitIndex:  v.lastIndex,
 		})
+		// The templates using the dependency can change while blocking, so
+		// they are read once the fetch returns.
+		if templateIDs := v.TemplateIDs(); len(templateIDs) > 0 {
+			span.SetAttributes(attribute.StringSlice("template.ids", templateIDs))
+		}
 		if err != nil {
 			if err == dep.ErrStopped {
-				log.Printf("[TRACE] (view) %s reported stop", v.dependency)
+				log.Printf("[TRACE] (view) %s reported stop%s", v.dependency, v.logFields())
+				span.End()
 			} else {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				span.End()
+				metrics.IncrCounterWithLabels([]string{"watch", "view", "fetch_error"}, 1,
+					v.metricLabels())
+				v.dataLock.Lock()
+				v.lastErr = err
+				v.dataLock.Unlock()
 				errCh <- err
 			}
 			return
 		}
+		metrics.MeasureSinceWithLabels([]string{"watch", "view", "fetch"}, start,
+			v.metricLabels())
+		if rm != nil {
+			span.SetAttributes(attribute.Int64("last_index", int64(rm.LastIndex)))
+		}
+		span.End()
 
 		if rm == nil {
 			errCh <- fmt.Errorf("received nil response metadata - this is a bug " +
@@ -236,10 +339,15 @@ func (v *View) fetch(doneCh, successCh chan<- struct{}, errCh chan<- error) {
 			return
 		}
 
+		v.dataLock.Lock()
+		v.lastFetch = time.Now().UTC()
+		v.lastErr = nil
+		v.dataLock.Unlock()
+
 		// If we got this far, we received data successfully. That data might not
 		// trigger a data update (because we could continue below), but we need to
 		// inform the poller to reset the retry count.
-		log.Printf("[TRACE] (view) %s marking successful data response", v.dependency)
+		log.Printf("[TRACE] (view) %s marking successful data response%s", v.dependency, v.logFields())
 		select {
 		case successCh <- struct{}{}:
 		default:
@@ -247,7 +355,7 @@ func (v *View) fetch(doneCh, successCh chan<- struct{}, errCh chan<- error) {
 
 		if allowStale && rm.LastContact > v.maxStale {
 			allowStale = false
-			log.Printf("[TRACE] (view) %s stale data (last contact exceeded max_stale)", v.dependency)
+			log.Printf("[TRACE] (view) %s stale data (last contact exceeded max_stale)%s", v.dependency, v.logFields())
 			continue
 		}
 
@@ -268,13 +376,13 @@ func (v *View) fetch(doneCh, successCh chan<- struct{}, errCh chan<- error) {
 		// blocking queries that return due to block timeout
 		// will have the same index
 		if rm.LastIndex == v.lastIndex {
-			log.Printf("[TRACE] (view) %s no new data (index was the same)", v.dependency)
+			log.Printf("[TRACE] (view) %s no new data (index was the same)%s", v.dependency, v.logFields())
 			continue
 		}
 
 		v.dataLock.Lock()
 		if rm.LastIndex < v.lastIndex {
-			log.Printf("[TRACE] (view) %s had a lower index, resetting", v.dependency)
+			log.Printf("[TRACE] (view) %s had a lower index, resetting%s", v.dependency, v.logFields())
 			v.lastIndex = 0
 			v.dataLock.Unlock()
 			continue
@@ -282,7 +390,7 @@ func (v *View) fetch(doneCh, successCh chan<- struct{}, errCh chan<- error) {
 		v.lastIndex = rm.LastIndex
 
 		if v.receivedData && reflect.DeepEqual(data, v.data) {
-			log.Printf("[TRACE] (view) %s no new data (contents were the same)", v.dependency)
+			log.Printf("[TRACE] (view) %s no new data (contents were the same)%s", v.dependency, v.logFields())
 			v.dataLock.Unlock()
 			continue
 		}
@@ -291,7 +399,7 @@ func (v *View) fetch(doneCh, successCh chan<- struct{}, errCh chan<- error) {
 		// lookup failures, but you want the dependency to act like it is still
 		// blocking and loop back and hit it again.
 		if data == nil && rm.BlockOnNil {
-			log.Printf("[TRACE] (view) %s asked for blocking query", v.dependency)
+			log.Printf("[TRACE] (view) %s asked for blocking query%s", v.dependency, v.logFields())
 			v.dataLock.Unlock()
 			continue
 		}
@@ -317,6 +425,28 @@ func rateLimiter(start time.Time) time.Duration {
 	return 0
 }
 
+// metricLabels returns the labels attached to metrics emitted for this view.
+// Only the kind of dependency (e.g. "health.service") is used, since the full
+// dependency string would produce an unbounded number of series.
+func (v *View) metricLabels() []metrics.Label {
+	return []metrics.Label{{Name: "type", Value: v.kind()}}
+}
+
+// kind returns the kind of dependency (e.g. "health.service") this view is
+// watching, without its arguments.
+func (v *View) kind() string {
+	kind := v.dependency.String()
+	if i := strings.IndexByte(kind, '('); i >= 0 {
+		kind = kind[:i]
+	}
+	return kind
+}
+
+// logFields returns the structured log fields identifying this view.
+func (v *View) logFields() string {
+	return logging.Fields("dependency", v.dependency.String())
+}
+
 // stop halts polling of this view.
 func (v *View) stop() {
 	v.dependency.Stop()
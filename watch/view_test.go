@@ -8,6 +8,11 @@ import (
 	"reflect"
 	"testing"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestPoll_returnsViewCh(t *testing.T) {
@@ -275,6 +280,48 @@ func TestFetch_savesView(t *testing.T) {
 	}
 }
 
+func TestFetch_spanTemplateIDs(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	defer otel.SetTracerProvider(prev)
+
+	view, err := NewView(&NewViewInput{
+		Dependency:  &TestDep{},
+		TemplateIDs: []string{"aabb", "ccdd"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doneCh := make(chan struct{})
+	successCh := make(chan struct{})
+	errCh := make(chan error)
+
+	go view.fetch(doneCh, successCh, errCh)
+
+	select {
+	case <-doneCh:
+	case err := <-errCh:
+		t.Fatalf("error while fetching: %s", err)
+	}
+
+	spans := sr.Ended()
+	if len(spans) != 1 || spans[0].Name() != "dependency.fetch" {
+		t.Fatalf("expected a dependency.fetch span, got %v", spans)
+	}
+	exp := attribute.StringSlice("template.ids", []string{"aabb", "ccdd"})
+	for _, kv := range spans[0].Attributes() {
+		if kv.Key == exp.Key {
+			if !reflect.DeepEqual(kv, exp) {
+				t.Errorf("expected %v to be %v", kv, exp)
+			}
+			return
+		}
+	}
+	t.Errorf("expected span to have %s attribute", exp.Key)
+}
+
 func TestFetch_returnsErrCh(t *testing.T) {
 	view, err := NewView(&NewViewInput{
 		Dependency: &TestDepFetchError{},
@@ -300,6 +347,69 @@ func TestFetch_returnsErrCh(t *testing.T) {
 	}
 }
 
+func TestStatus_afterFetch(t *testing.T) {
+	view, err := NewView(&NewViewInput{
+		Dependency: &TestDep{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := view.Status(); s.ReceivedData || !s.LastFetch.IsZero() {
+		t.Fatalf("expected empty status, got %#v", s)
+	}
+
+	doneCh := make(chan struct{})
+	successCh := make(chan struct{}, 1)
+	errCh := make(chan error)
+
+	go view.fetch(doneCh, successCh, errCh)
+
+	select {
+	case <-doneCh:
+	case err := <-errCh:
+		t.Fatalf("error while fetching: %s", err)
+	}
+
+	s := view.Status()
+	if !s.ReceivedData {
+		t.Errorf("expected ReceivedData to be true")
+	}
+	if s.LastIndex != 1 {
+		t.Errorf("expected %d to be %d", s.LastIndex, 1)
+	}
+	if s.LastFetch.IsZero() {
+		t.Errorf("expected LastFetch to be set")
+	}
+	if s.LastError != nil {
+		t.Errorf("expected no error, got %s", s.LastError)
+	}
+}
+
+func TestStatus_afterFetchError(t *testing.T) {
+	view, err := NewView(&NewViewInput{
+		Dependency: &TestDepFetchError{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doneCh := make(chan struct{})
+	successCh := make(chan struct{})
+	errCh := make(chan error)
+
+	go view.fetch(doneCh, successCh, errCh)
+	<-errCh
+
+	s := view.Status()
+	if s.LastError == nil || s.LastError.Error() != "failed to contact server" {
+		t.Errorf("expected fetch error, got %v", s.LastError)
+	}
+	if !s.LastFetch.IsZero() {
+		t.Errorf("expected LastFetch to be unset")
+	}
+}
+
 func TestStop_stopsPolling(t *testing.T) {
 	view, err := NewView(&NewViewInput{
 		Dependency: &TestDep{},
@@ -4,17 +4,24 @@
 package watch
 
 import (
+	"context"
 	"log"
+	"sort"
 	"sync"
 	"time"
 
 	dep "github.com/hashicorp/consul-template/dependency"
+	"github.com/hashicorp/consul-template/logging"
 	"github.com/pkg/errors"
 )
 
 // dataBufferSize is the default number of views to process in a batch.
 const dataBufferSize = 2048
 
+// VaultRevokeTimeout is the maximum amount of time to spend revoking Vault
+// leases when the watcher is stopped.
+var VaultRevokeTimeout = 10 * time.Second
+
 type RetryFunc func(int) (bool, time.Duration)
 
 // Watcher is a top-level manager for views that poll Consul for data.
@@ -146,7 +153,10 @@ func (w *Watcher) ServerErrCh() <-chan error {
 // will return true. If an error occurs while creating the view, it will be
 // returned here (but future errors returned by the view will happen on the
 // channel).
-func (w *Watcher) Add(d dep.Dependency) (bool, error) {
+//
+// The templateIDs are the IDs of the templates that use the dependency, which
+// are added to its fetch spans until they are replaced by SetTemplateIDs.
+func (w *Watcher) Add(d dep.Dependency, templateIDs ...string) (bool, error) {
 	w.Lock()
 	defer w.Unlock()
 	if w.stopped {
@@ -154,7 +164,8 @@ func (w *Watcher) Add(d dep.Dependency) (bool, error) {
 		return false, nil
 	}
 
-	log.Printf("[DEBUG] (watcher) adding %s", d)
+	log.Printf("[DEBUG] (watcher) adding %s%s", d,
+		logging.Fields("dependency", d.String()))
 
 	if _, ok := w.depViewMap[d.String()]; ok {
 		log.Printf("[TRACE] (watcher) %s already exists, skipping", d)
@@ -182,6 +193,7 @@ func (w *Watcher) Add(d dep.Dependency) (bool, error) {
 		FailLookupErrors:   w.failLookupErrors,
 		Once:               w.once,
 		RetryFunc:          retryFunc,
+		TemplateIDs:        append([]string(nil), templateIDs...),
 	})
 	if err != nil {
 		return false, errors.Wrap(err, "watcher")
@@ -225,7 +237,8 @@ func (w *Watcher) Remove(d dep.Dependency) bool {
 	w.Lock()
 	defer w.Unlock()
 
-	log.Printf("[DEBUG] (watcher) removing %s", d)
+	log.Printf("[DEBUG] (watcher) removing %s%s", d,
+		logging.Fields("dependency", d.String()))
 
 	if view, ok := w.depViewMap[d.String()]; ok {
 		log.Printf("[TRACE] (watcher) actually removing %s", d)
@@ -238,6 +251,47 @@ func (w *Watcher) Remove(d dep.Dependency) bool {
 	return false
 }
 
+// SetTemplateIDs sets the IDs of the templates that use each watched
+// dependency, keyed by the string form of the dependency. They are added to
+// the fetch spans of the dependency.
+func (w *Watcher) SetTemplateIDs(ids map[string][]string) {
+	w.Lock()
+	defer w.Unlock()
+
+	for key, view := range w.depViewMap {
+		view.setTemplateIDs(ids[key])
+	}
+}
+
+// RevokeLeases revokes the leases of the secrets of the given dependencies,
+// which are the ones returned by Stop. Failures are logged, and revoking gives
+// up after VaultRevokeTimeout.
+func (w *Watcher) RevokeLeases(leasers []dep.VaultLeaser) {
+	if w == nil || len(leasers) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), VaultRevokeTimeout)
+	defer cancel()
+
+	log.Printf("[DEBUG] (watcher) revoking vault leases")
+
+	for _, l := range leasers {
+		leaseID := l.LeaseID()
+		if leaseID == "" {
+			continue
+		}
+		if err := w.clients.Vault().Sys().RevokeWithContext(ctx, leaseID); err != nil {
+			log.Printf("[WARN] (watcher) %s: failed to revoke lease %s: %s", l, leaseID, err)
+			continue
+		}
+		log.Printf("[DEBUG] (watcher) %s: revoked lease %s", l, leaseID)
+	}
+
+	// Close the TCP connections used for revoking
+	w.clients.Stop()
+}
+
 // Size returns the number of views this watcher is watching.
 func (w *Watcher) Size() int {
 	w.Lock()
@@ -245,23 +299,52 @@ func (w *Watcher) Size() int {
 	return len(w.depViewMap)
 }
 
+// Status returns a snapshot of the state of every view this watcher is
+// watching, sorted by dependency.
+func (w *Watcher) Status() []*ViewStatus {
+	if w == nil {
+		return nil
+	}
+	w.Lock()
+	defer w.Unlock()
+
+	status := make([]*ViewStatus, 0, len(w.depViewMap))
+	for _, view := range w.depViewMap {
+		if view == nil {
+			continue
+		}
+		status = append(status, view.Status())
+	}
+	sort.Slice(status, func(i, j int) bool {
+		return status[i].Dependency.String() < status[j].Dependency.String()
+	})
+	return status
+}
+
 // Stop halts this watcher and any currently polling views immediately. If a
-// view was in the middle of a poll, no data will be returned.
-func (w *Watcher) Stop() {
+// view was in the middle of a poll, no data will be returned. It returns the
+// stopped dependencies that hold Vault leases, so their leases can be revoked
+// with RevokeLeases.
+func (w *Watcher) Stop() []dep.VaultLeaser {
 	if w == nil {
-		return
+		return nil
 	}
 	w.Lock()
 	defer w.Unlock()
 
 	log.Printf("[DEBUG] (watcher) stopping all views")
 
+	var leasers []dep.VaultLeaser
 	for _, view := range w.depViewMap {
 		if view == nil {
 			continue
 		}
 		log.Printf("[TRACE] (watcher) stopping %s", view.Dependency())
 		view.stop()
+
+		if l, ok := view.Dependency().(dep.VaultLeaser); ok {
+			leasers = append(leasers, l)
+		}
 	}
 
 	// Reset the map to have no views
@@ -270,4 +353,6 @@ func (w *Watcher) Stop() {
 	// Close any idle TCP connections
 	w.clients.Stop()
 	w.stopped = true
+
+	return leasers
 }
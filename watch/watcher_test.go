@@ -4,7 +4,13 @@
 package watch
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"sync"
 	"testing"
 
 	dep "github.com/hashicorp/consul-template/dependency"
@@ -181,3 +187,124 @@ func TestSize_returnsNumViews(t *testing.T) {
 		t.Errorf("expected %d to be %d", w.Size(), 10)
 	}
 }
+
+func TestSetTemplateIDs(t *testing.T) {
+	w := NewWatcher(&NewWatcherInput{
+		Clients: dep.NewClientSet(),
+		Once:    true,
+	})
+
+	a, b := &TestDep{name: "a"}, &TestDep{name: "b"}
+	for _, d := range []dep.Dependency{a, b} {
+		if _, err := w.Add(d, "eeff"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if ids := w.depViewMap[a.String()].TemplateIDs(); !reflect.DeepEqual(ids, []string{"eeff"}) {
+		t.Errorf("expected %v to be %v", ids, []string{"eeff"})
+	}
+
+	w.SetTemplateIDs(map[string][]string{a.String(): {"aabb"}})
+
+	if ids := w.depViewMap[a.String()].TemplateIDs(); !reflect.DeepEqual(ids, []string{"aabb"}) {
+		t.Errorf("expected %v to be %v", ids, []string{"aabb"})
+	}
+	if ids := w.depViewMap[b.String()].TemplateIDs(); ids != nil {
+		t.Errorf("expected no template IDs, got %v", ids)
+	}
+}
+
+func TestStatus_sorted(t *testing.T) {
+	w := NewWatcher(&NewWatcherInput{
+		Clients: dep.NewClientSet(),
+		Once:    true,
+	})
+	defer w.Stop()
+
+	for _, name := range []string{"b", "a", "c"} {
+		if _, err := w.Add(&TestDep{name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	w.ForceWatching(&TestDep{name: "nil"}, true)
+
+	status := w.Status()
+	act := make([]string, 0, len(status))
+	for _, s := range status {
+		act = append(act, s.Dependency.String())
+	}
+
+	exp := []string{"test_dep(a)", "test_dep(b)", "test_dep(c)"}
+	if !reflect.DeepEqual(exp, act) {
+		t.Errorf("\nexp: %#v\nact: %#v", exp, act)
+	}
+}
+
+func TestRevokeLeases(t *testing.T) {
+	var lock sync.Mutex
+	var revoked []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/sys/leases/revoke" {
+			http.NotFound(w, r)
+			return
+		}
+		var body struct {
+			LeaseID string `json:"lease_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		lock.Lock()
+		revoked = append(revoked, body.LeaseID)
+		lock.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	newWatcher := func() *Watcher {
+		clients := dep.NewClientSet()
+		if err := clients.CreateVaultClient(&dep.CreateVaultClientInput{
+			Address: ts.URL,
+			Token:   "token",
+		}); err != nil {
+			t.Fatal(err)
+		}
+		w := NewWatcher(&NewWatcherInput{
+			Clients: clients,
+			Once:    true,
+		})
+		for _, d := range []dep.Dependency{
+			&TestDepLease{TestDep: TestDep{name: "a"}, leaseID: "database/creds/a/1"},
+			&TestDepLease{TestDep: TestDep{name: "b"}, leaseID: "database/creds/b/2"},
+			&TestDepLease{TestDep: TestDep{name: "kv"}},
+			&TestDep{name: "c"},
+		} {
+			if _, err := w.Add(d); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return w
+	}
+
+	w := newWatcher()
+	leasers := w.Stop()
+	if len(leasers) != 3 {
+		t.Fatalf("expected 3 leasers, got %d", len(leasers))
+	}
+
+	t.Run("stop", func(t *testing.T) {
+		if len(revoked) != 0 {
+			t.Errorf("expected no revoked leases, got %v", revoked)
+		}
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		w.RevokeLeases(leasers)
+
+		sort.Strings(revoked)
+		exp := []string{"database/creds/a/1", "database/creds/b/2"}
+		if !reflect.DeepEqual(exp, revoked) {
+			t.Errorf("\nexp: %#v\nact: %#v", exp, revoked)
+		}
+	})
+}